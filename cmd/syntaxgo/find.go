@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_astnode"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+// findResult is the output of the find commands.
+// findResult 是 find 命令的输出。
+type findResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Receiver  string `json:"receiver,omitempty"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	Code      string `json:"code"`
+}
+
+func runFindFunc(env *environment, flags *commandFlags, args []string) error {
+	return findNode(env, flags, args, "func", func(astFile *ast.File, name string) (ast.Node, string, bool) {
+		for _, decl := range astFile.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == name {
+				return funcDecl, "", true
+			}
+		}
+		return nil, "", false
+	})
+}
+
+func runFindType(env *environment, flags *commandFlags, args []string) error {
+	return findNode(env, flags, args, "type", func(astFile *ast.File, name string) (ast.Node, string, bool) {
+		for _, decl := range astFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == name {
+					// The whole declaration is the code of a type declared alone, so "type" is included.
+					// 单独声明的类型以整个声明作为代码，从而包含 "type"。
+					if !genDecl.Lparen.IsValid() {
+						return genDecl, "", true
+					}
+					return typeSpec, "", true
+				}
+			}
+		}
+		return nil, "", false
+	})
+}
+
+// runFindMethod finds the method named like "Recv.Name", the receiver is the type name without the pointer.
+// runFindMethod 查找形如 "Recv.Name" 的方法，接收者是不带指针的类型名称。
+func runFindMethod(env *environment, flags *commandFlags, args []string) error {
+	return findNode(env, flags, args, "method", func(astFile *ast.File, name string) (ast.Node, string, bool) {
+		receiverName, methodName, ok := strings.Cut(name, ".")
+		if !ok {
+			return nil, "", false
+		}
+		funcDecl, ok := syntaxgo_search.FindFunctionByReceiverAndName(astFile, receiverName, methodName)
+		return funcDecl, receiverName, ok
+	})
+}
+
+// findNode finds the node by the name in the second argument, and prints its position and code.
+// findNode 根据第二个参数中的名称查找节点，并打印其位置和代码。
+func findNode(env *environment, flags *commandFlags, args []string, kind string, find func(astFile *ast.File, name string) (ast.Node, string, bool)) error {
+	path, rest, err := fileArg(args, 1)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	in, err := readInput(env, path)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	astFile, fset := in.astBundle.GetBundle()
+	node, receiver, ok := find(astFile, rest[0])
+	if !ok {
+		return erero.Errorf("no %s named %s in %s", kind, rest[0], in.name())
+	}
+	name := rest[0]
+	if receiver != "" {
+		name = strings.TrimPrefix(name, receiver+".")
+	}
+	start, end := fset.Position(node.Pos()), fset.Position(node.End())
+	result := &findResult{
+		Kind:      kind,
+		Name:      name,
+		Receiver:  receiver,
+		Path:      in.name(),
+		Line:      start.Line,
+		Column:    start.Column,
+		EndLine:   end.Line,
+		EndColumn: end.Column,
+		Code:      syntaxgo_astnode.GetText(in.source, node),
+	}
+	if flags.json {
+		return writeJSON(env, result)
+	}
+	if _, err := fmt.Fprintf(env.stdout, "%s:%d:%d\n%s\n", result.Path, result.Line, result.Column, result.Code); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFindFunc(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "find", "func", "-", "main")
+	require.Equal(t, 0, code)
+	require.Equal(t, "<stdin>:15:1\nfunc main() { fmt.Println(x, y) }\n", stdout)
+
+	_, stderr, code := runCommand(demoSource, "find", "func", "-", "Save")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "no func named Save in <stdin>")
+}
+
+func TestRunFindType(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "find", "type", "-", "Account")
+	require.Equal(t, 0, code)
+	require.Equal(t, "<stdin>:6:1\ntype Account struct {\n\tName string `json:\"name\"`\n\tID   int\n}\n", stdout)
+}
+
+func TestRunFindMethod(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "find", "method", "-json", "-", "Account.Save")
+	require.Equal(t, 0, code)
+
+	var result findResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	require.Equal(t, findResult{
+		Kind:      "method",
+		Name:      "Save",
+		Receiver:  "Account",
+		Path:      "<stdin>",
+		Line:      11,
+		Column:    1,
+		EndLine:   11,
+		EndColumn: 67,
+		Code:      "func (a *Account) Save() string { return strings.ToUpper(a.Name) }",
+	}, result)
+
+	_, _, code = runCommand(demoSource, "find", "method", "-", "Save")
+	require.Equal(t, 1, code)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+func runImportsAdd(env *environment, flags *commandFlags, args []string) error {
+	if len(args) < 2 || (flags.name != "" && len(args) != 2) {
+		return errUsage
+	}
+	in, err := readInput(env, args[0])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var notes []string
+	for _, path := range args[1:] {
+		var added bool
+		if flags.name != "" {
+			added = in.astBundle.AddNamedImport(flags.name, path)
+		} else {
+			added = in.astBundle.AddImport(path)
+		}
+		if added {
+			notes = append(notes, fmt.Sprintf("added import %q", path))
+		}
+	}
+	return writeChange(env, flags, in, notes)
+}
+
+func runImportsRemove(env *environment, flags *commandFlags, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	in, err := readInput(env, args[0])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var notes []string
+	for _, path := range args[1:] {
+		astFile, _ := in.astBundle.GetBundle()
+		var names []string
+		for _, importSpec := range astFile.Imports {
+			if syntaxgo_search.GetImportPath(importSpec) == path {
+				if importSpec.Name != nil {
+					names = append(names, importSpec.Name.Name)
+				} else {
+					names = append(names, "")
+				}
+			}
+		}
+		for _, name := range names {
+			var deleted bool
+			if name != "" {
+				deleted = in.astBundle.DeleteNamedImport(name, path)
+			} else {
+				deleted = in.astBundle.DeleteImport(path)
+			}
+			if deleted {
+				notes = append(notes, fmt.Sprintf("removed import %q", path))
+			}
+		}
+	}
+	return writeChange(env, flags, in, notes)
+}
+
+// runImportsFix adds the standard library imports of the unresolved package qualifiers, then removes the unused imports.
+// A qualifier matching several standard library packages, like "rand", is reported and left unresolved.
+// runImportsFix 为未解析的包限定符添加标准库导入，然后删除未使用的导入。
+// 匹配多个标准库包的限定符（比如 "rand"）会被报告并保持未解析。
+func runImportsFix(env *environment, flags *commandFlags, args []string) error {
+	path, _, err := fileArg(args, 0)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	in, err := readInput(env, path)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var notes []string
+	astFile, _ := in.astBundle.GetBundle()
+	declared, err := siblingDeclaredNames(in, astFile.Name.Name)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	if qualifiers := missingQualifiers(astFile, declared); len(qualifiers) > 0 {
+		packages, err := listStandardPackages()
+		if err != nil {
+			return erero.Wro(err)
+		}
+		for _, qualifier := range qualifiers {
+			switch paths := packages[qualifier]; len(paths) {
+			case 0:
+				notes = append(notes, fmt.Sprintf("no standard library package for %q", qualifier))
+			case 1:
+				if in.astBundle.AddImport(paths[0]) {
+					notes = append(notes, fmt.Sprintf("added import %q", paths[0]))
+				}
+			default:
+				notes = append(notes, fmt.Sprintf("ambiguous standard library packages for %q: %s", qualifier, strings.Join(paths, ", ")))
+			}
+		}
+	}
+	for _, path := range in.astBundle.DeleteUnusedImports() {
+		notes = append(notes, fmt.Sprintf("removed import %q", path))
+	}
+	return writeChange(env, flags, in, notes)
+}
+
+// missingQualifiers returns the package qualifiers in the file that no import or declared name explains, in sorted order.
+// The import names are guessed by syntaxgo_search.GetImportName, like "rand" for "math/rand/v2".
+// missingQualifiers 按排序后的顺序返回文件中无法由任何导入或已声明名称解释的包限定符。
+// 导入名称由 syntaxgo_search.GetImportName 推测，比如 "math/rand/v2" 的包名是 "rand"。
+func missingQualifiers(astFile *ast.File, declared map[string]bool) []string {
+	imported := map[string]bool{}
+	for _, importSpec := range astFile.Imports {
+		imported[syntaxgo_search.GetImportName(importSpec)] = true
+	}
+	missing := map[string]bool{}
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if selectorExpr, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selectorExpr.X.(*ast.Ident); ok && ident.Obj == nil && !imported[ident.Name] && !declared[ident.Name] {
+				missing[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return sortedKeys(missing)
+}
+
+// siblingDeclaredNames returns the top-level names declared in the other files of the package in the directory of the input.
+// siblingDeclaredNames 返回输入所在目录中同一个包的其他文件声明的顶层名称。
+func siblingDeclaredNames(in *input, packageName string) (map[string]bool, error) {
+	declared := map[string]bool{}
+	if in.path == "-" {
+		return declared, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(in.path), "*.go"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, path := range paths {
+		if filepath.Clean(path) == filepath.Clean(in.path) {
+			continue
+		}
+		astFile, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if err != nil || astFile.Name.Name != packageName {
+			continue // Other packages and broken files explain nothing / 其他包以及无法解析的文件不解释任何名称
+		}
+		for _, decl := range astFile.Decls {
+			switch item := decl.(type) {
+			case *ast.FuncDecl:
+				if item.Recv == nil {
+					declared[item.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range item.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						declared[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							declared[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return declared, nil
+}
+
+// majorVersionRegexp matches the major version element of import paths, like "v2".
+// majorVersionRegexp 匹配导入路径中的主版本元素，比如 "v2"。
+var majorVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// listStandardPackages maps the package names to the import paths of the standard library in GOROOT.
+// Internal, vendor and command packages are skipped, and versioned paths like "math/rand/v2" are not candidates.
+// listStandardPackages 将包名映射到 GOROOT 中标准库的导入路径。
+// 跳过 internal、vendor 以及命令包，带版本的路径（比如 "math/rand/v2"）不作为候选。
+func listStandardPackages() (map[string][]string, error) {
+	root := filepath.Join(build.Default.GOROOT, "src")
+	packages := map[string][]string{}
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() || path == root {
+			return nil
+		}
+		name := entry.Name()
+		if name == "internal" || name == "vendor" || name == "testdata" || name == "cmd" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return filepath.SkipDir
+		}
+		if majorVersionRegexp.MatchString(name) || !hasGoFiles(path) {
+			return nil
+		}
+		importPath := filepath.ToSlash(path[len(root)+1:])
+		packages[name] = append(packages[name], importPath)
+		return nil
+	})
+	if err != nil {
+		return nil, erero.WithMessagef(err, "list the standard library in %s", root)
+	}
+	return packages, nil
+}
+
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+)
+
+func TestRunImportsAdd(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "imports", "add", "-name", "str", "-", "strings")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "import (\n\t\"os\"\n\tstr \"strings\"\n)\n")
+
+	_, _, code = runCommand(demoSource, "imports", "add", "-name", "str", "-", "strings", "bytes")
+	require.Equal(t, 2, code)
+}
+
+func TestRunImportsRemove(t *testing.T) {
+	source := "package demo\n\nimport (\n\t\"os\"\n\tx \"os\"\n\t\"strings\"\n)\n"
+	stdout, stderr, code := runCommand(source, "imports", "remove", "-", "os")
+	require.Equal(t, 0, code)
+	require.Equal(t, "package demo\n\nimport (\n\t\"strings\"\n)\n", stdout)
+	require.Equal(t, "removed import \"os\"\nremoved import \"os\"\n", stderr)
+}
+
+func TestRunImportsFix(t *testing.T) {
+	stdout, stderr, code := runCommand(demoSource, "imports", "fix", "-")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "import (\n\t\"fmt\"\n\t\"strings\"\n)\n")
+	require.Equal(t, "added import \"fmt\"\nadded import \"strings\"\nremoved import \"os\"\n", stderr)
+
+	_, stderr, code = runCommand("package demo\n\nvar n = rand.Int()\n", "imports", "fix", "-")
+	require.Equal(t, 0, code)
+	require.Contains(t, stderr, "ambiguous standard library packages for \"rand\": crypto/rand, math/rand\n")
+}
+
+func TestRunImportsFix_SiblingNames(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n\nvar n = json.Name\n"), 0644))
+	done.Done(os.WriteFile(filepath.Join(root, "b.go"), []byte("package demo\n\nvar json struct{ Name string }\n"), 0644))
+
+	stdout, stderr, code := runCommand("", "imports", "fix", path)
+	require.Equal(t, 0, code)
+	require.Equal(t, "package demo\n\nvar n = json.Name\n", stdout)
+	require.Empty(t, stderr)
+}
@@ -0,0 +1,326 @@
+/*
+Command `syntaxgo` exposes the syntaxgo operations on the command line, for scripts and non-Go users.
+
+Usage:
+
+	syntaxgo <command> [flags] [file] [args...]
+
+The file is a Go source file, "-" or no file reads the source from stdin. The commands are:
+
+	pkgname [path]                          print the package name of a file or a directory
+	imports add [-name N] file path...      add imports
+	imports remove file path...             remove imports
+	imports fix file                        add missing standard library imports and remove unused imports
+	tag get file Struct.Field [key]         print the tag of a field, or the value of a key
+	tag set file Struct.Field key value     set the value of a key, "gorm.column" sets a field in the value
+	tag add file Struct.Field key value     add a key that is not in the tag
+	tag remove file Struct.Field key        remove a key
+	find func|type|method file name         print the position and code, a method is named like "Recv.Name"
+	outline [file|dir]                      print the top-level declarations
+	print-ast [-style S] [-depth N] [file]  print the syntax tree as a tree or s-expressions
+
+The commands changing the source print the new source to stdout, -w writes it back to the file,
+-diff prints a unified diff instead. The -json flag prints the results as JSON, except for print-ast.
+*/
+
+/*
+Command `syntaxgo` 在命令行中提供 syntaxgo 的功能，供脚本以及不使用 Go 的用户使用。
+
+用法：
+
+	syntaxgo <command> [flags] [file] [args...]
+
+file 是 Go 源文件，"-" 或者不提供文件时从标准输入读取源代码。命令包括：
+
+	pkgname [path]                          打印文件或目录的包名
+	imports add [-name N] file path...      添加导入
+	imports remove file path...             删除导入
+	imports fix file                        添加缺失的标准库导入并删除未使用的导入
+	tag get file Struct.Field [key]         打印字段的标签，或者指定键的值
+	tag set file Struct.Field key value     设置键的值，"gorm.column" 设置值中的字段
+	tag add file Struct.Field key value     添加标签中不存在的键
+	tag remove file Struct.Field key        删除键
+	find func|type|method file name         打印位置和代码，方法的名称形如 "Recv.Name"
+	outline [file|dir]                      打印顶层声明
+	print-ast [-style S] [-depth N] [file]  以树形或 s 表达式打印语法树
+
+修改源代码的命令将新的源代码打印到标准输出，-w 将其写回文件，-diff 则打印统一差异。除 print-ast 之外，-json 以 JSON 格式打印结果。
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_diff"
+	"github.com/yyle88/zaplog"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// The library logs to stdout, which is the output of the commands, and the commands report the errors themselves.
+	// 库的日志输出到标准输出，而标准输出是命令的输出，并且命令会自行报告错误。
+	zaplog.SetLog(zap.NewNop())
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// environment is the input and output of the command, replaced in tests.
+// environment 是命令的输入和输出，在测试中会被替换。
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a subcommand, the name can have two words like "imports add".
+// command 是子命令，名称可以包含两个单词，比如 "imports add"。
+type command struct {
+	name   string
+	usage  string
+	modify bool // Whether the command changes the source / 命令是否修改源代码
+	noJSON bool // Whether the command has no JSON output / 命令是否没有 JSON 输出
+	run    func(env *environment, flags *commandFlags, args []string) error
+}
+
+// commandFlags are the flags shared by the commands.
+// commandFlags 是命令共享的标志。
+type commandFlags struct {
+	set   *flag.FlagSet
+	write bool   // Write the new source back to the file / 将新的源代码写回文件
+	diff  bool   // Print a unified diff instead of the new source / 打印统一差异而不是新的源代码
+	json  bool   // Print the results as JSON / 以 JSON 格式打印结果
+	name  string // Import name of "imports add" / "imports add" 的导入名称
+	style string // Style of "print-ast" / "print-ast" 的布局
+	depth int    // Depth limit of "print-ast" / "print-ast" 的深度限制
+}
+
+var commands = []*command{
+	{name: "pkgname", usage: "pkgname [path]", run: runPkgName},
+	{name: "imports add", usage: "imports add [-name N] file path...", modify: true, run: runImportsAdd},
+	{name: "imports remove", usage: "imports remove file path...", modify: true, run: runImportsRemove},
+	{name: "imports fix", usage: "imports fix file", modify: true, run: runImportsFix},
+	{name: "tag get", usage: "tag get file Struct.Field [key]", run: runTagGet},
+	{name: "tag set", usage: "tag set file Struct.Field key value", modify: true, run: runTagSet},
+	{name: "tag add", usage: "tag add file Struct.Field key value", modify: true, run: runTagAdd},
+	{name: "tag remove", usage: "tag remove file Struct.Field key", modify: true, run: runTagRemove},
+	{name: "find func", usage: "find func file name", run: runFindFunc},
+	{name: "find type", usage: "find type file name", run: runFindType},
+	{name: "find method", usage: "find method file Recv.Name", run: runFindMethod},
+	{name: "outline", usage: "outline [file|dir]", run: runOutline},
+	{name: "print-ast", usage: "print-ast [-style S] [-depth N] [file]", noJSON: true, run: runPrintAst},
+}
+
+// run runs the command line and returns the exit code, 2 for usage errors and 1 for other errors.
+// run 运行命令行并返回退出码，用法错误为 2，其他错误为 1。
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	env := &environment{stdin: stdin, stdout: stdout, stderr: stderr}
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		printUsage(stderr)
+		return 2
+	}
+	flags := &commandFlags{set: flag.NewFlagSet(cmd.name, flag.ContinueOnError)}
+	flags.set.SetOutput(stderr)
+	flags.set.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: syntaxgo %s\n", cmd.usage)
+		flags.set.PrintDefaults()
+	}
+	if cmd.modify {
+		flags.set.BoolVar(&flags.write, "w", false, "write the new source back to the file")
+		flags.set.BoolVar(&flags.diff, "diff", false, "print a unified diff instead of the new source")
+	}
+	if cmd.name == "imports add" {
+		flags.set.StringVar(&flags.name, "name", "", "import name, like \"_\" or an alias")
+	}
+	if cmd.name == "print-ast" {
+		flags.set.StringVar(&flags.style, "style", "tree", "layout of the syntax tree, tree or sexp")
+		flags.set.IntVar(&flags.depth, "depth", 0, "levels printed below the root, 0 prints all")
+	}
+	if !cmd.noJSON {
+		flags.set.BoolVar(&flags.json, "json", false, "print the results as JSON")
+	}
+	if err := flags.set.Parse(rest); err != nil {
+		return 2
+	}
+	if err := cmd.run(env, flags, flags.set.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			flags.set.Usage()
+			return 2
+		}
+		_, _ = fmt.Fprintf(stderr, "syntaxgo %s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// errUsage is returned by the commands when the arguments are wrong.
+// errUsage 在参数错误时由命令返回。
+var errUsage = errors.New("wrong arguments")
+
+// findCommand finds the command by the first one or two arguments.
+// findCommand 根据前一个或两个参数查找命令。
+func findCommand(args []string) (*command, []string) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):]
+		}
+	}
+	return nil, nil
+}
+
+func printUsage(writer io.Writer) {
+	_, _ = fmt.Fprintln(writer, "usage: syntaxgo <command> [flags] [file] [args...]")
+	_, _ = fmt.Fprintln(writer, "commands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(writer, "  %s\n", cmd.usage)
+	}
+	_, _ = fmt.Fprintln(writer, "the file \"-\" reads from stdin, -w writes in place, -diff prints a unified diff and -json prints JSON")
+}
+
+// input is the source code of the command, read from a file or stdin.
+// input 是命令的源代码，从文件或标准输入读取。
+type input struct {
+	path      string // File path, "-" for stdin / 文件路径，标准输入为 "-"
+	source    []byte
+	astBundle *syntaxgo_ast.AstBundle
+}
+
+// name returns the name of the input in the output, like in diffs and positions.
+// name 返回输入在输出中的名称，比如在差异和位置中。
+func (in *input) name() string {
+	if in.path == "-" {
+		return "<stdin>"
+	}
+	return in.path
+}
+
+// readInput reads and parses the file, "-" or an empty path reads from stdin.
+// readInput 读取并解析文件，"-" 或空路径从标准输入读取。
+func readInput(env *environment, path string) (*input, error) {
+	if path == "" || path == "-" {
+		source, err := io.ReadAll(env.stdin)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		astBundle, err := syntaxgo_ast.NewAstBundleV1(source)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		return &input{path: "-", source: source, astBundle: astBundle}, nil
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	// Parse the source already read, so the diff and the change compare against what was parsed.
+	// 解析已经读取的源代码，从而差异和变更比较的就是被解析的代码。
+	astBundle, err := syntaxgo_ast.NewAstBundleFromSource(token.NewFileSet(), path, source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return &input{path: path, source: source, astBundle: astBundle}, nil
+}
+
+// changeResult is the JSON output of the commands changing the source.
+// changeResult 是修改源代码的命令的 JSON 输出。
+type changeResult struct {
+	Path    string   `json:"path"`
+	Changed bool     `json:"changed"`
+	Written bool     `json:"written"`
+	Notes   []string `json:"notes,omitempty"`
+	Diff    string   `json:"diff,omitempty"`
+	Source  string   `json:"source,omitempty"`
+}
+
+// writeChange outputs the changed bundle of the input, as the new source, a diff, or writing the file.
+// writeChange 输出输入中被修改的 AST 包，即新的源代码、差异或写入文件。
+func writeChange(env *environment, flags *commandFlags, in *input, notes []string) error {
+	newSource, err := in.astBundle.FormatSource()
+	if err != nil {
+		return erero.Wro(err)
+	}
+	result := &changeResult{Path: in.name(), Changed: string(newSource) != string(in.source), Notes: notes}
+	if flags.write {
+		if in.path == "-" {
+			return erero.New("-w needs a file, the source is read from stdin")
+		}
+		if result.Changed {
+			if err := in.astBundle.Save(); err != nil {
+				return erero.Wro(err)
+			}
+			result.Written = true
+		}
+	}
+	if flags.diff {
+		// Name the versions like gofmt -d, which works for absolute paths too.
+		// 与 gofmt -d 一样命名两个版本，对于绝对路径同样适用。
+		result.Diff = syntaxgo_diff.NewOptions().SetNames(in.name()+".orig", in.name()).Compare(in.source, newSource).Unified()
+	} else if !flags.write {
+		result.Source = string(newSource)
+	}
+	if flags.json {
+		return writeJSON(env, result)
+	}
+	for _, note := range notes {
+		_, _ = fmt.Fprintln(env.stderr, note)
+	}
+	if flags.diff {
+		_, err = io.WriteString(env.stdout, result.Diff)
+	} else if !flags.write {
+		_, err = io.WriteString(env.stdout, result.Source)
+	}
+	if err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+func writeJSON(env *environment, value interface{}) error {
+	encoder := json.NewEncoder(env.stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+// fileArg splits the arguments into the file and the rest, the file is required when there are more arguments.
+// fileArg 将参数拆分为文件和其余参数，当存在更多参数时文件是必需的。
+func fileArg(args []string, count int) (string, []string, error) {
+	if len(args) != count+1 {
+		return "", nil, errUsage
+	}
+	return args[0], args[1:], nil
+}
+
+// optionalFileArg returns the only argument as the file, or stdin when there is no argument.
+// optionalFileArg 返回唯一的参数作为文件，没有参数时使用标准输入。
+func optionalFileArg(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "-", nil
+	case 1:
+		return args[0], nil
+	default:
+		return "", errUsage
+	}
+}
+
+func sortedKeys(items map[string]bool) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
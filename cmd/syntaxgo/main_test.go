@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+)
+
+const demoSource = `package demo
+
+import "os"
+
+// Account is an account.
+type Account struct {
+	Name string ` + "`json:\"name\"`" + `
+	ID   int
+}
+
+func (a *Account) Save() string { return strings.ToUpper(a.Name) }
+
+var x, y = 1, 2
+
+func main() { fmt.Println(x, y) }
+`
+
+// runCommand runs the command line with the stdin, and returns the stdout, the stderr and the exit code.
+// runCommand 使用标准输入运行命令行，并返回标准输出、标准错误以及退出码。
+func runCommand(stdin string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+// writeDemoFile writes the demo source into a temp directory and returns the path.
+// writeDemoFile 将示例源代码写入临时目录并返回路径。
+func writeDemoFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "demo.go")
+	done.Done(os.WriteFile(path, []byte(demoSource), 0644))
+	return path
+}
+
+func TestRun_Usage(t *testing.T) {
+	_, stderr, code := runCommand("")
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, "imports add [-name N] file path...")
+
+	_, _, code = runCommand("", "unknown")
+	require.Equal(t, 2, code)
+
+	_, stderr, code = runCommand("", "tag", "get", "-")
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, "usage: syntaxgo tag get file Struct.Field [key]")
+
+	_, _, code = runCommand("", "outline", "-unknown-flag")
+	require.Equal(t, 2, code)
+}
+
+func TestRun_Write(t *testing.T) {
+	path := writeDemoFile(t)
+
+	stdout, stderr, code := runCommand("", "imports", "add", "-w", path, "strings")
+	require.Equal(t, 0, code, stderr)
+	require.Empty(t, stdout)
+	require.Equal(t, "added import \"strings\"\n", stderr)
+	require.Contains(t, string(rese.V1(os.ReadFile(path))), "import (\n\t\"os\"\n\t\"strings\"\n)\n")
+
+	_, stderr, code = runCommand(demoSource, "imports", "add", "-w", "-", "strings")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "-w needs a file")
+}
+
+func TestRun_Diff(t *testing.T) {
+	path := writeDemoFile(t)
+
+	stdout, _, code := runCommand("", "imports", "remove", "-diff", path, "os")
+	require.Equal(t, 0, code)
+	require.Equal(t, "--- "+path+".orig\n+++ "+path+"\n@@ -1,7 +1,5 @@\n package demo\n \n-import \"os\"\n-\n // Account is an account.\n type Account struct {\n \tName string `json:\"name\"`\n", stdout)
+	require.Equal(t, demoSource, string(rese.V1(os.ReadFile(path))))
+}
+
+func TestRun_JSON(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "imports", "add", "-json", "-diff", "-", "strings")
+	require.Equal(t, 0, code)
+
+	var result changeResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	require.Equal(t, "<stdin>", result.Path)
+	require.True(t, result.Changed)
+	require.False(t, result.Written)
+	require.Equal(t, []string{"added import \"strings\""}, result.Notes)
+	require.Contains(t, result.Diff, "+\t\"strings\"\n")
+	require.Empty(t, result.Source)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_outline"
+)
+
+// pkgNameResult is the JSON output of "pkgname".
+// pkgNameResult 是 "pkgname" 的 JSON 输出。
+type pkgNameResult struct {
+	Path    string `json:"path"`
+	Package string `json:"package"`
+}
+
+// runPkgName prints the package name of a file, or of the Go files in a directory ignoring the external test package.
+// runPkgName 打印文件的包名，或者目录中 Go 文件的包名，忽略外部测试包。
+func runPkgName(env *environment, flags *commandFlags, args []string) error {
+	path, err := optionalFileArg(args)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var packageName string
+	if info, err := os.Stat(path); path != "-" && err == nil && info.IsDir() {
+		if packageName, err = dirPackageName(path); err != nil {
+			return erero.Wro(err)
+		}
+	} else {
+		in, err := readInput(env, path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		path = in.name()
+		packageName = in.astBundle.GetPackageName()
+	}
+	if flags.json {
+		return writeJSON(env, &pkgNameResult{Path: path, Package: packageName})
+	}
+	if _, err := fmt.Fprintln(env.stdout, packageName); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+func dirPackageName(dir string) (string, error) {
+	packages, err := parser.ParseDir(token.NewFileSet(), dir, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	names := map[string]bool{}
+	for name := range packages {
+		if !strings.HasSuffix(name, "_test") {
+			names[name] = true
+		}
+	}
+	switch sortedNames := sortedKeys(names); len(sortedNames) {
+	case 0:
+		return "", erero.Errorf("no Go package in %s", dir)
+	case 1:
+		return sortedNames[0], nil
+	default:
+		return "", erero.Errorf("several packages in %s: %s", dir, strings.Join(sortedNames, ", "))
+	}
+}
+
+// runOutline prints the top-level declarations in source order, the argument can be a file or a package directory.
+// The JSON output is the outline of syntaxgo_outline, with doc comments, signatures, fields and tags.
+// runOutline 按源代码顺序打印顶层声明，参数可以是文件或者包目录。
+// JSON 输出是 syntaxgo_outline 的大纲，包含文档注释、签名、字段以及标签。
+func runOutline(env *environment, flags *commandFlags, args []string) error {
+	path, err := optionalFileArg(args)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var outline *syntaxgo_outline.Outline
+	if info, err := os.Stat(path); path != "-" && err == nil && info.IsDir() {
+		astBundles, err := readPackage(path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		if outline, err = syntaxgo_outline.NewPackageOutline(astBundles); err != nil {
+			return erero.Wro(err)
+		}
+	} else {
+		in, err := readInput(env, path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		if outline, err = syntaxgo_outline.NewFileOutline(in.astBundle); err != nil {
+			return erero.Wro(err)
+		}
+	}
+	if flags.json {
+		return writeJSON(env, outline)
+	}
+	for _, item := range outlineItems(outline) {
+		location := strconv.Itoa(item.position.Line)
+		if len(outline.Files) > 1 {
+			location = filepath.Base(item.position.File) + ":" + location
+		}
+		if _, err := fmt.Fprintf(env.stdout, "%s\t%s\t%s\n", location, item.kind, item.name); err != nil {
+			return erero.Wro(err)
+		}
+	}
+	return nil
+}
+
+// readPackage reads the Go files in the directory, ignoring test files and files of other packages.
+// readPackage 读取目录中的 Go 文件，忽略测试文件以及其他包的文件。
+func readPackage(dir string) ([]*syntaxgo_ast.AstBundle, error) {
+	packageName, err := dirPackageName(dir)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var astBundles []*syntaxgo_ast.AstBundle
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		astBundle, err := syntaxgo_ast.NewAstBundleV4(path)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		if astBundle.GetPackageName() == packageName {
+			astBundles = append(astBundles, astBundle)
+		}
+	}
+	return astBundles, nil
+}
+
+// outlineItem is a line of the text output of "outline".
+// outlineItem 是 "outline" 文本输出中的一行。
+type outlineItem struct {
+	kind     string
+	name     string
+	position *syntaxgo_outline.Position
+}
+
+// outlineItems lists the declarations of the outline sorted by the file, the line and the column.
+// outlineItems 列出大纲中的声明，按文件、行号以及列号排序。
+func outlineItems(outline *syntaxgo_outline.Outline) []*outlineItem {
+	var items []*outlineItem
+	addFunc := func(fn *syntaxgo_outline.Func) {
+		item := &outlineItem{kind: "func", name: fn.Name, position: fn.Position}
+		if fn.Receiver != "" {
+			item.kind, item.name = "method", "("+fn.Receiver+")."+fn.Name
+		}
+		items = append(items, item)
+	}
+	for _, typ := range outline.Types {
+		items = append(items, &outlineItem{kind: "type", name: typ.Name, position: typ.Position})
+		if typ.Kind != "interface" {
+			for _, method := range typ.Methods {
+				addFunc(method)
+			}
+		}
+	}
+	for _, fn := range outline.Funcs {
+		addFunc(fn)
+	}
+	for _, value := range outline.Consts {
+		items = append(items, &outlineItem{kind: "const", name: value.Name, position: value.Position})
+	}
+	for _, value := range outline.Vars {
+		items = append(items, &outlineItem{kind: "var", name: value.Name, position: value.Position})
+	}
+	fileIndex := map[string]int{}
+	for idx, file := range outline.Files {
+		fileIndex[file] = idx
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].position, items[j].position
+		if a.File != b.File {
+			return fileIndex[a.File] < fileIndex[b.File]
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return items
+}
+
+// runPrintAst prints the syntax tree with the positions, skipping the nil and zero fields.
+// runPrintAst 打印带有位置的语法树，跳过 nil 以及零值字段。
+func runPrintAst(env *environment, flags *commandFlags, args []string) error {
+	path, err := optionalFileArg(args)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	in, err := readInput(env, path)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	options := syntaxgo_ast.NewPrintOptions().SetStyle(syntaxgo_ast.PrintStyle(strings.ToUpper(flags.style))).SetMaxDepth(flags.depth)
+	if err := in.astBundle.Fprint(env.stdout, options); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
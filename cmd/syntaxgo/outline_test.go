@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+	"github.com/yyle88/syntaxgo/syntaxgo_outline"
+)
+
+func TestRunPkgName(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "pkgname")
+	require.Equal(t, 0, code)
+	require.Equal(t, "demo\n", stdout)
+
+	stdout, _, code = runCommand("", "pkgname", "-json", runpath.PARENT.Path())
+	require.Equal(t, 0, code)
+	var result pkgNameResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	require.Equal(t, "main", result.Package)
+
+	_, stderr, code := runCommand("", "pkgname", t.TempDir())
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "no Go package in")
+}
+
+func TestRunOutline(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "outline")
+	require.Equal(t, 0, code)
+	require.Equal(t, "6\ttype\tAccount\n11\tmethod\t(*Account).Save\n13\tvar\tx\n13\tvar\ty\n15\tfunc\tmain\n", stdout)
+
+	stdout, _, code = runCommand(demoSource, "outline", "-json", "-")
+	require.Equal(t, 0, code)
+	outline := rese.P1(syntaxgo_outline.ParseJSON([]byte(stdout)))
+	require.Equal(t, "demo", outline.Package)
+	require.Len(t, outline.Types, 1)
+	require.Len(t, outline.Types[0].Methods, 1)
+	require.Equal(t, "*Account", outline.Types[0].Methods[0].Receiver)
+	require.Equal(t, 11, outline.Types[0].Methods[0].Position.Line)
+}
+
+func TestRunOutline_Directory(t *testing.T) {
+	root := t.TempDir()
+	done.Done(os.WriteFile(filepath.Join(root, "a.go"), []byte("package demo\n\nfunc (a *A) Run() {}\n"), 0644))
+	done.Done(os.WriteFile(filepath.Join(root, "b.go"), []byte("package demo\n\ntype A struct{}\n"), 0644))
+	done.Done(os.WriteFile(filepath.Join(root, "b_test.go"), []byte("package demo_test\n\nfunc X() {}\n"), 0644))
+
+	stdout, _, code := runCommand("", "outline", root)
+	require.Equal(t, 0, code)
+	require.Equal(t, "a.go:3\tmethod\t(*A).Run\nb.go:3\ttype\tA\n", stdout)
+
+	stdout, _, code = runCommand("", "outline", "-json", root)
+	require.Equal(t, 0, code)
+	outline := rese.P1(syntaxgo_outline.ParseJSON([]byte(stdout)))
+	require.Len(t, outline.Files, 2)
+	require.Empty(t, outline.Funcs)
+}
+
+func TestRunPrintAst(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "print-ast")
+	require.Equal(t, 0, code)
+	require.True(t, strings.HasPrefix(stdout, "*ast.File\n├── Package: 1:1\n"))
+	require.Contains(t, stdout, "Name: \"Account\"\n")
+
+	stdout, _, code = runCommand(demoSource, "print-ast", "-style", "sexp", "-depth", "1")
+	require.Equal(t, 0, code)
+	require.True(t, strings.HasPrefix(stdout, "(File :Package 1:1 :Name (Ident ...) :Decls [...]"))
+
+	_, stderr, code := runCommand(demoSource, "print-ast", "-style", "xml")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "unknown print style")
+
+	_, _, code = runCommand(demoSource, "print-ast", "-json")
+	require.Equal(t, 2, code)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+	"github.com/yyle88/syntaxgo/syntaxgo_tag"
+)
+
+// tagResult is the JSON output of "tag get".
+// tagResult 是 "tag get" 的 JSON 输出。
+type tagResult struct {
+	Struct string `json:"struct"`
+	Field  string `json:"field"`
+	Tag    string `json:"tag"`
+	Key    string `json:"key,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+func runTagGet(env *environment, flags *commandFlags, args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return errUsage
+	}
+	in, err := readInput(env, args[0])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	structName, fieldName, err := splitFieldArg(args[1])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	field, err := findField(in.astBundle, structName, fieldName)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	tag, err := fieldTag(field)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	result := &tagResult{Struct: structName, Field: fieldName, Tag: tag}
+	output := tag
+	if len(args) == 3 {
+		result.Key = args[2]
+		result.Value = reflect.StructTag(tag).Get(args[2])
+		output = result.Value
+	}
+	if flags.json {
+		return writeJSON(env, result)
+	}
+	if _, err := fmt.Fprintln(env.stdout, output); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+// runTagSet sets the value of a key, the key "gorm.column" sets the field "column" in the value of "gorm".
+// runTagSet 设置键的值，键 "gorm.column" 设置 "gorm" 的值中的 "column" 字段。
+func runTagSet(env *environment, flags *commandFlags, args []string) error {
+	return editTag(env, flags, args, 2, func(tag string, args []string) (string, error) {
+		key, value := args[0], args[1]
+		if key, field, ok := strings.Cut(key, "."); ok {
+			if _, ok := utils.FindTagPair(tag, key); !ok {
+				tag = setTagValue(tag, key, "")
+			}
+			return syntaxgo_tag.SetTagFieldValue(tag, key, field, value, syntaxgo_tag.INSERT_LOCATION_END), nil
+		}
+		return setTagValue(tag, key, value), nil
+	})
+}
+
+func runTagAdd(env *environment, flags *commandFlags, args []string) error {
+	return editTag(env, flags, args, 2, func(tag string, args []string) (string, error) {
+		key, value := args[0], args[1]
+		if _, ok := utils.FindTagPair(tag, key); ok {
+			return "", erero.Errorf("key %s is already in the tag %s", key, tag)
+		}
+		return setTagValue(tag, key, value), nil
+	})
+}
+
+func runTagRemove(env *environment, flags *commandFlags, args []string) error {
+	return editTag(env, flags, args, 1, func(tag string, args []string) (string, error) {
+		newTag := deleteTagValue(tag, args[0])
+		if newTag == tag {
+			return "", erero.Errorf("key %s is not in the tag %s", args[0], tag)
+		}
+		return newTag, nil
+	})
+}
+
+// editTag edits the tag of the field named by the second argument, the edit gets the arguments after it.
+// editTag 编辑第二个参数指定的字段的标签，编辑函数获得其后的参数。
+func editTag(env *environment, flags *commandFlags, args []string, count int, edit func(tag string, args []string) (string, error)) error {
+	path, rest, err := fileArg(args, count+1)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	in, err := readInput(env, path)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	structName, fieldName, err := splitFieldArg(rest[0])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	field, err := findField(in.astBundle, structName, fieldName)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	tag, err := fieldTag(field)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	newTag, err := edit(tag, rest[1:])
+	if err != nil {
+		return erero.Wro(err)
+	}
+	setFieldTag(field, newTag)
+	return writeChange(env, flags, in, nil)
+}
+
+// splitFieldArg splits the argument like "Struct.Field".
+// splitFieldArg 拆分形如 "Struct.Field" 的参数。
+func splitFieldArg(arg string) (string, string, error) {
+	structName, fieldName, ok := strings.Cut(arg, ".")
+	if !ok || structName == "" || fieldName == "" {
+		return "", "", erero.Errorf("field %q is not like Struct.Field", arg)
+	}
+	return structName, fieldName, nil
+}
+
+// findField finds the field of the struct by one of its names, or by the type name of an embedded field.
+// findField 按字段的某个名称，或者嵌入字段的类型名称查找结构体的字段。
+func findField(astBundle *syntaxgo_ast.AstBundle, structName string, fieldName string) (*ast.Field, error) {
+	astFile, _ := astBundle.GetBundle()
+	structType, ok := syntaxgo_search.FindStructTypeByName(astFile, structName)
+	if !ok {
+		return nil, erero.Errorf("no struct name = %s in the source", structName)
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 && syntaxgo_ast.GetEmbeddedFieldName(field.Type) == fieldName {
+			return field, nil
+		}
+		for _, name := range field.Names {
+			if name.Name == fieldName {
+				return field, nil
+			}
+		}
+	}
+	return nil, erero.Errorf("no field name = %s in the struct", fieldName)
+}
+
+// fieldTag returns the tag of the field without the quotes, empty when the field has no tag.
+// fieldTag 返回字段的标签（不含引号），字段没有标签时返回空。
+func fieldTag(field *ast.Field) (string, error) {
+	if field.Tag == nil {
+		return "", nil
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	return tag, nil
+}
+
+// setFieldTag sets the tag of the field, an empty tag removes it.
+// The tag is quoted with backquotes, or with double quotes when it contains a backquote or a newline.
+// setFieldTag 设置字段的标签，空标签表示删除标签。
+// 标签使用反引号引用，当标签包含反引号或换行符时使用双引号。
+func setFieldTag(field *ast.Field, tag string) {
+	if tag == "" {
+		field.Tag = nil
+		return
+	}
+	value := "`" + tag + "`"
+	if strings.ContainsAny(tag, "`\r\n") {
+		value = strconv.Quote(tag)
+	}
+	if field.Tag == nil {
+		field.Tag = &ast.BasicLit{ValuePos: field.Type.End(), Kind: token.STRING}
+	}
+	field.Tag.Value = value
+}
+
+// setTagValue sets the value of the key in the tag, the key is added at the end when it is not in the tag.
+// setTagValue 设置标签中键的值，键不在标签中时添加到末尾。
+func setTagValue(tag string, key string, value string) string {
+	newPair := key + ":" + strconv.Quote(value)
+	if pair, ok := utils.FindTagPair(tag, key); ok {
+		return tag[:pair.Start] + newPair + tag[pair.End:]
+	}
+	if strings.TrimSpace(tag) == "" {
+		return newPair
+	}
+	return strings.TrimRight(tag, " ") + " " + newPair
+}
+
+// deleteTagValue deletes the key and its value from the tag, the tag is returned unchanged when the key is not in it.
+// deleteTagValue 从标签中删除键及其值，键不在标签中时原样返回标签。
+func deleteTagValue(tag string, key string) string {
+	pair, ok := utils.FindTagPair(tag, key)
+	if !ok {
+		return tag
+	}
+	return strings.TrimSpace(strings.TrimRight(tag[:pair.Start], " ") + " " + strings.TrimLeft(tag[pair.End:], " "))
+}
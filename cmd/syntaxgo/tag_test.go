@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTagGet(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "tag", "get", "-", "Account.Name")
+	require.Equal(t, 0, code)
+	require.Equal(t, "json:\"name\"\n", stdout)
+
+	stdout, _, code = runCommand(demoSource, "tag", "get", "-json", "-", "Account.Name", "json")
+	require.Equal(t, 0, code)
+	var result tagResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	require.Equal(t, tagResult{Struct: "Account", Field: "Name", Tag: `json:"name"`, Key: "json", Value: "name"}, result)
+
+	_, stderr, code := runCommand(demoSource, "tag", "get", "-", "Account.Missing")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "no field name = Missing")
+
+	_, stderr, code = runCommand(demoSource, "tag", "get", "-", "Account")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "is not like Struct.Field")
+}
+
+func TestRunTagSet(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "tag", "set", "-", "Account.Name", "json", "username")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tName string `json:\"username\"`\n")
+
+	stdout, _, code = runCommand(demoSource, "tag", "set", "-", "Account.ID", "gorm.column", "id")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tID   int    `gorm:\"column:id;\"`\n")
+}
+
+func TestRunTagAdd(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "tag", "add", "-", "Account.Name", "yaml", "name")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tName string `json:\"name\" yaml:\"name\"`\n")
+
+	_, stderr, code := runCommand(demoSource, "tag", "add", "-", "Account.Name", "json", "name")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "key json is already in the tag")
+}
+
+func TestRunTagRemove(t *testing.T) {
+	stdout, _, code := runCommand(demoSource, "tag", "remove", "-", "Account.Name", "json")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tName string\n")
+
+	_, stderr, code := runCommand(demoSource, "tag", "remove", "-", "Account.Name", "yaml")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "key yaml is not in the tag")
+}
+
+func TestRunTagRemove_EscapedQuote(t *testing.T) {
+	const source = "package demo\n\ntype Account struct {\n\tName string `desc:\"say \\\"hi\\\"\" json:\"name\"`\n}\n"
+
+	stdout, _, code := runCommand(source, "tag", "get", "-", "Account.Name", "desc")
+	require.Equal(t, 0, code)
+	require.Equal(t, "say \"hi\"\n", stdout)
+
+	stdout, _, code = runCommand(source, "tag", "remove", "-", "Account.Name", "desc")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tName string `json:\"name\"`\n")
+}
+
+func TestRunTagSet_EscapedQuote(t *testing.T) {
+	const source = "package demo\n\ntype Account struct {\n\tName string `desc:\"say \\\"hi\\\"\" json:\"name\"`\n\tID   int // primary key\n}\n"
+
+	stdout, _, code := runCommand(source, "tag", "set", "-", "Account.Name", "json", `a"b`)
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tName string `desc:\"say \\\"hi\\\"\" json:\"a\\\"b\"`\n")
+
+	stdout, _, code = runCommand(source, "tag", "add", "-", "Account.ID", "json", "id")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "\tID   int    `json:\"id\"` // primary key\n")
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strings"
+)
+
+// CutDirective cuts a directive comment line like "//go:embed static" into "go", "embed" and "static",
+// it is shared by syntaxgo_search and syntaxgo_ast.
+// Following the Go convention, the line must start with "//" directly followed by "word:word" in lowercase letters and digits.
+// CutDirective 将指令注释行（比如 "//go:embed static"）切分为 "go"、"embed" 和 "static"，由 syntaxgo_search 和 syntaxgo_ast 共用。
+// 按照 Go 的约定，该行必须以 "//" 开头，并紧跟由小写字母和数字组成的 "word:word"。
+func CutDirective(text string) (namespace string, name string, value string, ok bool) {
+	line, ok := strings.CutPrefix(text, "//")
+	if !ok {
+		return "", "", "", false
+	}
+	namespace, rest, ok := strings.Cut(line, ":")
+	if !ok || !isDirectiveWord(namespace) {
+		return "", "", "", false
+	}
+	name, value, _ = strings.Cut(rest, " ")
+	if name == "" || !isDirectiveWord(name[:1]) {
+		return "", "", "", false
+	}
+	return namespace, name, strings.TrimSpace(value), true
+}
+
+func isDirectiveWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, c := range word {
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"go/ast"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GuessPackageName guesses the package name from the import path, it is shared by syntaxgo_search and syntaxgo_ast.
+// GuessPackageName 根据导入路径推测包名，由 syntaxgo_search 和 syntaxgo_ast 共用。
+func GuessPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			if dir := path.Dir(importPath); dir != "." {
+				base = path.Base(dir)
+			}
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if idx := strings.IndexFunc(base, func(c rune) bool {
+		return !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_')
+	}); idx >= 0 {
+		base = base[:idx]
+	}
+	return base
+}
+
+// UsesQualifier reports whether the file uses the package name as a qualifier, a dot import is used by any unresolved identifier.
+// UsesQualifier 判断文件是否把该包名用作限定符，点导入只要存在未解析的标识符就视为被使用。
+func UsesQualifier(astFile *ast.File, name string) bool {
+	used := false
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if used {
+			return false
+		}
+		switch item := node.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := item.X.(*ast.Ident); ok && ident.Obj == nil && ident.Name == name {
+				used = true
+			}
+		case *ast.Ident:
+			if name == "." && item.Obj == nil && item != astFile.Name {
+				used = true
+			}
+		}
+		return true
+	})
+	return used
+}
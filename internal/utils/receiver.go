@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"go/ast"
+)
+
+// GetReceiverTypeName returns the type name of the function receiver and whether it is a pointer receiver,
+// it is shared by syntaxgo_search and syntaxgo_ast.
+// GetReceiverTypeName 返回函数接收者的类型名称以及是否为指针接收者，由 syntaxgo_search 和 syntaxgo_ast 共用。
+func GetReceiverTypeName(funcDecl *ast.FuncDecl) (typeName string, isPointer bool, ok bool) {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return "", false, false
+	}
+	recvType := funcDecl.Recv.List[0].Type
+	for {
+		switch node := recvType.(type) {
+		case *ast.ParenExpr:
+			recvType = node.X
+		case *ast.StarExpr:
+			isPointer = true
+			recvType = node.X
+		case *ast.IndexExpr:
+			recvType = node.X
+		case *ast.IndexListExpr:
+			recvType = node.X
+		case *ast.Ident:
+			return node.Name, isPointer, true
+		default:
+			return "", false, false
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"strconv"
+)
+
+// TagPair is a key:"value" pair of a struct tag, like json:"name" in `json:"name" gorm:"column:name"`.
+// TagPair 是结构体标签中的一个 key:"value" 对，比如 `json:"name" gorm:"column:name"` 中的 json:"name"。
+type TagPair struct {
+	Key   string // Key like json / 键，比如 json
+	Value string // Unquoted value / 去掉引号的值
+	Start int    // Index of the key in the tag / 键在标签中的下标
+	End   int    // Index after the closing quote in the tag / 标签中右引号之后的下标
+}
+
+// ParseTagPairs parses the tag into key:"value" pairs in order, by the rules of reflect.StructTag,
+// it is shared by syntaxgo_outline and the syntaxgo command.
+// The values are Go string literals, so escaped quotes like json:"a\"b" are kept in the value.
+// Like reflect.StructTag.Lookup, the parsing stops at the first malformed pair.
+// ParseTagPairs 按 reflect.StructTag 的规则将标签按顺序解析为 key:"value" 对，由 syntaxgo_outline 和 syntaxgo 命令共用。
+// 值是 Go 字符串字面量，因此像 json:"a\"b" 这样转义的引号会保留在值中。
+// 与 reflect.StructTag.Lookup 一样，遇到第一个格式错误的对时停止解析。
+func ParseTagPairs(tag string) []*TagPair {
+	var pairs []*TagPair
+	offset := 0
+	for offset < len(tag) {
+		// Skip the leading space.
+		// 跳过前导空格。
+		for offset < len(tag) && tag[offset] == ' ' {
+			offset++
+		}
+		start := offset
+		// The key is a non-empty string of non-control characters other than space, quote and colon.
+		// 键是由除空格、引号以及冒号之外的非控制字符组成的非空字符串。
+		for offset < len(tag) && tag[offset] > ' ' && tag[offset] != ':' && tag[offset] != '"' && tag[offset] != 0x7f {
+			offset++
+		}
+		if offset == start || offset+1 >= len(tag) || tag[offset] != ':' || tag[offset+1] != '"' {
+			break
+		}
+		key := tag[start:offset]
+		offset++ // Skip the colon / 跳过冒号
+
+		// Scan the quoted string to find the value.
+		// 扫描带引号的字符串以找到值。
+		quoteStart := offset
+		offset++
+		for offset < len(tag) && tag[offset] != '"' {
+			if tag[offset] == '\\' {
+				offset++
+			}
+			offset++
+		}
+		if offset >= len(tag) {
+			break
+		}
+		offset++ // Skip the closing quote / 跳过右引号
+		value, err := strconv.Unquote(tag[quoteStart:offset])
+		if err != nil {
+			break
+		}
+		pairs = append(pairs, &TagPair{Key: key, Value: value, Start: start, End: offset})
+	}
+	return pairs
+}
+
+// FindTagPair finds the first pair of the key in the tag, see ParseTagPairs.
+// FindTagPair 查找标签中该键的第一个对，参见 ParseTagPairs。
+func FindTagPair(tag, key string) (*TagPair, bool) {
+	for _, pair := range ParseTagPairs(tag) {
+		if pair.Key == key {
+			return pair, true
+		}
+	}
+	return nil, false
+}
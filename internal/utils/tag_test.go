@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagPairs(t *testing.T) {
+	const tag = `json:"name,omitempty"  gorm:"column:name" desc:"say \"hi\""`
+
+	pairs := ParseTagPairs(tag)
+	require.Len(t, pairs, 3)
+	require.Equal(t, &TagPair{Key: "json", Value: "name,omitempty", Start: 0, End: 21}, pairs[0])
+	require.Equal(t, "gorm", pairs[1].Key)
+	require.Equal(t, `gorm:"column:name"`, tag[pairs[1].Start:pairs[1].End])
+	require.Equal(t, `say "hi"`, pairs[2].Value)
+
+	// The values agree with reflect.StructTag.
+	// 值与 reflect.StructTag 一致。
+	for _, pair := range pairs {
+		require.Equal(t, reflect.StructTag(tag).Get(pair.Key), pair.Value)
+	}
+
+	// The parsing stops at the first malformed pair like reflect.StructTag.
+	// 与 reflect.StructTag 一样，遇到第一个格式错误的对时停止解析。
+	pairs = ParseTagPairs(`json:"id" gorm:column yaml:"id"`)
+	require.Len(t, pairs, 1)
+	require.Empty(t, ParseTagPairs(""))
+	require.Empty(t, ParseTagPairs(`json:"id`))
+}
+
+func TestFindTagPair(t *testing.T) {
+	pair, ok := FindTagPair(`xjson:"a" json:"b"`, "json")
+	require.True(t, ok)
+	require.Equal(t, "b", pair.Value)
+
+	_, ok = FindTagPair(`json:"b"`, "yaml")
+	require.False(t, ok)
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// ListGoFiles lists the Go files in the root in sorted order, it is shared by syntaxgo_batch and syntaxgo_rewrite.
+// With recursive, the subdirectories are walked too, skipping the ones the go command ignores and the nested modules, see IsSkippedDir.
+// ListGoFiles 按排序后的顺序列出 root 中的 Go 文件，由 syntaxgo_batch 和 syntaxgo_rewrite 共用。
+// 设置 recursive 时也会遍历子目录，跳过 go 命令忽略的目录以及嵌套模块，参见 IsSkippedDir。
+func ListGoFiles(root string, recursive bool) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == root {
+				return nil
+			}
+			if !recursive || IsSkippedDir(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && filepath.Ext(path) == ".go" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// IsSkippedDir reports whether walking a module tree skips the directory:
+// vendor, testdata, the ones starting with "." or "_", and the roots of nested modules.
+// IsSkippedDir 判断遍历模块树时是否跳过该目录：
+// vendor、testdata、以 "." 或 "_" 开头的目录，以及嵌套模块的根目录。
+func IsSkippedDir(path string) bool {
+	name := filepath.Base(path)
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(path, "go.mod"))
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,176 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+
+	"github.com/yyle88/erero"
+)
+
+// editSource formats the bundle into source code, applies the edit, and replaces the bundle with the reparsed result.
+// The node is located again in the formatted source, so the edit gets the node of the file it edits.
+// After the edit, nodes found before the call are no longer part of the bundle.
+// editSource 将 AST 包格式化为源代码并执行编辑，然后用重新解析的结果替换 AST 包。
+// 节点会在格式化后的源代码中重新定位，因此编辑函数拿到的是它所编辑文件中的节点。
+// 编辑之后，调用之前找到的节点不再属于该 AST 包。
+func (ab *AstBundle) editSource(node ast.Node, edit func(source []byte, astFile *ast.File, node ast.Node) ([]byte, error)) error {
+	source, err := ab.FormatSource()
+	if err != nil {
+		return erero.Wro(err)
+	}
+	fset := token.NewFileSet()
+	astFile, err := parseSource(fset, ab.fileName(), source)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	var newNode ast.Node
+	if node != nil {
+		var ok bool
+		if newNode, ok = findSameNode(ab.file, node, astFile); !ok {
+			return erero.Errorf("node %T is not in the bundle", node)
+		}
+	}
+	newSource, err := edit(source, astFile, newNode)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	return ab.reload(newSource)
+}
+
+// reload replaces the bundle with the result of parsing the source code.
+// The positions keep the file name of the bundle, and the bundle keeps its origin file.
+// reload 用解析源代码的结果替换 AST 包。
+// 位置保持 AST 包的文件名，AST 包也保持其来源文件。
+func (ab *AstBundle) reload(source []byte) error {
+	fset := token.NewFileSet()
+	astFile, err := parseSource(fset, ab.fileName(), source)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	ab.fset = fset
+	ab.file = astFile
+	return nil
+}
+
+// fileName returns the file name of the positions in the bundle, empty when it is parsed from bytes.
+// fileName 返回 AST 包中位置的文件名，从字节解析时为空。
+func (ab *AstBundle) fileName() string {
+	return ab.fset.Position(ab.file.Pos()).Filename
+}
+
+// parseSource parses the source code with comments, the positions use the name as the file name.
+// parseSource 解析源代码，同时保留注释，位置使用该名称作为文件名。
+func parseSource(fset *token.FileSet, name string, source []byte) (*ast.File, error) {
+	astFile, err := parser.ParseFile(fset, name, source, parser.ParseComments)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return astFile, nil
+}
+
+// findSameNode finds the node in another file with the same structure, like the file reparsed from the formatted source.
+// The node is matched by its type and its index among the nodes of that type, in the order of ast.Inspect.
+// findSameNode 在结构相同的另一个文件中（比如格式化后重新解析的文件）找到对应的节点。
+// 节点通过其类型以及在 ast.Inspect 顺序中同类型节点里的序号进行匹配。
+func findSameNode(astFile *ast.File, node ast.Node, newFile *ast.File) (ast.Node, bool) {
+	if node == ast.Node(astFile) {
+		return newFile, true
+	}
+	nodeType := reflect.TypeOf(node)
+	index := -1
+	count := 0
+	ast.Inspect(astFile, func(item ast.Node) bool {
+		if index >= 0 || item == nil {
+			return false
+		}
+		if reflect.TypeOf(item) == nodeType {
+			if item == node {
+				index = count
+				return false
+			}
+			count++
+		}
+		return true
+	})
+	if index < 0 {
+		return nil, false
+	}
+	var result ast.Node
+	count = 0
+	ast.Inspect(newFile, func(item ast.Node) bool {
+		if result != nil || item == nil {
+			return false
+		}
+		if reflect.TypeOf(item) == nodeType {
+			if count == index {
+				result = item
+				return false
+			}
+			count++
+		}
+		return true
+	})
+	return result, result != nil
+}
+
+// offsetOf returns the byte offset of the position in the source code of the file.
+// offsetOf 返回位置在文件源代码中的字节偏移量。
+func offsetOf(astFile *ast.File, pos token.Pos) int {
+	return int(pos - astFile.FileStart)
+}
+
+// lineStart returns the offset of the start of the line containing the offset.
+// lineStart 返回包含该偏移量的行的起始偏移量。
+func lineStart(source []byte, offset int) int {
+	for offset > 0 && source[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// lineEnd returns the offset after the newline of the line containing the offset.
+// lineEnd 返回包含该偏移量的行的换行符之后的偏移量。
+func lineEnd(source []byte, offset int) int {
+	for offset < len(source) && source[offset] != '\n' {
+		offset++
+	}
+	if offset < len(source) {
+		offset++
+	}
+	return offset
+}
+
+// lineIndent returns the indent before the offset, and false when there is code before the offset in the same line.
+// lineIndent 返回偏移量之前的缩进，当同一行中偏移量之前存在代码时返回 false。
+func lineIndent(source []byte, offset int) (string, bool) {
+	start := lineStart(source, offset)
+	for idx := start; idx < offset; idx++ {
+		if source[idx] != ' ' && source[idx] != '\t' {
+			return "", false
+		}
+	}
+	return string(source[start:offset]), true
+}
+
+// replaceRange replaces the source code between the offsets with the new code.
+// replaceRange 用新代码替换两个偏移量之间的源代码。
+func replaceRange(source []byte, start int, end int, newCode []byte) []byte {
+	result := make([]byte, 0, len(source)-(end-start)+len(newCode))
+	result = append(result, source[:start]...)
+	result = append(result, newCode...)
+	result = append(result, source[end:]...)
+	return result
+}
+
+// formatEditedSource formats the edited source code, so the inserted code gets the standard layout.
+// formatEditedSource 格式化编辑后的源代码，使插入的代码具有标准的排版。
+func formatEditedSource(source []byte) ([]byte, error) {
+	newSource, err := format.Source(source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newSource, nil
+}
@@ -0,0 +1,367 @@
+package syntaxgo_ast
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"sort"
+
+	"github.com/yyle88/erero"
+)
+
+// AstJSONVersion is the version of the JSON encoding of the AST, the decoder rejects other versions.
+// AstJSONVersion 是 AST 的 JSON 编码版本，解码时拒绝其他版本。
+const AstJSONVersion = "syntaxgo.ast/v1"
+
+// astJSON is the JSON document of an encoded file.
+// astJSON 是已编码文件的 JSON 文档。
+type astJSON struct {
+	Version string          `json:"version"`
+	File    *astJSONFile    `json:"file"`
+	Ast     json.RawMessage `json:"ast"`
+}
+
+// astJSONFile is the token.File of the AST, it makes the positions valid after decoding.
+// astJSONFile 是 AST 的 token.File，使解码之后的位置仍然有效。
+type astJSONFile struct {
+	Name  string `json:"name"`
+	Base  int    `json:"base"`
+	Size  int    `json:"size"`
+	Lines []int  `json:"lines"`
+}
+
+// astJSONTypes are the types of the pointers and interface values that can appear in an ast.File, named in "$type".
+// astJSONTypes 是 ast.File 中可能出现的指针以及接口值的类型，在 "$type" 中以名称表示。
+var astJSONTypes = func() map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for _, value := range []any{
+		(*ast.ArrayType)(nil), (*ast.AssignStmt)(nil), (*ast.BadDecl)(nil), (*ast.BadExpr)(nil),
+		(*ast.BadStmt)(nil), (*ast.BasicLit)(nil), (*ast.BinaryExpr)(nil), (*ast.BlockStmt)(nil),
+		(*ast.BranchStmt)(nil), (*ast.CallExpr)(nil), (*ast.CaseClause)(nil), (*ast.ChanType)(nil),
+		(*ast.CommClause)(nil), (*ast.Comment)(nil), (*ast.CommentGroup)(nil), (*ast.CompositeLit)(nil),
+		(*ast.DeclStmt)(nil), (*ast.DeferStmt)(nil), (*ast.Ellipsis)(nil), (*ast.EmptyStmt)(nil),
+		(*ast.ExprStmt)(nil), (*ast.Field)(nil), (*ast.FieldList)(nil), (*ast.File)(nil),
+		(*ast.ForStmt)(nil), (*ast.FuncDecl)(nil), (*ast.FuncLit)(nil), (*ast.FuncType)(nil),
+		(*ast.GenDecl)(nil), (*ast.GoStmt)(nil), (*ast.Ident)(nil), (*ast.IfStmt)(nil),
+		(*ast.ImportSpec)(nil), (*ast.IncDecStmt)(nil), (*ast.IndexExpr)(nil), (*ast.IndexListExpr)(nil),
+		(*ast.InterfaceType)(nil), (*ast.KeyValueExpr)(nil), (*ast.LabeledStmt)(nil), (*ast.MapType)(nil),
+		(*ast.Object)(nil), (*ast.ParenExpr)(nil), (*ast.RangeStmt)(nil), (*ast.ReturnStmt)(nil),
+		(*ast.Scope)(nil), (*ast.SelectStmt)(nil), (*ast.SelectorExpr)(nil), (*ast.SendStmt)(nil),
+		(*ast.SliceExpr)(nil), (*ast.StarExpr)(nil), (*ast.StructType)(nil), (*ast.SwitchStmt)(nil),
+		(*ast.TypeAssertExpr)(nil), (*ast.TypeSpec)(nil), (*ast.TypeSwitchStmt)(nil), (*ast.UnaryExpr)(nil),
+		(*ast.ValueSpec)(nil),
+		// The value of a const object is its iota, stored as an int in ast.Object.Data.
+		// 常量对象的值是其 iota，以 int 存储在 ast.Object.Data 中。
+		0, "", false,
+	} {
+		typ := reflect.TypeOf(value)
+		if typ.Kind() == reflect.Pointer {
+			types[typ.Elem().Name()] = typ
+		} else {
+			types[typ.Name()] = typ
+		}
+	}
+	return types
+}()
+
+/*
+EncodeJSON encodes the AST file as JSON without loss, so DecodeJSON rebuilds an equivalent file.
+
+Every node is an object with its go/ast type in "$type" and its fields by their Go names, positions are the
+token.Pos numbers and the token.File is stored along, so the positions stay valid after decoding. A pointer
+seen a second time, like a doc comment also listed in File.Comments or an ast.Object shared by identifiers,
+is written as {"$ref": id} of the "$id" of its first appearance, so shared and cyclic nodes are kept.
+The line directives like "//line a.go:10" are not kept in the positions.
+*/
+/*
+EncodeJSON 将 AST 文件无损编码为 JSON，使 DecodeJSON 能够重建等价的文件。
+
+每个节点都是一个对象，"$type" 中是其 go/ast 类型，字段使用其 Go 名称，位置是 token.Pos 数值，并且一同保存
+token.File，因此解码之后位置仍然有效。第二次遇到的指针（比如同时出现在 File.Comments 中的文档注释，或者
+多个标识符共享的 ast.Object）写作 {"$ref": id}，引用其第一次出现时的 "$id"，从而保留共享以及循环的节点。
+位置中不保留形如 "//line a.go:10" 的行指令。
+*/
+func EncodeJSON(fset *token.FileSet, astFile *ast.File) ([]byte, error) {
+	tokenFile := fset.File(astFile.Pos())
+	if tokenFile == nil {
+		return nil, erero.New("the file is not in the file set")
+	}
+	encoder := &astJSONEncoder{ids: map[astJSONPointer]int{}}
+	node, err := encoder.encode(reflect.ValueOf(astFile))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	nodeData, err := json.Marshal(node)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	document := &astJSON{
+		Version: AstJSONVersion,
+		File: &astJSONFile{
+			Name:  tokenFile.Name(),
+			Base:  tokenFile.Base(),
+			Size:  tokenFile.Size(),
+			Lines: tokenFile.Lines(),
+		},
+		Ast: nodeData,
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return data, nil
+}
+
+// DecodeJSON decodes the AST file encoded by EncodeJSON, with a new file set holding its token.File.
+// DecodeJSON 解码由 EncodeJSON 编码的 AST 文件，并返回包含其 token.File 的新文件集。
+func DecodeJSON(data []byte) (*ast.File, *token.FileSet, error) {
+	var document astJSON
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, nil, erero.Wro(err)
+	}
+	if document.Version != AstJSONVersion {
+		return nil, nil, erero.Errorf("ast json version %q is not %q", document.Version, AstJSONVersion)
+	}
+	if document.File == nil || document.File.Base < 1 {
+		return nil, nil, erero.New("ast json has no valid file")
+	}
+	fset := token.NewFileSet()
+	tokenFile := fset.AddFile(document.File.Name, document.File.Base, document.File.Size)
+	if !tokenFile.SetLines(document.File.Lines) {
+		return nil, nil, erero.Errorf("ast json has invalid lines of the file %s", document.File.Name)
+	}
+	decoder := &astJSONDecoder{ids: map[int]reflect.Value{}}
+	value, err := decoder.decode(document.Ast, reflect.TypeOf((*ast.File)(nil)))
+	if err != nil {
+		return nil, nil, erero.Wro(err)
+	}
+	astFile := value.Interface().(*ast.File)
+	if astFile == nil {
+		return nil, nil, erero.New("ast json has no file")
+	}
+	return astFile, fset, nil
+}
+
+// NewAstBundleFromJSON creates an AstBundle from the JSON encoded by EncodeJSON, the bundle has no origin file.
+// NewAstBundleFromJSON 根据 EncodeJSON 编码的 JSON 创建 AstBundle，该 AST 包没有来源文件。
+func NewAstBundleFromJSON(data []byte) (*AstBundle, error) {
+	astFile, fset, err := DecodeJSON(data)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return NewAstBundle(fset, astFile), nil
+}
+
+// EncodeJSON encodes the AST as JSON without loss, see the function EncodeJSON.
+// EncodeJSON 将 AST 无损编码为 JSON，参见函数 EncodeJSON。
+func (ab *AstBundle) EncodeJSON() ([]byte, error) {
+	return EncodeJSON(ab.fset, ab.file)
+}
+
+// astJSONPointer identifies a pointer by its type and address.
+// astJSONPointer 通过类型和地址标识一个指针。
+type astJSONPointer struct {
+	typ     reflect.Type
+	address uintptr
+}
+
+// astJSONEncoder converts the AST to JSON values, the ids are given to the pointers in the order of the fields.
+// astJSONEncoder 将 AST 转换为 JSON 值，按字段的顺序为指针分配 id。
+type astJSONEncoder struct {
+	ids map[astJSONPointer]int
+}
+
+func (encoder *astJSONEncoder) encode(value reflect.Value) (any, error) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil, nil
+		}
+		pointer := astJSONPointer{typ: value.Type(), address: value.Pointer()}
+		if id, ok := encoder.ids[pointer]; ok {
+			return map[string]any{"$ref": id}, nil
+		}
+		if value.Elem().Kind() != reflect.Struct || astJSONTypes[value.Type().Elem().Name()] != value.Type() {
+			return nil, erero.Errorf("unsupported ast type %s", value.Type())
+		}
+		id := len(encoder.ids) + 1
+		encoder.ids[pointer] = id
+		node := map[string]any{"$id": id, "$type": value.Type().Elem().Name()}
+		structValue := value.Elem()
+		for idx := 0; idx < structValue.NumField(); idx++ {
+			field := structValue.Type().Field(idx)
+			if !field.IsExported() {
+				continue
+			}
+			fieldNode, err := encoder.encode(structValue.Field(idx))
+			if err != nil {
+				return nil, erero.WithMessagef(err, "encode %s.%s", value.Type().Elem().Name(), field.Name)
+			}
+			node[field.Name] = fieldNode
+		}
+		return node, nil
+	case reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		elem := value.Elem()
+		if elem.Kind() == reflect.Pointer {
+			return encoder.encode(elem)
+		}
+		if astJSONTypes[elem.Type().Name()] != elem.Type() {
+			return nil, erero.Errorf("unsupported ast value type %s", elem.Type())
+		}
+		return map[string]any{"$type": elem.Type().Name(), "value": elem.Interface()}, nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil, nil
+		}
+		items := make([]any, 0, value.Len())
+		for idx := 0; idx < value.Len(); idx++ {
+			item, err := encoder.encode(value.Index(idx))
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case reflect.Map:
+		if value.IsNil() {
+			return nil, nil
+		}
+		items := map[string]any{}
+		for _, key := range sortedMapKeys(value) {
+			item, err := encoder.encode(value.MapIndex(key))
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			items[key.String()] = item
+		}
+		return items, nil
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	default:
+		return nil, erero.Errorf("unsupported ast value kind %s", value.Kind())
+	}
+}
+
+// astJSONDecoder rebuilds the AST from JSON, visiting the fields in the same order as the encoder to resolve the ids.
+// astJSONDecoder 根据 JSON 重建 AST，按与编码时相同的顺序访问字段以解析 id。
+type astJSONDecoder struct {
+	ids map[int]reflect.Value
+}
+
+func (decoder *astJSONDecoder) decode(data json.RawMessage, typ reflect.Type) (reflect.Value, error) {
+	if string(data) == "null" {
+		return reflect.Zero(typ), nil
+	}
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		var node map[string]json.RawMessage
+		if err := json.Unmarshal(data, &node); err != nil {
+			return reflect.Value{}, erero.Wro(err)
+		}
+		if refData, ok := node["$ref"]; ok {
+			var id int
+			if err := json.Unmarshal(refData, &id); err != nil {
+				return reflect.Value{}, erero.Wro(err)
+			}
+			value, ok := decoder.ids[id]
+			if !ok || !value.Type().AssignableTo(typ) {
+				return reflect.Value{}, erero.Errorf("invalid ref %d to %s", id, typ)
+			}
+			return value, nil
+		}
+		var typeName string
+		if err := json.Unmarshal(node["$type"], &typeName); err != nil {
+			return reflect.Value{}, erero.WithMessagef(err, "decode $type of %s", typ)
+		}
+		nodeType, ok := astJSONTypes[typeName]
+		if !ok || !nodeType.AssignableTo(typ) {
+			return reflect.Value{}, erero.Errorf("invalid $type %q for %s", typeName, typ)
+		}
+		if nodeType.Kind() != reflect.Pointer {
+			value, err := decoder.decode(node["value"], nodeType)
+			if err != nil {
+				return reflect.Value{}, erero.Wro(err)
+			}
+			return value, nil
+		}
+		var id int
+		if err := json.Unmarshal(node["$id"], &id); err != nil {
+			return reflect.Value{}, erero.WithMessagef(err, "decode $id of %s", typeName)
+		}
+		value := reflect.New(nodeType.Elem())
+		decoder.ids[id] = value
+		structValue := value.Elem()
+		for idx := 0; idx < structValue.NumField(); idx++ {
+			field := structValue.Type().Field(idx)
+			fieldData, ok := node[field.Name]
+			if !field.IsExported() || !ok {
+				continue
+			}
+			fieldValue, err := decoder.decode(fieldData, field.Type)
+			if err != nil {
+				return reflect.Value{}, erero.WithMessagef(err, "decode %s.%s", typeName, field.Name)
+			}
+			structValue.Field(idx).Set(fieldValue)
+		}
+		return value, nil
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return reflect.Value{}, erero.Wro(err)
+		}
+		value := reflect.MakeSlice(typ, len(items), len(items))
+		for idx, itemData := range items {
+			item, err := decoder.decode(itemData, typ.Elem())
+			if err != nil {
+				return reflect.Value{}, erero.Wro(err)
+			}
+			value.Index(idx).Set(item)
+		}
+		return value, nil
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return reflect.Value{}, erero.Wro(err)
+		}
+		keys := make([]string, 0, len(items))
+		for key := range items {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		value := reflect.MakeMapWithSize(typ, len(items))
+		for _, key := range keys {
+			item, err := decoder.decode(items[key], typ.Elem())
+			if err != nil {
+				return reflect.Value{}, erero.Wro(err)
+			}
+			value.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), item)
+		}
+		return value, nil
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value := reflect.New(typ)
+		if err := json.Unmarshal(data, value.Interface()); err != nil {
+			return reflect.Value{}, erero.Wro(err)
+		}
+		return value.Elem(), nil
+	default:
+		return reflect.Value{}, erero.Errorf("unsupported ast value kind %s", typ.Kind())
+	}
+}
+
+// sortedMapKeys returns the string keys of the map in sorted order, so the ids are given in a stable order.
+// sortedMapKeys 按排序后的顺序返回映射的字符串键，从而以稳定的顺序分配 id。
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
@@ -0,0 +1,96 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+const astJSONSource = `// Package demo is a demo.
+package demo
+
+import "fmt"
+
+const (
+	A = iota // first
+	B
+)
+
+// Account is an account.
+type Account[T any] struct {
+	Name string ` + "`json:\"name\"`" + `
+	Data T
+}
+
+func (a *Account[T]) Show(values ...int) {
+	for i, v := range values {
+		if v > 0 {
+			goto done
+		}
+		fmt.Println(i, a.Name, "\tx")
+	}
+done:
+	_ = func() chan<- int { return nil }
+}
+`
+
+func TestEncodeJSON(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(astJSONSource)))
+
+	data := rese.V1(astBundle.EncodeJSON())
+	require.True(t, strings.HasPrefix(string(data), "{\n  \"version\": \"syntaxgo.ast/v1\",\n"))
+	require.Contains(t, string(data), `"$type": "File"`)
+
+	newBundle := rese.P1(NewAstBundleFromJSON(data))
+	astFile, fset := astBundle.GetBundle()
+	newFile, newFset := newBundle.GetBundle()
+	// Deep equality covers the comments, the objects and the pointers shared between the nodes.
+	// 深度相等覆盖注释、对象以及节点之间共享的指针。
+	require.True(t, reflect.DeepEqual(astFile, newFile))
+	require.Same(t, newFile.Doc, newFile.Comments[0])
+	typeSpec := newFile.Decls[2].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	require.Same(t, typeSpec, typeSpec.Name.Obj.Decl)
+
+	require.Equal(t, rese.V1(astBundle.FormatSource()), rese.V1(newBundle.FormatSource()))
+	position := fset.Position(astFile.Decls[3].Pos())
+	require.Equal(t, position, newFset.Position(newFile.Decls[3].Pos()))
+	require.Equal(t, 17, position.Line)
+
+	// Encoding the decoded file gives the same JSON, so the JSON is usable in snapshot tests.
+	// 编码解码后的文件得到相同的 JSON，因此该 JSON 可用于快照测试。
+	require.Equal(t, string(data), string(rese.V1(newBundle.EncodeJSON())))
+}
+
+func TestEncodeJSON_CurrentFile(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV4(runpath.CurrentPath()))
+
+	newBundle := rese.P1(NewAstBundleFromJSON(rese.V1(astBundle.EncodeJSON())))
+	astFile, _ := astBundle.GetBundle()
+	newFile, _ := newBundle.GetBundle()
+	require.True(t, reflect.DeepEqual(astFile, newFile))
+	require.Empty(t, newBundle.GetPath())
+}
+
+func TestEncodeJSON_NotInFileSet(t *testing.T) {
+	astFile, _ := rese.P1(NewAstBundleV1([]byte("package demo\n"))).GetBundle()
+
+	_, err := EncodeJSON(token.NewFileSet(), astFile)
+	require.Error(t, err)
+}
+
+func TestDecodeJSON_Invalid(t *testing.T) {
+	_, _, err := DecodeJSON([]byte(`{"version": "syntaxgo.ast/v0"}`))
+	require.Error(t, err)
+
+	_, _, err = DecodeJSON([]byte(`{"version": "syntaxgo.ast/v1", "file": {"name": "a.go", "base": 1, "size": 10, "lines": [0]}, "ast": {"$id": 1, "$type": "Ident"}}`))
+	require.Error(t, err)
+
+	_, _, err = DecodeJSON([]byte(`{"version": "syntaxgo.ast/v1", "file": {"name": "a.go", "base": 1, "size": 10, "lines": [0]}, "ast": {"$id": 1, "$type": "File", "Name": {"$ref": 5}}}`))
+	require.Error(t, err)
+}
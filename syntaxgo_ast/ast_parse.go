@@ -2,11 +2,14 @@ package syntaxgo_ast
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/printer"
+	"go/scanner"
 	"go/token"
+	"os"
 
 	"github.com/yyle88/erero"
 	"golang.org/x/tools/go/ast/astutil"
@@ -17,11 +20,21 @@ Package `syntaxgo_ast` provides tools for parsing, analyzing, and manipulating G
 
 This package simplifies common tasks such as:
   - Creating and managing AST (Abstract Syntax Tree) bundles.
+  - Loading files and packages from an fs.FS, an overlay of in-memory contents or an io.Reader.
+  - Parsing half-written files tolerantly, keeping the partial AST together with the syntax errors as diagnostics.
   - Adding or removing import paths programmatically.
+  - Editing doc comments and the generated file header.
+  - Regenerating the code between begin and end marker comments, keeping the rest of the file untouched.
+  - Replacing or inserting functions, methods, types and consts by name.
+  - Deleting declarations, specs and fields together with their comments, and deleting unused imports.
+  - Appending, inserting, removing and reordering struct fields, splitting multi-name fields when needed, and setting their tags.
+  - Renaming functions, methods, types and fields with their references in a package, resolved with go/types.
   - Formatting AST nodes back into Go source code.
+  - Saving the bundle back to its file atomically, refusing when the file changed on disk since it was parsed.
   - Serializing AST structures into textual representations.
+  - Encoding the AST as JSON without loss and decoding it back, for caching and snapshot tests.
   - Accessing metadata like the package name.
-  - Printing the AST structure for debugging or analysis.
+  - Printing the AST structure for debugging or analysis, to any writer as a tree or s-expressions with a depth limit.
 
 `syntaxgo_ast` can be used in the following scenarios:
   - Static code analysis.
@@ -36,11 +49,21 @@ Package `syntaxgo_ast` 提供了使用 `go/ast` 包解析、分析和操作、
 
 这个包简化了以下常见任务：
   - 创建和管理 AST（抽象语法树）集合。
+  - 从 fs.FS、内存内容的覆盖层或者 io.Reader 加载文件和包。
+  - 宽容地解析写了一半的文件，保留部分 AST，并将语法错误作为诊断信息。
   - 以编程方式添加或删除导入路径。
+  - 编辑文档注释以及生成文件的头部注释。
+  - 重新生成开始和结束标记注释之间的代码，文件的其余部分保持不变。
+  - 按名称替换或插入函数、方法、类型以及常量。
+  - 连同注释一起删除声明、spec 以及字段，并删除未使用的导入。
+  - 追加、插入、删除以及重新排列结构体字段，必要时拆分声明多个名称的字段，以及设置字段的标签。
+  - 借助 go/types 解析，在包内重命名函数、方法、类型以及字段及其引用。
   - 将 AST 节点格式化为 Go 源代码。
+  - 以原子方式将 AST 包保存回其文件，文件在解析之后被修改过时拒绝保存。
   - 将 AST 结构序列化为文本表示。
+  - 将 AST 无损编码为 JSON 并解码回来，用于缓存以及快照测试。
   - 访问诸如包名之类的元数据。
-  - 打印 AST 结构以便调试或分析。
+  - 打印 AST 结构以便调试或分析，可以树形或 s 表达式输出到任意 writer，并限制深度。
 
 `syntaxgo_ast` 适用于以下场景：
   - 静态代码分析。
@@ -60,6 +83,18 @@ type AstBundle struct {
 	// file is the parsed AST file representation.
 	// file 是已解析的 AST 文件表示。
 	file *ast.File
+
+	// path is the file the bundle was parsed from or last saved to, empty when it was parsed from bytes.
+	// path 是 AST 包解析自或最近保存到的文件，从字节解析时为空。
+	path string
+
+	// hash is the sha256 of the file content when it was parsed or saved, used to detect changes on disk.
+	// hash 是解析或保存时文件内容的 sha256，用于检测磁盘上的文件是否被修改。
+	hash [sha256.Size]byte
+
+	// diagnostics are the syntax errors of a tolerant parse, the file is partial when there are some.
+	// diagnostics 是宽容解析得到的语法错误，存在语法错误时文件不完整。
+	diagnostics scanner.ErrorList
 }
 
 // NewAstBundle creates a new AstBundle.
@@ -96,11 +131,7 @@ func NewAstBundleV2(fset *token.FileSet, data []byte) (*AstBundle, error) {
 func NewAstBundleV3(fset *token.FileSet, path string) (*AstBundle, error) {
 	// Parse the Go source file at the specified path and attach comments to the AST.
 	// 解析指定路径的 Go 源文件，并将注释附加到 AST。
-	astFile, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
-	if err != nil {
-		return nil, erero.Wro(err)
-	}
-	return NewAstBundle(fset, astFile), nil
+	return NewAstBundleV5(fset, path, parser.ParseComments)
 }
 
 // NewAstBundleV4 creates an AstBundle from a file path using a new FileSet.
@@ -112,15 +143,35 @@ func NewAstBundleV4(path string) (*AstBundle, error) {
 }
 
 // NewAstBundleV5 creates an AstBundle by parsing a file with a specific parser mode.
+// The bundle remembers its origin file only when the mode has parser.ParseComments and is not ImportsOnly or PackageClauseOnly,
+// since saving a partial or comment-free file would cut the code or the comments from it.
 // NewAstBundleV5 根据文件路径和特定的解析模式创建 AstBundle 实例。
+// 仅当解析模式包含 parser.ParseComments 且不是 ImportsOnly 或 PackageClauseOnly 时，AST 包才记录其来源文件，
+// 因为保存不完整或不带注释的文件会删掉其中的代码或注释。
 func NewAstBundleV5(fset *token.FileSet, path string, mode parser.Mode) (*AstBundle, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newAstBundleFromFile(fset, path, source, mode)
+}
+
+// newAstBundleFromFile parses the source read from the file at the path with the mode.
+// newAstBundleFromFile 使用该解析模式解析从该路径的文件读取的源代码。
+func newAstBundleFromFile(fset *token.FileSet, path string, source []byte, mode parser.Mode) (*AstBundle, error) {
 	// Parse the file at the given path using the specified parser mode.
 	// 使用指定的解析模式解析给定路径的文件。
-	astFile, err := parser.ParseFile(fset, path, nil, mode)
+	astFile, err := parser.ParseFile(fset, path, source, mode)
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
-	return NewAstBundle(fset, astFile), nil
+	// Remember the origin of the bundle, so that Save can write it back, only when the mode parses the whole file with comments.
+	// 记录 AST 包的来源，以便 Save 将其写回，仅当解析模式解析了带注释的整个文件时才记录。
+	astBundle := NewAstBundle(fset, astFile)
+	if isSaveableMode(mode) {
+		astBundle.setOrigin(path, source)
+	}
+	return astBundle, nil
 }
 
 // NewAstBundleV6 creates an AstBundle from a file path using a new FileSet and specific parser mode.
@@ -0,0 +1,290 @@
+package syntaxgo_ast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// PrintStyle is the layout of the printed AST.
+// PrintStyle 是打印 AST 的布局。
+type PrintStyle string
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	PRINT_STYLE_TREE PrintStyle = "TREE" // One field on each line, drawn as a tree / 每行一个字段，绘制为树
+	PRINT_STYLE_SEXP PrintStyle = "SEXP" // Compact s-expression on one line / 单行的紧凑 s 表达式
+)
+
+// PrintOptions configures printing the AST.
+// PrintOptions 配置打印 AST。
+type PrintOptions struct {
+	style     PrintStyle // Layout of the output / 输出的布局
+	maxDepth  int        // Levels printed below the node, 0 prints all / 节点之下打印的层数，0 打印全部
+	skipZero  bool       // Skip the nil and zero fields / 跳过 nil 以及零值字段
+	positions bool       // Print the positions as file:line:col / 以 file:line:col 打印位置
+	objects   bool       // Print the resolved objects and scopes / 打印已解析的对象以及作用域
+}
+
+// NewPrintOptions creates PrintOptions printing the tree style with positions, skipping the nil and zero fields and the objects.
+// NewPrintOptions 创建以树形布局打印并带有位置的 PrintOptions，跳过 nil 以及零值字段和对象。
+func NewPrintOptions() *PrintOptions {
+	return &PrintOptions{style: PRINT_STYLE_TREE, skipZero: true, positions: true}
+}
+
+// SetStyle sets the layout of the output.
+// SetStyle 设置输出的布局。
+func (options *PrintOptions) SetStyle(style PrintStyle) *PrintOptions {
+	options.style = style
+	return options
+}
+
+// SetMaxDepth sets the levels printed below the node, the deeper nodes and lists are elided as "...", 0 prints all.
+// SetMaxDepth 设置节点之下打印的层数，更深的节点和列表省略为 "..."，0 打印全部。
+func (options *PrintOptions) SetMaxDepth(maxDepth int) *PrintOptions {
+	options.maxDepth = maxDepth
+	return options
+}
+
+// SetSkipZero sets whether to skip the nil, empty and zero fields, like invalid positions and empty names.
+// SetSkipZero 设置是否跳过 nil、空以及零值字段，比如无效的位置和空名称。
+func (options *PrintOptions) SetSkipZero(skipZero bool) *PrintOptions {
+	options.skipZero = skipZero
+	return options
+}
+
+// SetPositions sets whether to print the position fields, as file:line:col.
+// SetPositions 设置是否打印位置字段，格式为 file:line:col。
+func (options *PrintOptions) SetPositions(positions bool) *PrintOptions {
+	options.positions = positions
+	return options
+}
+
+// SetObjects sets whether to print the deprecated objects and scopes, an object is printed as its kind and name.
+// SetObjects 设置是否打印已弃用的对象以及作用域，对象打印为其种类和名称。
+func (options *PrintOptions) SetObjects(objects bool) *PrintOptions {
+	options.objects = objects
+	return options
+}
+
+/*
+Fprint prints the AST node to the writer, the node can be any node, a slice of nodes or the whole file.
+NewPrintOptions is used when the options are nil.
+
+The tree style prints like:
+
+	*ast.Ident
+	├── NamePos: a.go:1:9
+	└── Name: "demo"
+
+The s-expression style prints the same node like:
+
+	(Ident :NamePos a.go:1:9 :Name "demo")
+*/
+/*
+Fprint 将 AST 节点打印到 writer，节点可以是任意节点、节点切片或者整个文件。
+options 为 nil 时使用 NewPrintOptions。
+
+树形布局的打印结果如上面第一个示例，s 表达式布局打印同一个节点的结果如上面第二个示例。
+*/
+func Fprint(w io.Writer, fset *token.FileSet, node any, options *PrintOptions) error {
+	if options == nil {
+		options = NewPrintOptions()
+	}
+	printer := &astPrinter{fset: fset, options: options}
+	item := printer.build("", reflect.ValueOf(node), 0)
+	var output strings.Builder
+	switch options.style {
+	case PRINT_STYLE_TREE:
+		writeTreeItem(&output, item, "", "")
+	case PRINT_STYLE_SEXP:
+		writeSexpItem(&output, item)
+		output.WriteString("\n")
+	default:
+		return erero.Errorf("unknown print style %q", options.style)
+	}
+	if _, err := io.WriteString(w, output.String()); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+// Fprint prints the AST file to the writer, see the function Fprint.
+// Fprint 将 AST 文件打印到 writer，参见函数 Fprint。
+func (ab *AstBundle) Fprint(w io.Writer, options *PrintOptions) error {
+	return Fprint(w, ab.fset, ab.file, options)
+}
+
+// FprintNode prints a node of the AST file to the writer, like a function found by name.
+// FprintNode 将 AST 文件中的一个节点打印到 writer，比如按名称找到的函数。
+func (ab *AstBundle) FprintNode(w io.Writer, node ast.Node, options *PrintOptions) error {
+	return Fprint(w, ab.fset, node, options)
+}
+
+// printItem is a node, a list or a value to print.
+// printItem 是待打印的节点、列表或者值。
+type printItem struct {
+	name     string       // Field name or list index, empty for the root / 字段名称或列表索引，根为空
+	text     string       // Type of the node or the list, or the value / 节点或列表的类型，或者值
+	list     bool         // The item is a list / 该项是列表
+	node     bool         // The item is a node / 该项是节点
+	elided   bool         // Children are cut by the depth limit / 子项被深度限制截断
+	children []*printItem // Fields or elements / 字段或元素
+}
+
+// astPrinter converts the values to print items following the options.
+// astPrinter 按照选项将值转换为待打印的项。
+type astPrinter struct {
+	fset    *token.FileSet
+	options *PrintOptions
+}
+
+var (
+	posType    = reflect.TypeOf(token.NoPos)
+	tokenType  = reflect.TypeOf(token.ILLEGAL)
+	objectType = reflect.TypeOf((*ast.Object)(nil))
+	scopeType  = reflect.TypeOf((*ast.Scope)(nil))
+)
+
+func (printer *astPrinter) build(name string, value reflect.Value, depth int) *printItem {
+	if !value.IsValid() {
+		return &printItem{name: name, text: "nil"}
+	}
+	switch value.Type() {
+	case posType:
+		return &printItem{name: name, text: printer.fset.Position(value.Interface().(token.Pos)).String()}
+	case tokenType:
+		return &printItem{name: name, text: value.Interface().(token.Token).String()}
+	case objectType:
+		if value.IsNil() {
+			return &printItem{name: name, text: "nil"}
+		}
+		object := value.Interface().(*ast.Object)
+		return &printItem{name: name, text: object.Kind.String() + " " + object.Name}
+	case scopeType:
+		if value.IsNil() {
+			return &printItem{name: name, text: "nil"}
+		}
+		names := make([]string, 0, len(value.Interface().(*ast.Scope).Objects))
+		for objectName := range value.Interface().(*ast.Scope).Objects {
+			names = append(names, objectName)
+		}
+		sort.Strings(names)
+		return &printItem{name: name, text: "scope[" + strings.Join(names, " ") + "]"}
+	}
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return &printItem{name: name, text: "nil"}
+		}
+		return printer.build(name, value.Elem(), depth)
+	case reflect.Pointer:
+		if value.IsNil() {
+			return &printItem{name: name, text: "nil"}
+		}
+		if value.Elem().Kind() != reflect.Struct {
+			return printer.build(name, value.Elem(), depth)
+		}
+		item := &printItem{name: name, text: value.Type().String(), node: true}
+		if printer.options.maxDepth > 0 && depth >= printer.options.maxDepth {
+			item.elided = true
+			return item
+		}
+		structValue := value.Elem()
+		for idx := 0; idx < structValue.NumField(); idx++ {
+			field := structValue.Type().Field(idx)
+			fieldValue := structValue.Field(idx)
+			if !field.IsExported() || printer.skipField(field, fieldValue) {
+				continue
+			}
+			item.children = append(item.children, printer.build(field.Name, fieldValue, depth+1))
+		}
+		return item
+	case reflect.Slice, reflect.Array:
+		item := &printItem{name: name, text: fmt.Sprintf("%s (len = %d)", value.Type(), value.Len()), list: true}
+		if value.Len() > 0 && printer.options.maxDepth > 0 && depth >= printer.options.maxDepth {
+			item.elided = true
+			return item
+		}
+		for idx := 0; idx < value.Len(); idx++ {
+			item.children = append(item.children, printer.build(strconv.Itoa(idx), value.Index(idx), depth+1))
+		}
+		return item
+	case reflect.String:
+		return &printItem{name: name, text: strconv.Quote(value.String())}
+	default:
+		return &printItem{name: name, text: fmt.Sprint(value.Interface())}
+	}
+}
+
+// skipField tells whether the options leave out the field.
+// skipField 判断选项是否排除该字段。
+func (printer *astPrinter) skipField(field reflect.StructField, value reflect.Value) bool {
+	if !printer.options.positions && field.Type == posType {
+		return true
+	}
+	if !printer.options.objects && (field.Type == objectType || field.Type == scopeType || field.Name == "Unresolved") {
+		return true
+	}
+	if printer.options.skipZero {
+		if value.IsZero() || ((value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.Len() == 0) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTreeItem(output *strings.Builder, item *printItem, prefix string, childPrefix string) {
+	output.WriteString(prefix)
+	if item.name != "" {
+		output.WriteString(item.name + ": ")
+	}
+	output.WriteString(item.text)
+	if item.elided {
+		output.WriteString(" ...")
+	}
+	output.WriteString("\n")
+	for idx, child := range item.children {
+		if idx == len(item.children)-1 {
+			writeTreeItem(output, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			writeTreeItem(output, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func writeSexpItem(output *strings.Builder, item *printItem) {
+	switch {
+	case item.node:
+		output.WriteString("(" + strings.TrimPrefix(item.text, "*ast."))
+		if item.elided {
+			output.WriteString(" ...")
+		}
+		for _, child := range item.children {
+			output.WriteString(" :" + child.name + " ")
+			writeSexpItem(output, child)
+		}
+		output.WriteString(")")
+	case item.list:
+		output.WriteString("[")
+		if item.elided {
+			output.WriteString("...")
+		}
+		for idx, child := range item.children {
+			if idx > 0 {
+				output.WriteString(" ")
+			}
+			writeSexpItem(output, child)
+		}
+		output.WriteString("]")
+	default:
+		output.WriteString(item.text)
+	}
+}
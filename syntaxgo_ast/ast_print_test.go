@@ -0,0 +1,89 @@
+package syntaxgo_ast
+
+import (
+	"bytes"
+	"go/ast"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+)
+
+const astPrintSource = "package demo\n\nfunc A(x int) int {\n\treturn x + 1\n}\n"
+
+func TestAstBundle_FprintNode(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(astPrintSource)))
+	astFile, _ := astBundle.GetBundle()
+
+	var buf bytes.Buffer
+	done.Done(astBundle.FprintNode(&buf, astFile.Name, NewPrintOptions()))
+	t.Log(buf.String())
+	require.Equal(t, "*ast.Ident\n├── NamePos: 1:9\n└── Name: \"demo\"\n", buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.FprintNode(&buf, astFile.Name, NewPrintOptions().SetSkipZero(false).SetPositions(false)))
+	require.Equal(t, "*ast.Ident\n└── Name: \"demo\"\n", buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.FprintNode(&buf, astFile.Name, NewPrintOptions().SetSkipZero(false).SetObjects(true)))
+	require.Equal(t, "*ast.Ident\n├── NamePos: 1:9\n├── Name: \"demo\"\n└── Obj: nil\n", buf.String())
+}
+
+func TestAstBundle_FprintNode_Sexp(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(astPrintSource)))
+	astFile, _ := astBundle.GetBundle()
+	body := astFile.Decls[0].(*ast.FuncDecl).Body
+
+	var buf bytes.Buffer
+	done.Done(astBundle.FprintNode(&buf, body, NewPrintOptions().SetStyle(PRINT_STYLE_SEXP).SetPositions(false)))
+	t.Log(buf.String())
+	require.Equal(t, `(BlockStmt :List [(ReturnStmt :Results [(BinaryExpr :X (Ident :Name "x") :Op + :Y (BasicLit :Kind INT :Value "1"))])])`+"\n", buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.FprintNode(&buf, body, NewPrintOptions().SetStyle(PRINT_STYLE_SEXP).SetPositions(false).SetMaxDepth(3)))
+	require.Equal(t, "(BlockStmt :List [(ReturnStmt :Results [...])])\n", buf.String())
+}
+
+func TestAstBundle_Fprint(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(astPrintSource)))
+
+	var buf bytes.Buffer
+	done.Done(astBundle.Fprint(&buf, NewPrintOptions().SetMaxDepth(2)))
+	t.Log(buf.String())
+	require.Equal(t, `*ast.File
+├── Package: 1:1
+├── Name: *ast.Ident
+│   ├── NamePos: 1:9
+│   └── Name: "demo"
+├── Decls: []ast.Decl (len = 1)
+│   └── 0: *ast.FuncDecl ...
+├── FileStart: 1:1
+└── FileEnd: 5:3
+`, buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.Fprint(&buf, NewPrintOptions().SetObjects(true)))
+	require.Contains(t, buf.String(), "Scope: scope[A]\n")
+	require.Contains(t, buf.String(), "Obj: var x\n")
+
+	require.Error(t, astBundle.Fprint(&buf, NewPrintOptions().SetStyle("XML")))
+}
+
+func TestFprint_NilOptions(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(astPrintSource)))
+	astFile, fset := astBundle.GetBundle()
+
+	var buf bytes.Buffer
+	done.Done(Fprint(&buf, fset, astFile.Name, nil))
+	require.Equal(t, "*ast.Ident\n├── NamePos: 1:9\n└── Name: \"demo\"\n", buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.FprintNode(&buf, astFile.Name, nil))
+	require.Equal(t, "*ast.Ident\n├── NamePos: 1:9\n└── Name: \"demo\"\n", buf.String())
+
+	buf.Reset()
+	done.Done(astBundle.Fprint(&buf, nil))
+	require.True(t, strings.HasPrefix(buf.String(), "*ast.File\n"))
+}
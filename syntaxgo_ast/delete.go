@@ -0,0 +1,329 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// DeleteFunc deletes the function with its doc comment, returns false when the function does not exist.
+// DeleteFunc 删除函数及其文档注释，当函数不存在时返回 false。
+func (ab *AstBundle) DeleteFunc(funcName string) (bool, error) {
+	return ab.deleteDecl(func(astFile *ast.File) (ast.Node, *ast.CommentGroup, bool) {
+		for _, decl := range astFile.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == funcName {
+				return funcDecl, funcDecl.Doc, true
+			}
+		}
+		return nil, nil, false
+	})
+}
+
+// DeleteMethod deletes the method of the receiver type with its doc comment, returns false when the method does not exist.
+// DeleteMethod 删除接收者类型的方法及其文档注释，当方法不存在时返回 false。
+func (ab *AstBundle) DeleteMethod(receiverName string, methodName string) (bool, error) {
+	return ab.deleteDecl(func(astFile *ast.File) (ast.Node, *ast.CommentGroup, bool) {
+		for _, decl := range astFile.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv != nil && funcDeclName(funcDecl) == receiverName+"."+methodName {
+				return funcDecl, funcDecl.Doc, true
+			}
+		}
+		return nil, nil, false
+	})
+}
+
+// DeleteType deletes the type with its doc and line comments, the group is deleted when it becomes empty.
+// The methods of the type are not deleted.
+// DeleteType 删除类型及其文档注释和行尾注释，当分组变为空时删除整个分组。
+// 该类型的方法不会被删除。
+func (ab *AstBundle) DeleteType(typeName string) (bool, error) {
+	return ab.deleteSpec(token.TYPE, typeName)
+}
+
+// DeleteValue deletes the const or var spec declaring the name, with its doc and line comments.
+// A spec declaring several names like "a, b = 1, 2" only loses the name and its value,
+// the group is deleted when it becomes empty.
+// DeleteValue 删除声明该名称的常量或变量 spec，连同其文档注释和行尾注释。
+// 对于声明多个名称的 spec（比如 "a, b = 1, 2"），只删除该名称及其对应的值，
+// 当分组变为空时删除整个分组。
+func (ab *AstBundle) DeleteValue(name string) (bool, error) {
+	found, err := ab.deleteSpec(token.CONST, name)
+	if err != nil || found {
+		return found, err
+	}
+	return ab.deleteSpec(token.VAR, name)
+}
+
+// DeleteField deletes the field of the struct type with its doc and line comments.
+// For a field declaring several names like "A, B int", only the name is deleted.
+// Embedded fields are named by their type name, like "Base" for "*pkg.Base".
+// DeleteField 删除结构体类型的字段及其文档注释和行尾注释。
+// 对于声明多个名称的字段（比如 "A, B int"），只删除该名称。
+// 嵌入字段以其类型名称命名，比如 "*pkg.Base" 的名称是 "Base"。
+func (ab *AstBundle) DeleteField(structName string, fieldName string) (bool, error) {
+	found := false
+	err := ab.editSource(nil, func(source []byte, astFile *ast.File, _ ast.Node) ([]byte, error) {
+		structType, ok := findStructType(astFile, structName)
+		if !ok {
+			return source, nil
+		}
+		for _, field := range structType.Fields.List {
+			if len(field.Names) == 0 {
+				if GetEmbeddedFieldName(field.Type) == fieldName {
+					found = true
+					start, end := removalRange(source, astFile, field, field.Doc)
+					return formatEditedSource(replaceRange(source, start, end, nil))
+				}
+				continue
+			}
+			for idx, ident := range field.Names {
+				if ident.Name != fieldName {
+					continue
+				}
+				found = true
+				if len(field.Names) == 1 {
+					start, end := removalRange(source, astFile, field, field.Doc)
+					return formatEditedSource(replaceRange(source, start, end, nil))
+				}
+				start, end := removalRangeInList(astFile, identNodes(field.Names), idx)
+				return formatEditedSource(replaceRange(source, start, end, nil))
+			}
+		}
+		return source, nil
+	})
+	return found, err
+}
+
+// DeleteUnusedImports deletes the imports not used in the file, returns the deleted import paths.
+// Blank and dot imports are kept. Since the package name of an import path is guessed,
+// nothing is deleted when the file uses a package qualifier which no import explains.
+// DeleteUnusedImports 删除文件中未使用的导入，返回被删除的导入路径。
+// 空白导入和点导入会被保留。由于导入路径的包名是推测出来的，
+// 当文件使用了无法由任何导入解释的包限定符时，不会删除任何导入。
+func (ab *AstBundle) DeleteUnusedImports() []string {
+	qualifiers := map[string]bool{}
+	ast.Inspect(ab.file, func(node ast.Node) bool {
+		if selectorExpr, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selectorExpr.X.(*ast.Ident); ok && ident.Obj == nil {
+				qualifiers[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	type importItem struct {
+		name string
+		path string
+	}
+	var items []importItem
+	for _, importSpec := range ab.file.Imports {
+		path, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := utils.GuessPackageName(path)
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+		items = append(items, importItem{name: name, path: path})
+		delete(qualifiers, name)
+	}
+	if len(qualifiers) > 0 {
+		return nil
+	}
+
+	var deleted []string
+	for _, item := range items {
+		if item.name == "_" || item.name == "." || utils.UsesQualifier(ab.file, item.name) {
+			continue
+		}
+		explicitName := ""
+		for _, importSpec := range ab.file.Imports {
+			if importSpec.Name != nil && importSpec.Path.Value == strconv.Quote(item.path) {
+				explicitName = importSpec.Name.Name
+			}
+		}
+		if astutil.DeleteNamedImport(ab.fset, ab.file, explicitName, item.path) {
+			deleted = append(deleted, item.path)
+		}
+	}
+	return deleted
+}
+
+// deleteDecl deletes the declaration found in the formatted source, together with its doc comment.
+// deleteDecl 删除在格式化后的源代码中找到的声明，连同其文档注释。
+func (ab *AstBundle) deleteDecl(find func(astFile *ast.File) (ast.Node, *ast.CommentGroup, bool)) (bool, error) {
+	found := false
+	err := ab.editSource(nil, func(source []byte, astFile *ast.File, _ ast.Node) ([]byte, error) {
+		node, doc, ok := find(astFile)
+		if !ok {
+			return source, nil
+		}
+		found = true
+		start, end := removalRange(source, astFile, node, doc)
+		return formatEditedSource(replaceRange(source, start, end, nil))
+	})
+	return found, err
+}
+
+// deleteSpec deletes the type or value spec with the name, the GenDecl is deleted when it has no other spec.
+// deleteSpec 删除具有指定名称的类型或值 spec，当 GenDecl 没有其它 spec 时删除整个 GenDecl。
+func (ab *AstBundle) deleteSpec(tok token.Token, name string) (bool, error) {
+	found := false
+	err := ab.editSource(nil, func(source []byte, astFile *ast.File, _ ast.Node) ([]byte, error) {
+		for _, decl := range astFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != tok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				names := specNames(spec)
+				idx := indexOfName(names, name)
+				if idx < 0 {
+					continue
+				}
+				found = true
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok && len(names) > 1 {
+					if len(valueSpec.Values) > 0 && len(valueSpec.Values) != len(valueSpec.Names) {
+						return nil, erero.Errorf("name %s shares the values with other names, can not delete it alone", name)
+					}
+					// Delete the value first, since it is after the name.
+					// 先删除值，因为它位于名称之后。
+					if len(valueSpec.Values) > 0 {
+						start, end := removalRangeInList(astFile, exprNodes(valueSpec.Values), idx)
+						source = replaceRange(source, start, end, nil)
+					}
+					start, end := removalRangeInList(astFile, identNodes(valueSpec.Names), idx)
+					return formatEditedSource(replaceRange(source, start, end, nil))
+				}
+				if len(genDecl.Specs) == 1 {
+					start, end := removalRange(source, astFile, genDecl, genDecl.Doc)
+					return formatEditedSource(replaceRange(source, start, end, nil))
+				}
+				start, end := removalRange(source, astFile, spec, specDoc(spec))
+				return formatEditedSource(replaceRange(source, start, end, nil))
+			}
+		}
+		return source, nil
+	})
+	return found, err
+}
+
+// removalRange returns the range to delete for the node and its doc comment.
+// When the node takes whole lines, the lines are deleted together with the line comment,
+// otherwise only the node and the following semicolon are deleted.
+// removalRange 返回删除节点及其文档注释时的范围。
+// 当节点占据整行时，连同行尾注释删除这些行，否则只删除节点及其后的分号。
+func removalRange(source []byte, astFile *ast.File, node ast.Node, doc *ast.CommentGroup) (int, int) {
+	start := offsetOf(astFile, node.Pos())
+	if doc != nil {
+		start = offsetOf(astFile, doc.Pos())
+	}
+	end := offsetOf(astFile, node.End())
+	if _, ok := lineIndent(source, start); ok && restOfLineIsComment(source, end) {
+		return lineStart(source, start), lineEnd(source, end)
+	}
+	for end < len(source) && (source[end] == ' ' || source[end] == '\t') {
+		end++
+	}
+	if end < len(source) && source[end] == ';' {
+		end++
+	}
+	return start, end
+}
+
+// removalRangeInList returns the range to delete one node of a comma separated list, together with the comma next to it.
+// removalRangeInList 返回删除逗号分隔列表中某个节点时的范围，连同其旁边的逗号。
+func removalRangeInList(astFile *ast.File, nodes []ast.Node, idx int) (int, int) {
+	if idx+1 < len(nodes) {
+		return offsetOf(astFile, nodes[idx].Pos()), offsetOf(astFile, nodes[idx+1].Pos())
+	}
+	return offsetOf(astFile, nodes[idx-1].End()), offsetOf(astFile, nodes[idx].End())
+}
+
+func identNodes(idents []*ast.Ident) []ast.Node {
+	nodes := make([]ast.Node, 0, len(idents))
+	for _, ident := range idents {
+		nodes = append(nodes, ident)
+	}
+	return nodes
+}
+
+func exprNodes(exprs []ast.Expr) []ast.Node {
+	nodes := make([]ast.Node, 0, len(exprs))
+	for _, expr := range exprs {
+		nodes = append(nodes, expr)
+	}
+	return nodes
+}
+
+// restOfLineIsComment returns whether the rest of the line after the offset is blank or a line comment.
+// restOfLineIsComment 返回偏移量之后的行剩余部分是否为空白或行尾注释。
+func restOfLineIsComment(source []byte, offset int) bool {
+	rest := strings.TrimLeft(string(source[offset:lineEnd(source, offset)]), " \t")
+	return rest == "" || rest == "\n" || strings.HasPrefix(rest, "//")
+}
+
+func findStructType(astFile *ast.File, structName string) (*ast.StructType, bool) {
+	for _, decl := range astFile.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == structName {
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					return structType, ok
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// GetEmbeddedFieldName returns the type name of an embedded field, which is its field name, like "Base" for "*pkg.Base[T]".
+// It returns empty for a type expression which cannot be embedded.
+// GetEmbeddedFieldName 返回嵌入字段的类型名称，也就是其字段名称，比如 "*pkg.Base[T]" 的名称是 "Base"。
+// 对于不能被嵌入的类型表达式返回空。
+func GetEmbeddedFieldName(typeExpr ast.Expr) string {
+	switch item := typeExpr.(type) {
+	case *ast.Ident:
+		return item.Name
+	case *ast.StarExpr:
+		return GetEmbeddedFieldName(item.X)
+	case *ast.SelectorExpr:
+		return item.Sel.Name
+	case *ast.IndexExpr:
+		return GetEmbeddedFieldName(item.X)
+	case *ast.IndexListExpr:
+		return GetEmbeddedFieldName(item.X)
+	}
+	return ""
+}
+
+// specNames returns the type name, or the names of a value spec.
+// specNames 返回类型名称，或者值声明的所有名称。
+func specNames(spec ast.Spec) []string {
+	switch item := spec.(type) {
+	case *ast.TypeSpec:
+		return []string{item.Name.Name}
+	case *ast.ValueSpec:
+		var names []string
+		for _, ident := range item.Names {
+			names = append(names, ident.Name)
+		}
+		return names
+	}
+	return nil
+}
+
+func indexOfName(names []string, name string) int {
+	for idx, item := range names {
+		if item == name {
+			return idx
+		}
+	}
+	return -1
+}
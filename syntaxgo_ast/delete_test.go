@@ -0,0 +1,150 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const deleteSource = `package demo
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limit is the limit.
+const Limit = 10 // max
+
+const (
+	// KindA is the first kind.
+	KindA = "a" // first
+	KindB = "b"
+)
+
+var x, y, z = 1, 2, 3
+
+// Account is an account.
+type Account struct {
+	// Name is the name.
+	Name string // the name
+	A, B int
+	*Base
+}
+
+type Base struct{ ID int; Code string }
+
+// Hello says hello.
+func Hello() string { return fmt.Sprint("hello") }
+
+// Upper returns the upper name.
+func (a *Account) Upper() string { return strings.ToUpper(a.Name) }
+
+func Decode(data []byte, v any) error { return yaml.Unmarshal(data, v) }
+`
+
+func TestAstBundle_DeleteFunc(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+
+	require.True(t, rese.V1(astBundle.DeleteFunc("Hello")))
+	require.False(t, rese.V1(astBundle.DeleteFunc("Hello")))
+	require.False(t, rese.V1(astBundle.DeleteFunc("Upper")))
+	require.Equal(t, []string{"fmt"}, astBundle.DeleteUnusedImports())
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.NotContains(t, source, "Hello")
+	require.NotContains(t, source, `"fmt"`)
+	require.Contains(t, source, "\"strings\"\n")
+	require.Contains(t, source, "}\n\n// Upper returns the upper name.\n")
+}
+
+func TestAstBundle_DeleteMethod(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+
+	require.True(t, rese.V1(astBundle.DeleteMethod("Account", "Upper")))
+	require.False(t, rese.V1(astBundle.DeleteMethod("Base", "Upper")))
+	require.Equal(t, []string{"strings"}, astBundle.DeleteUnusedImports())
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	require.NotContains(t, source, "Upper")
+	require.Contains(t, source, "\"gopkg.in/yaml.v3\"")
+}
+
+func TestAstBundle_DeleteType(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+
+	require.True(t, rese.V1(astBundle.DeleteType("Account")))
+	require.False(t, rese.V1(astBundle.DeleteType("Account")))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.NotContains(t, source, "Account is an account.")
+	require.NotContains(t, source, "type Account")
+	require.Contains(t, source, "var x, y, z = 1, 2, 3\n\ntype Base struct")
+}
+
+func TestAstBundle_DeleteValue(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+
+	require.True(t, rese.V1(astBundle.DeleteValue("Limit")))
+	require.True(t, rese.V1(astBundle.DeleteValue("KindA")))
+	require.True(t, rese.V1(astBundle.DeleteValue("y")))
+	require.False(t, rese.V1(astBundle.DeleteValue("Limit")))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.NotContains(t, source, "Limit")
+	require.NotContains(t, source, "KindA")
+	require.NotContains(t, source, "first")
+	require.Contains(t, source, "const (\n\tKindB = \"b\"\n)\n")
+	require.Contains(t, source, "var x, z = 1, 3\n")
+
+	require.True(t, rese.V1(astBundle.DeleteValue("KindB")))
+	source = string(rese.V1(astBundle.FormatSource()))
+	require.NotContains(t, source, "const")
+}
+
+func TestAstBundle_DeleteValue_SharedValues(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte("package demo\n\nvar a, b = f()\n\nfunc f() (int, int) { return 1, 2 }\n")))
+
+	_, err := astBundle.DeleteValue("a")
+	require.Error(t, err)
+}
+
+func TestAstBundle_DeleteField(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+
+	require.True(t, rese.V1(astBundle.DeleteField("Account", "Name")))
+	require.True(t, rese.V1(astBundle.DeleteField("Account", "A")))
+	require.True(t, rese.V1(astBundle.DeleteField("Account", "Base")))
+	require.True(t, rese.V1(astBundle.DeleteField("Base", "ID")))
+	require.False(t, rese.V1(astBundle.DeleteField("Account", "Name")))
+	require.False(t, rese.V1(astBundle.DeleteField("Other", "Name")))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "type Account struct {\n\tB int\n}\n")
+	require.Contains(t, source, "type Base struct {\n\tCode string\n}\n")
+	require.NotContains(t, source, "the name")
+}
+
+func TestAstBundle_DeleteUnusedImports(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(deleteSource)))
+	require.Empty(t, astBundle.DeleteUnusedImports())
+
+	// An unknown qualifier may belong to an import whose package name is guessed wrong, so nothing is deleted.
+	// 未知的限定符可能属于某个包名被推测错误的导入，因此不会删除任何导入。
+	astBundle = rese.P1(NewAstBundleV1([]byte("package demo\n\nimport (\n\t\"fmt\"\n\t\"example.com/x/go-unknown\"\n)\n\nvar v = other.Value\n")))
+	require.Empty(t, astBundle.DeleteUnusedImports())
+}
+
+func TestGetEmbeddedFieldName(t *testing.T) {
+	require.Equal(t, "Base", GetEmbeddedFieldName(&ast.StarExpr{X: &ast.SelectorExpr{X: ast.NewIdent("pkg"), Sel: ast.NewIdent("Base")}}))
+	require.Equal(t, "Node", GetEmbeddedFieldName(&ast.IndexExpr{X: ast.NewIdent("Node"), Index: ast.NewIdent("T")}))
+	require.Empty(t, GetEmbeddedFieldName(&ast.ArrayType{Elt: ast.NewIdent("int")}))
+}
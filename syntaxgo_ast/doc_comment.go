@@ -0,0 +1,236 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+)
+
+// generatedHeaderRegexp matches the generated file header defined by the Go convention.
+// generatedHeaderRegexp 匹配 Go 约定中的生成文件头部注释。
+var generatedHeaderRegexp = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// SetDocComment sets the doc comment of the node in the source code, the existing doc comment is replaced.
+// The node can be *ast.File (the package clause), *ast.FuncDecl, *ast.GenDecl, *ast.TypeSpec, *ast.ValueSpec or *ast.Field,
+// and the astFile must be parsed from the source code. Lines of the text get the "// " prefix unless they start with "//".
+// The directive lines of the existing doc comment, like "//go:embed x.txt", are kept after the new text.
+// SetDocComment 设置源代码中节点的文档注释，已有的文档注释会被替换。
+// 节点可以是 *ast.File（包声明）、*ast.FuncDecl、*ast.GenDecl、*ast.TypeSpec、*ast.ValueSpec 或 *ast.Field，
+// 并且 astFile 必须是从该源代码解析得到的。文本的每一行都会加上 "// " 前缀，已经以 "//" 开头的行除外。
+// 已有文档注释中的指令行（比如 "//go:embed x.txt"）会保留在新文本之后。
+func SetDocComment(source []byte, astFile *ast.File, node ast.Node, text string) ([]byte, error) {
+	target, doc, err := findDocTarget(astFile, node)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if doc != nil {
+		start, end := offsetOf(astFile, doc.Pos()), offsetOf(astFile, doc.End())
+		indent, _ := lineIndent(source, start)
+		lines := []string{formatCommentLines(text, indent)}
+		for _, directive := range directiveLines(doc) {
+			if !slices.Contains(strings.Split(text, "\n"), directive) {
+				lines = append(lines, directive)
+			}
+		}
+		return replaceRange(source, start, end, []byte(strings.Join(lines, "\n"+indent))), nil
+	}
+	offset := offsetOf(astFile, target.Pos())
+	indent, ok := lineIndent(source, offset)
+	if !ok {
+		return nil, erero.Errorf("node %T does not start a line, so it can not get a doc comment", node)
+	}
+	start := lineStart(source, offset)
+	return replaceRange(source, start, start, []byte(indent+formatCommentLines(text, indent)+"\n")), nil
+}
+
+// AppendDocComment appends a paragraph to the doc comment of the node, like "Deprecated: use X instead.".
+// When the node has no doc comment, the paragraph becomes the doc comment.
+// AppendDocComment 在节点的文档注释后追加一个段落，比如 "Deprecated: use X instead."。
+// 当节点没有文档注释时，该段落成为文档注释。
+func AppendDocComment(source []byte, astFile *ast.File, node ast.Node, text string) ([]byte, error) {
+	_, doc, err := findDocTarget(astFile, node)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if doc == nil {
+		return SetDocComment(source, astFile, node, text)
+	}
+	// The directive lines are kept after the text by SetDocComment.
+	// 指令行由 SetDocComment 保留在文本之后。
+	var lines []string
+	for _, comment := range doc.List {
+		if _, _, _, ok := utils.CutDirective(comment.Text); !ok {
+			lines = append(lines, comment.Text)
+		}
+	}
+	// Drop the empty lines left before the directives.
+	// 去掉指令之前遗留的空行。
+	for len(lines) > 0 && lines[len(lines)-1] == "//" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return SetDocComment(source, astFile, node, text)
+	}
+	lines = append(lines, "//", strings.TrimRight(text, "\n"))
+	return SetDocComment(source, astFile, node, strings.Join(lines, "\n"))
+}
+
+// DeleteDocComment deletes the doc comment of the node in the source code, together with its lines.
+// The directive lines like "//go:embed x.txt" are kept.
+// DeleteDocComment 删除源代码中节点的文档注释，连同其所在的行一起删除。
+// 类似 "//go:embed x.txt" 的指令行会被保留。
+func DeleteDocComment(source []byte, astFile *ast.File, node ast.Node) ([]byte, error) {
+	_, doc, err := findDocTarget(astFile, node)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if doc == nil {
+		return source, nil
+	}
+	if directives := directiveLines(doc); len(directives) > 0 {
+		start, end := offsetOf(astFile, doc.Pos()), offsetOf(astFile, doc.End())
+		indent, _ := lineIndent(source, start)
+		return replaceRange(source, start, end, []byte(strings.Join(directives, "\n"+indent))), nil
+	}
+	start := lineStart(source, offsetOf(astFile, doc.Pos()))
+	end := lineEnd(source, offsetOf(astFile, doc.End()))
+	return replaceRange(source, start, end, nil), nil
+}
+
+// SetGeneratedHeader inserts or updates the header "// Code generated by <tool>. DO NOT EDIT." before the package clause.
+// An existing header matching the Go convention is replaced, otherwise the header is inserted at the top of the file.
+// SetGeneratedHeader 在包声明之前插入或更新头部注释 "// Code generated by <tool>. DO NOT EDIT."。
+// 已有的符合 Go 约定的头部注释会被替换，否则在文件顶部插入该头部注释。
+func SetGeneratedHeader(source []byte, tool string) ([]byte, error) {
+	if tool == "" {
+		return nil, erero.New("tool name is empty")
+	}
+	astFile, err := parser.ParseFile(token.NewFileSet(), "", source, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	header := "// Code generated by " + tool + ". DO NOT EDIT."
+	headerRegion := source[:offsetOf(astFile, astFile.Package)]
+	if loc := generatedHeaderRegexp.FindIndex(headerRegion); loc != nil {
+		return replaceRange(source, loc[0], loc[1], []byte(header)), nil
+	}
+	return replaceRange(source, 0, 0, []byte(header+"\n\n")), nil
+}
+
+// SetDocComment sets the doc comment of the node in the bundle, see SetDocComment.
+// The bundle is reparsed, so nodes found before the call are no longer part of the bundle.
+// SetDocComment 设置 AST 包中节点的文档注释，参见 SetDocComment。
+// AST 包会被重新解析，因此调用之前找到的节点不再属于该 AST 包。
+func (ab *AstBundle) SetDocComment(node ast.Node, text string) error {
+	return ab.editSource(node, func(source []byte, astFile *ast.File, node ast.Node) ([]byte, error) {
+		return SetDocComment(source, astFile, node, text)
+	})
+}
+
+// AppendDocComment appends a paragraph to the doc comment of the node in the bundle, see AppendDocComment.
+// AppendDocComment 在 AST 包中节点的文档注释后追加一个段落，参见 AppendDocComment。
+func (ab *AstBundle) AppendDocComment(node ast.Node, text string) error {
+	return ab.editSource(node, func(source []byte, astFile *ast.File, node ast.Node) ([]byte, error) {
+		return AppendDocComment(source, astFile, node, text)
+	})
+}
+
+// DeleteDocComment deletes the doc comment of the node in the bundle, see DeleteDocComment.
+// DeleteDocComment 删除 AST 包中节点的文档注释，参见 DeleteDocComment。
+func (ab *AstBundle) DeleteDocComment(node ast.Node) error {
+	return ab.editSource(node, func(source []byte, astFile *ast.File, node ast.Node) ([]byte, error) {
+		return DeleteDocComment(source, astFile, node)
+	})
+}
+
+// SetGeneratedHeader inserts or updates the generated file header of the bundle, see SetGeneratedHeader.
+// SetGeneratedHeader 插入或更新 AST 包的生成文件头部注释，参见 SetGeneratedHeader。
+func (ab *AstBundle) SetGeneratedHeader(tool string) error {
+	return ab.editSource(nil, func(source []byte, _ *ast.File, _ ast.Node) ([]byte, error) {
+		return SetGeneratedHeader(source, tool)
+	})
+}
+
+// findDocTarget returns the node owning the doc comment and the doc comment.
+// A spec of an unparenthesized GenDecl uses the GenDecl, like "type A struct{}", since the doc is attached to the GenDecl.
+// findDocTarget 返回拥有文档注释的节点以及该文档注释。
+// 没有括号的 GenDecl 中的声明（比如 "type A struct{}"）使用 GenDecl，因为文档注释挂在 GenDecl 上。
+func findDocTarget(astFile *ast.File, node ast.Node) (ast.Node, *ast.CommentGroup, error) {
+	switch item := node.(type) {
+	case *ast.File:
+		return &packageClause{pos: item.Package}, item.Doc, nil
+	case *ast.FuncDecl:
+		return item, item.Doc, nil
+	case *ast.GenDecl:
+		return item, item.Doc, nil
+	case *ast.TypeSpec:
+		if genDecl, ok := findParenlessGenDecl(astFile, item); ok {
+			return genDecl, genDecl.Doc, nil
+		}
+		return item, item.Doc, nil
+	case *ast.ValueSpec:
+		if genDecl, ok := findParenlessGenDecl(astFile, item); ok {
+			return genDecl, genDecl.Doc, nil
+		}
+		return item, item.Doc, nil
+	case *ast.Field:
+		return item, item.Doc, nil
+	default:
+		return nil, nil, erero.Errorf("node %T can not have a doc comment", node)
+	}
+}
+
+// findParenlessGenDecl finds the GenDecl declaring the spec without parentheses.
+// findParenlessGenDecl 查找不带括号地声明该 spec 的 GenDecl。
+func findParenlessGenDecl(astFile *ast.File, spec ast.Spec) (*ast.GenDecl, bool) {
+	for _, decl := range astFile.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Lparen == token.NoPos && len(genDecl.Specs) == 1 && genDecl.Specs[0] == spec {
+			return genDecl, true
+		}
+	}
+	return nil, false
+}
+
+// packageClause is the node of the "package" keyword, the file doc comment is attached to it.
+// packageClause 是 "package" 关键字对应的节点，文件的文档注释挂在它上面。
+type packageClause struct {
+	pos token.Pos
+}
+
+func (clause *packageClause) Pos() token.Pos { return clause.pos }
+func (clause *packageClause) End() token.Pos { return clause.pos + token.Pos(len("package")) }
+
+// directiveLines returns the directive lines of the doc comment, like "//go:embed x.txt" and "//nolint:errcheck".
+// directiveLines 返回文档注释中的指令行，比如 "//go:embed x.txt" 和 "//nolint:errcheck"。
+func directiveLines(doc *ast.CommentGroup) []string {
+	var lines []string
+	for _, comment := range doc.List {
+		if _, _, _, ok := utils.CutDirective(comment.Text); ok {
+			lines = append(lines, comment.Text)
+		}
+	}
+	return lines
+}
+
+// formatCommentLines formats the text into comment lines joined with newline and indent.
+// formatCommentLines 将文本格式化为用换行和缩进连接的注释行。
+func formatCommentLines(text string, indent string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "//"):
+			lines = append(lines, line)
+		case strings.TrimSpace(line) == "":
+			lines = append(lines, "//")
+		default:
+			lines = append(lines, "// "+line)
+		}
+	}
+	return strings.Join(lines, "\n"+indent)
+}
@@ -0,0 +1,127 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const docCommentSource = `package demo
+
+// Account is an account.
+type Account struct {
+	// Name is the name.
+	Name string
+	Age  int
+}
+
+type (
+	Role string
+)
+
+func (a *Account) Login() {}
+`
+
+func TestSetDocComment(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(docCommentSource)))
+	astFile := astBundle.file
+
+	typeSpec := astFile.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	source := rese.V1(SetDocComment([]byte(docCommentSource), astFile, typeSpec, "Account is a user account.\n\nIt has a name."))
+	t.Log(string(source))
+	require.Contains(t, string(source), "// Account is a user account.\n//\n// It has a name.\ntype Account struct {")
+	require.NotContains(t, string(source), "Account is an account.")
+
+	field := typeSpec.Type.(*ast.StructType).Fields.List[1]
+	source = rese.V1(SetDocComment([]byte(docCommentSource), astFile, field, "Age is the age."))
+	require.Contains(t, string(source), "\tName string\n\t// Age is the age.\n\tAge  int\n")
+
+	roleSpec := astFile.Decls[1].(*ast.GenDecl).Specs[0]
+	source = rese.V1(SetDocComment([]byte(docCommentSource), astFile, roleSpec, "Role is a role."))
+	require.Contains(t, string(source), "type (\n\t// Role is a role.\n\tRole string\n)")
+
+	source = rese.V1(SetDocComment([]byte(docCommentSource), astFile, astFile, "Package demo is a demo."))
+	require.Contains(t, string(source), "// Package demo is a demo.\npackage demo\n")
+}
+
+func TestAppendDocComment(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(docCommentSource)))
+	astFile := astBundle.file
+
+	source := rese.V1(AppendDocComment([]byte(docCommentSource), astFile, astFile.Decls[0], "Deprecated: use User instead."))
+	require.Contains(t, string(source), "// Account is an account.\n//\n// Deprecated: use User instead.\ntype Account struct {")
+
+	source = rese.V1(AppendDocComment([]byte(docCommentSource), astFile, astFile.Decls[2], "Deprecated: use SignIn instead."))
+	require.Contains(t, string(source), "// Deprecated: use SignIn instead.\nfunc (a *Account) Login() {}")
+}
+
+func TestDeleteDocComment(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(docCommentSource)))
+	astFile := astBundle.file
+
+	source := rese.V1(DeleteDocComment([]byte(docCommentSource), astFile, astFile.Decls[0]))
+	require.Contains(t, string(source), "package demo\n\ntype Account struct {")
+
+	field := astFile.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List[0]
+	source = rese.V1(DeleteDocComment([]byte(docCommentSource), astFile, field))
+	require.Contains(t, string(source), "type Account struct {\n\tName string\n")
+
+	source = rese.V1(DeleteDocComment([]byte(docCommentSource), astFile, astFile.Decls[2]))
+	require.Equal(t, docCommentSource, string(source))
+}
+
+func TestSetDocComment_KeepDirectives(t *testing.T) {
+	const source = "package demo\n\nimport _ \"embed\"\n\n// X is the text.\n//\n//go:embed x.txt\nvar X string\n\n//go:generate stringer -type=Kind\n//nolint:unused\ntype Kind int\n"
+	astBundle := rese.P1(NewAstBundleV1([]byte(source)))
+	astFile := astBundle.file
+
+	newSource := string(rese.V1(SetDocComment([]byte(source), astFile, astFile.Decls[1], "X is the new text.")))
+	t.Log(newSource)
+	require.Contains(t, newSource, "\n// X is the new text.\n//go:embed x.txt\nvar X string\n")
+
+	newSource = string(rese.V1(SetDocComment([]byte(source), astFile, astFile.Decls[2], "Kind is a kind.")))
+	require.Contains(t, newSource, "\n// Kind is a kind.\n//go:generate stringer -type=Kind\n//nolint:unused\ntype Kind int\n")
+
+	newSource = string(rese.V1(AppendDocComment([]byte(source), astFile, astFile.Decls[1], "Deprecated: use Y.")))
+	require.Contains(t, newSource, "\n// X is the text.\n//\n// Deprecated: use Y.\n//go:embed x.txt\nvar X string\n")
+
+	newSource = string(rese.V1(AppendDocComment([]byte(source), astFile, astFile.Decls[2], "Deprecated: use Type.")))
+	require.Contains(t, newSource, "\n// Deprecated: use Type.\n//go:generate stringer -type=Kind\n//nolint:unused\ntype Kind int\n")
+
+	newSource = string(rese.V1(DeleteDocComment([]byte(source), astFile, astFile.Decls[1])))
+	require.Contains(t, newSource, "\n\n//go:embed x.txt\nvar X string\n")
+	require.NotContains(t, newSource, "X is the text.")
+}
+
+func TestSetGeneratedHeader(t *testing.T) {
+	source := rese.V1(SetGeneratedHeader([]byte(docCommentSource), "demo-gen"))
+	require.Equal(t, "// Code generated by demo-gen. DO NOT EDIT.\n\n"+docCommentSource, string(source))
+
+	source = rese.V1(SetGeneratedHeader(source, "demo-gen v2"))
+	require.Equal(t, "// Code generated by demo-gen v2. DO NOT EDIT.\n\n"+docCommentSource, string(source))
+
+	_, err := SetGeneratedHeader(source, "")
+	require.Error(t, err)
+}
+
+func TestAstBundle_SetDocComment(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(docCommentSource)))
+
+	require.NoError(t, astBundle.SetDocComment(astBundle.file.Decls[2], "Login logs in."))
+	require.NoError(t, astBundle.AppendDocComment(astBundle.file.Decls[2], "Deprecated: use SignIn instead."))
+	require.NoError(t, astBundle.DeleteDocComment(astBundle.file.Decls[0]))
+	require.NoError(t, astBundle.SetGeneratedHeader("demo-gen"))
+
+	// The doc comment is attached to the reparsed node, so it survives formatting.
+	// 文档注释挂在重新解析后的节点上，因此格式化之后依然保留。
+	funcDecl := astBundle.file.Decls[2].(*ast.FuncDecl)
+	require.Equal(t, "Login logs in.\n\nDeprecated: use SignIn instead.\n", funcDecl.Doc.Text())
+	require.True(t, ast.IsGenerated(astBundle.file))
+
+	source := rese.V1(astBundle.FormatSource())
+	t.Log(string(source))
+	require.Contains(t, string(source), "// Login logs in.\n//\n// Deprecated: use SignIn instead.\nfunc (a *Account) Login() {}")
+	require.NotContains(t, string(source), "Account is an account.")
+}
@@ -0,0 +1,250 @@
+package syntaxgo_ast
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// Loader reads and parses the sources from the OS file system or an fs.FS, with an overlay of in-memory contents.
+// The overlay replaces files or adds files that do not exist, like the unsaved buffers of an editor.
+// Loader 从操作系统文件系统或者 fs.FS 读取并解析源代码，并带有内存内容的覆盖层。
+// 覆盖层替换已有文件或者添加不存在的文件，比如编辑器中未保存的缓冲区。
+type Loader struct {
+	fsys     fs.FS             // File system, nil for the OS file system / 文件系统，nil 表示操作系统文件系统
+	overlay  map[string][]byte // Contents by the cleaned paths / 按清理后路径索引的内容
+	mode     parser.Mode       // Parser mode / 解析模式
+	tolerant bool              // Keep the partial files with syntax errors / 保留带有语法错误的部分文件
+}
+
+// NewLoader creates a Loader reading the OS file system and parsing the comments.
+// NewLoader 创建读取操作系统文件系统并解析注释的 Loader。
+func NewLoader() *Loader {
+	return &Loader{overlay: map[string][]byte{}, mode: parser.ParseComments}
+}
+
+// SetFS sets the file system, like an embed.FS or an fstest.MapFS, the paths are then slash-separated fs.FS paths.
+// SetFS 设置文件系统，比如 embed.FS 或者 fstest.MapFS，之后的路径是以斜杠分隔的 fs.FS 路径。
+func (loader *Loader) SetFS(fsys fs.FS) *Loader {
+	loader.fsys = fsys
+	return loader
+}
+
+// SetOverlay sets the in-memory contents by path, they are read in place of the files.
+// SetOverlay 设置按路径索引的内存内容，读取时代替对应的文件。
+func (loader *Loader) SetOverlay(overlay map[string][]byte) *Loader {
+	loader.overlay = map[string][]byte{}
+	for name, content := range overlay {
+		loader.overlay[loader.clean(name)] = content
+	}
+	return loader
+}
+
+// SetMode sets the parser mode.
+// SetMode 设置解析模式。
+func (loader *Loader) SetMode(mode parser.Mode) *Loader {
+	loader.mode = mode
+	return loader
+}
+
+// SetTolerant sets whether to keep the partial files with syntax errors, see NewAstBundleTolerant.
+// SetTolerant 设置是否保留带有语法错误的部分文件，参见 NewAstBundleTolerant。
+func (loader *Loader) SetTolerant(tolerant bool) *Loader {
+	loader.tolerant = tolerant
+	return loader
+}
+
+// ReadFile reads the overlay content of the path, or the file in the file system.
+// ReadFile 读取该路径的覆盖层内容，或者文件系统中的文件。
+func (loader *Loader) ReadFile(name string) ([]byte, error) {
+	if content, ok := loader.overlay[loader.clean(name)]; ok {
+		return content, nil
+	}
+	var source []byte
+	var err error
+	if loader.fsys != nil {
+		source, err = fs.ReadFile(loader.fsys, loader.clean(name))
+	} else {
+		source, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return source, nil
+}
+
+// LoadFile parses the file, the positions use the path as the file name.
+// Only a file read from the OS file system without overlay remembers its origin, so Save works on it,
+// and only when the mode parses the whole file with comments, see NewAstBundleV5.
+// Other bundles have no origin file, FormatSource gives their source and SaveTo writes them.
+// LoadFile 解析该文件，位置使用该路径作为文件名。
+// 只有从操作系统文件系统读取且没有覆盖层的文件会记录其来源，从而可以对其使用 Save，
+// 并且仅当解析模式解析了带注释的整个文件时才记录，参见 NewAstBundleV5。
+// 其他 AST 包没有来源文件，FormatSource 给出其源代码，SaveTo 将其写入文件。
+func (loader *Loader) LoadFile(fset *token.FileSet, name string) (*AstBundle, error) {
+	source, err := loader.ReadFile(name)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var astFile *ast.File
+	var diagnostics scanner.ErrorList
+	if loader.tolerant {
+		astFile, diagnostics, err = parseTolerant(fset, name, source, loader.mode)
+	} else {
+		astFile, err = parser.ParseFile(fset, name, source, loader.mode)
+	}
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astBundle := NewAstBundle(fset, astFile)
+	astBundle.diagnostics = diagnostics
+	if _, ok := loader.overlay[loader.clean(name)]; !ok && loader.fsys == nil && isSaveableMode(loader.mode) {
+		astBundle.setOrigin(name, source)
+	}
+	return astBundle, nil
+}
+
+// LoadPackage parses the Go files in the directory into one FileSet, together with the overlay files in it.
+// Test files, and files starting with "." or "_", are skipped, and the files must have the same package name.
+// LoadPackage 将目录中的 Go 文件连同其中的覆盖层文件解析到同一个 FileSet 中。
+// 跳过测试文件以及以 "." 或 "_" 开头的文件，这些文件必须有相同的包名。
+func (loader *Loader) LoadPackage(fset *token.FileSet, dir string) ([]*AstBundle, error) {
+	names, err := loader.listGoFiles(dir)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if len(names) == 0 {
+		return nil, erero.Errorf("no Go files in %s", dir)
+	}
+	astBundles := make([]*AstBundle, 0, len(names))
+	for _, name := range names {
+		astBundle, err := loader.LoadFile(fset, name)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		if len(astBundles) > 0 && astBundle.GetPackageName() != astBundles[0].GetPackageName() {
+			return nil, erero.Errorf("package %s in %s is not %s", astBundle.GetPackageName(), name, astBundles[0].GetPackageName())
+		}
+		astBundles = append(astBundles, astBundle)
+	}
+	return astBundles, nil
+}
+
+// listGoFiles returns the paths of the Go files in the directory and in the overlay, in sorted order.
+// listGoFiles 按排序后的顺序返回目录中以及覆盖层中的 Go 文件路径。
+func (loader *Loader) listGoFiles(dir string) ([]string, error) {
+	dir = loader.clean(dir)
+	var entries []fs.DirEntry
+	var err error
+	if loader.fsys != nil {
+		entries, err = fs.ReadDir(loader.fsys, dir)
+	} else {
+		entries, err = os.ReadDir(dir)
+	}
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && loader.hasOverlayIn(dir)) {
+		return nil, erero.Wro(err)
+	}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() && isPackageGoFile(entry.Name()) {
+			seen[loader.join(dir, entry.Name())] = true
+		}
+	}
+	for name := range loader.overlay {
+		if loader.dir(name) == dir && isPackageGoFile(loader.base(name)) {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (loader *Loader) hasOverlayIn(dir string) bool {
+	for name := range loader.overlay {
+		if loader.dir(name) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// clean cleans the path in the convention of the file system, slash-separated for an fs.FS.
+// clean 按文件系统的约定清理路径，fs.FS 使用斜杠分隔。
+func (loader *Loader) clean(name string) string {
+	if loader.fsys != nil {
+		return path.Clean(filepath.ToSlash(name))
+	}
+	return filepath.Clean(name)
+}
+
+func (loader *Loader) join(dir string, name string) string {
+	if loader.fsys != nil {
+		return path.Join(dir, name)
+	}
+	return filepath.Join(dir, name)
+}
+
+func (loader *Loader) dir(name string) string {
+	if loader.fsys != nil {
+		return path.Dir(name)
+	}
+	return filepath.Dir(name)
+}
+
+func (loader *Loader) base(name string) string {
+	if loader.fsys != nil {
+		return path.Base(name)
+	}
+	return filepath.Base(name)
+}
+
+// isPackageGoFile tells whether the file is a Go file of the package, the go tool ignores the files starting with "." or "_".
+// isPackageGoFile 判断该文件是否是包的 Go 文件，go 工具会忽略以 "." 或 "_" 开头的文件。
+func isPackageGoFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "_")
+}
+
+// NewAstBundleFromFS parses the file in the file system, like an embed.FS or an fstest.MapFS, see Loader.
+// NewAstBundleFromFS 解析文件系统（比如 embed.FS 或者 fstest.MapFS）中的文件，参见 Loader。
+func NewAstBundleFromFS(fsys fs.FS, name string) (*AstBundle, error) {
+	return NewLoader().SetFS(fsys).LoadFile(token.NewFileSet(), name)
+}
+
+// NewAstBundleFromReader parses the source read from the reader, the positions use the name as the file name.
+// The bundle has no origin file, SaveTo writes it.
+// NewAstBundleFromReader 解析从 reader 读取的源代码，位置使用该名称作为文件名。
+// 该 AST 包没有来源文件，使用 SaveTo 将其写入文件。
+func NewAstBundleFromReader(fset *token.FileSet, name string, reader io.Reader) (*AstBundle, error) {
+	source, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astFile, err := parser.ParseFile(fset, name, source, parser.ParseComments)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return NewAstBundle(fset, astFile), nil
+}
+
+// NewAstBundleFromSource parses the source already read from the file at the path, so the bundle has exactly that source.
+// The bundle remembers the file as its origin, Save writes it back and refuses when the file no longer has that source.
+// NewAstBundleFromSource 解析已经从该路径的文件读取的源代码，因此 AST 包恰好对应该源代码。
+// AST 包将该文件记录为其来源，Save 将其写回，并在文件不再是该源代码时拒绝写入。
+func NewAstBundleFromSource(fset *token.FileSet, path string, source []byte) (*AstBundle, error) {
+	return newAstBundleFromFile(fset, path, source, parser.ParseComments)
+}
@@ -0,0 +1,130 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+)
+
+func newTestMapFS() fstest.MapFS {
+	return fstest.MapFS{
+		"demo/a.go":      {Data: []byte("package demo\n\nfunc A() {}\n")},
+		"demo/b.go":      {Data: []byte("package demo\n\nfunc B() {}\n")},
+		"demo/a_test.go": {Data: []byte("package demo_test\n")},
+		"demo/_x.go":     {Data: []byte("package ignored\n")},
+		"demo/sub/c.go":  {Data: []byte("package sub\n")},
+	}
+}
+
+// funcNames returns the file name and the first function name of each bundle.
+// funcNames 返回每个 AST 包的文件名以及第一个函数的名称。
+func funcNames(astBundles []*AstBundle) []string {
+	var names []string
+	for _, astBundle := range astBundles {
+		astFile, fset := astBundle.GetBundle()
+		names = append(names, fset.Position(astFile.Pos()).Filename+" "+astFile.Decls[0].(*ast.FuncDecl).Name.Name)
+	}
+	return names
+}
+
+func TestNewAstBundleFromFS(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleFromFS(newTestMapFS(), "demo/a.go"))
+	require.Equal(t, "demo", astBundle.GetPackageName())
+	require.Empty(t, astBundle.GetPath())
+
+	astFile, fset := astBundle.GetBundle()
+	require.Equal(t, "demo/a.go:3:1", fset.Position(astFile.Decls[0].Pos()).String())
+	require.Error(t, astBundle.Save())
+
+	_, err := NewAstBundleFromFS(newTestMapFS(), "demo/missing.go")
+	require.Error(t, err)
+}
+
+func TestNewAstBundleFromReader(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleFromReader(token.NewFileSet(), "buffer.go", strings.NewReader("package demo\n")))
+	require.Equal(t, "demo", astBundle.GetPackageName())
+	require.Empty(t, astBundle.GetPath())
+
+	_, err := NewAstBundleFromReader(token.NewFileSet(), "buffer.go", strings.NewReader("package\n"))
+	require.Error(t, err)
+}
+
+func TestNewAstBundleFromSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	source := []byte("package demo\n")
+	done.Done(os.WriteFile(path, source, 0644))
+
+	astBundle := rese.P1(NewAstBundleFromSource(token.NewFileSet(), path, source))
+	require.Equal(t, path, astBundle.GetPath())
+	require.True(t, astBundle.AddImport("strings"))
+
+	// The file changed after the source was read, so Save refuses to overwrite it.
+	// 读取源代码之后文件被修改了，因此 Save 拒绝覆盖它。
+	done.Done(os.WriteFile(path, []byte("package demo\n\nfunc A() {}\n"), 0644))
+	require.Error(t, astBundle.Save())
+
+	astBundle = rese.P1(NewAstBundleFromSource(token.NewFileSet(), path, rese.V1(os.ReadFile(path))))
+	require.True(t, astBundle.AddImport("strings"))
+	require.NoError(t, astBundle.Save())
+	require.Contains(t, string(rese.V1(os.ReadFile(path))), "import \"strings\"")
+
+	_, err := NewAstBundleFromSource(token.NewFileSet(), path, []byte("package\n"))
+	require.Error(t, err)
+}
+
+func TestLoader_LoadPackage_FS(t *testing.T) {
+	loader := NewLoader().SetFS(newTestMapFS()).SetOverlay(map[string][]byte{
+		"demo/b.go":   []byte("package demo\n\nfunc B2() {}\n"),
+		"./demo/n.go": []byte("package demo\n\nfunc N() {}\n"),
+	})
+
+	astBundles := rese.V1(loader.LoadPackage(token.NewFileSet(), "demo/"))
+	require.Equal(t, []string{"demo/a.go A", "demo/b.go B2", "demo/n.go N"}, funcNames(astBundles))
+
+	// A directory only in the overlay is fine.
+	// 只存在于覆盖层中的目录也可以加载。
+	loader.SetOverlay(map[string][]byte{"new/x.go": []byte("package x\n\nfunc X() {}\n")})
+	require.Equal(t, []string{"new/x.go X"}, funcNames(rese.V1(loader.LoadPackage(token.NewFileSet(), "new"))))
+
+	_, err := loader.LoadPackage(token.NewFileSet(), "missing")
+	require.Error(t, err)
+}
+
+func TestLoader_LoadPackage_MixedPackages(t *testing.T) {
+	loader := NewLoader().SetFS(newTestMapFS()).SetOverlay(map[string][]byte{
+		"demo/z.go": []byte("package other\n"),
+	})
+
+	_, err := loader.LoadPackage(token.NewFileSet(), "demo")
+	require.Error(t, err)
+}
+
+func TestLoader_LoadFile_Overlay(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n\nfunc A() {}\n"), 0644))
+
+	// The file on disk remembers its origin, so Save works on it.
+	// 磁盘上的文件记录其来源，因此可以对其使用 Save。
+	astBundle := rese.P1(NewLoader().LoadFile(token.NewFileSet(), path))
+	require.Equal(t, path, astBundle.GetPath())
+
+	// The unsaved buffer replaces the file and has no origin, so Save does not overwrite the file.
+	// 未保存的缓冲区替换该文件且没有来源，因此 Save 不会覆盖该文件。
+	loader := NewLoader().SetOverlay(map[string][]byte{path: []byte("package demo\n\nfunc Unsaved() {}\n")})
+	astBundle = rese.P1(loader.LoadFile(token.NewFileSet(), path))
+	require.Equal(t, []string{path + " Unsaved"}, funcNames([]*AstBundle{astBundle}))
+	require.Empty(t, astBundle.GetPath())
+	require.Error(t, astBundle.Save())
+
+	astBundles := rese.V1(loader.LoadPackage(token.NewFileSet(), root))
+	require.Equal(t, []string{path + " Unsaved"}, funcNames(astBundles))
+}
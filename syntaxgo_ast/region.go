@@ -0,0 +1,244 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+)
+
+// Region is a block of generated code between the begin and end marker comments, like:
+//
+//	// gen:begin methods
+//	func (a *Account) GetName() string { return a.Name }
+//	// gen:end
+//
+// Region 是位于开始和结束标记注释之间的生成代码块。
+type Region struct {
+	Name         string // Region name / 区域名称
+	Begin        int    // Offset of the begin marker line / 开始标记行的偏移量
+	ContentBegin int    // Offset of the content, after the begin marker line / 内容的偏移量，位于开始标记行之后
+	ContentEnd   int    // Offset after the content, at the end marker line / 内容结束的偏移量，位于结束标记行
+	End          int    // Offset after the end marker line / 结束标记行之后的偏移量
+}
+
+// Content returns the code between the marker lines.
+// Content 返回标记行之间的代码。
+func (region *Region) Content(source []byte) []byte {
+	return source[region.ContentBegin:region.ContentEnd]
+}
+
+// RegionOptions configures the marker comments of regions, the default markers are "gen:begin" and "gen:end".
+// RegionOptions 配置区域的标记注释，默认标记为 "gen:begin" 和 "gen:end"。
+type RegionOptions struct {
+	beginMarker string // Begin marker followed by the region name / 开始标记，后面跟着区域名称
+	endMarker   string // End marker, optionally followed by the region name / 结束标记，后面可以跟着区域名称
+}
+
+// NewRegionOptions creates RegionOptions with the default markers "gen:begin" and "gen:end".
+// NewRegionOptions 创建使用默认标记 "gen:begin" 和 "gen:end" 的 RegionOptions。
+func NewRegionOptions() *RegionOptions {
+	return &RegionOptions{
+		beginMarker: "gen:begin",
+		endMarker:   "gen:end",
+	}
+}
+
+// SetBeginMarker sets the begin marker, like "region:begin".
+// SetBeginMarker 设置开始标记，比如 "region:begin"。
+func (options *RegionOptions) SetBeginMarker(beginMarker string) *RegionOptions {
+	options.beginMarker = beginMarker
+	return options
+}
+
+// SetEndMarker sets the end marker, like "region:end".
+// SetEndMarker 设置结束标记，比如 "region:end"。
+func (options *RegionOptions) SetEndMarker(endMarker string) *RegionOptions {
+	options.endMarker = endMarker
+	return options
+}
+
+// FindRegions finds the regions in the source code, in source order.
+// Nested, unterminated and duplicated regions are errors.
+// FindRegions 查找源代码中的区域，按源码顺序排列。
+// 嵌套、未结束以及重名的区域都会报错。
+func (options *RegionOptions) FindRegions(source []byte) ([]*Region, error) {
+	var regions []*Region
+	var current *Region
+	names := map[string]bool{}
+	for offset, lineNum := 0, 1; offset < len(source); lineNum++ {
+		next := lineEnd(source, offset)
+		line := string(source[offset:next])
+		if name, ok := options.matchMarker(line, options.beginMarker); ok {
+			if current != nil {
+				return nil, erero.Errorf("region %q begins in region %q at line %d", name, current.Name, lineNum)
+			}
+			if name == "" {
+				return nil, erero.Errorf("region without name at line %d", lineNum)
+			}
+			if names[name] {
+				return nil, erero.Errorf("duplicated region %q at line %d", name, lineNum)
+			}
+			names[name] = true
+			current = &Region{Name: name, Begin: offset, ContentBegin: next}
+		} else if name, ok := options.matchMarker(line, options.endMarker); ok {
+			if current == nil {
+				return nil, erero.Errorf("region end without begin at line %d", lineNum)
+			}
+			if name != "" && name != current.Name {
+				return nil, erero.Errorf("region %q ends with name %q at line %d", current.Name, name, lineNum)
+			}
+			current.ContentEnd = offset
+			current.End = next
+			regions = append(regions, current)
+			current = nil
+		}
+		offset = next
+	}
+	if current != nil {
+		return nil, erero.Errorf("region %q is not terminated", current.Name)
+	}
+	return regions, nil
+}
+
+// FindRegion finds the region with the name in the source code.
+// FindRegion 查找源代码中具有指定名称的区域。
+func (options *RegionOptions) FindRegion(source []byte, name string) (*Region, bool, error) {
+	regions, err := options.FindRegions(source)
+	if err != nil {
+		return nil, false, erero.Wro(err)
+	}
+	for _, region := range regions {
+		if region.Name == name {
+			return region, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// ReplaceRegion replaces the content of the region with the code, everything outside the region is kept byte-for-byte.
+// ReplaceRegion 用新代码替换区域的内容，区域之外的内容逐字节保持不变。
+func (options *RegionOptions) ReplaceRegion(source []byte, name string, code []byte) ([]byte, error) {
+	region, ok, err := options.FindRegion(source, name)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !ok {
+		return nil, erero.Errorf("no region name = %s in the source", name)
+	}
+	return replaceRange(source, region.ContentBegin, region.ContentEnd, withTrailingNewline(code)), nil
+}
+
+// UpsertRegion replaces the content of the region, or creates the region at the anchor when it is missing.
+// UpsertRegion 替换区域的内容，当区域不存在时在锚点处创建该区域。
+func (options *RegionOptions) UpsertRegion(source []byte, name string, code []byte, anchor *RegionAnchor) ([]byte, error) {
+	_, ok, err := options.FindRegion(source, name)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if ok {
+		return options.ReplaceRegion(source, name, code)
+	}
+	offset, err := anchor.locate(source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	block := "// " + options.beginMarker + " " + name + "\n" + string(withTrailingNewline(code)) + "// " + options.endMarker + "\n"
+	if offset > 0 && source[offset-1] != '\n' {
+		block = "\n" + block
+	}
+	return replaceRange(source, offset, offset, []byte("\n"+block)), nil
+}
+
+// matchMarker matches the comment line "// <marker> <name>", the name can be empty.
+// matchMarker 匹配注释行 "// <marker> <name>"，名称可以为空。
+func (options *RegionOptions) matchMarker(line string, marker string) (string, bool) {
+	text, ok := strings.CutPrefix(strings.TrimSpace(line), "//")
+	if !ok {
+		return "", false
+	}
+	text = strings.TrimSpace(text)
+	rest, ok := strings.CutPrefix(text, marker)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// RegionAnchor is where a missing region is created.
+// RegionAnchor 是创建缺失区域的位置。
+type RegionAnchor struct {
+	typeName string // Create the region after the type declaration / 在类型声明之后创建区域
+	funcName string // Create the region after the function declaration / 在函数声明之后创建区域
+}
+
+// NewAnchorFileEnd creates an anchor at the end of the file.
+// NewAnchorFileEnd 创建位于文件末尾的锚点。
+func NewAnchorFileEnd() *RegionAnchor {
+	return &RegionAnchor{}
+}
+
+// NewAnchorAfterType creates an anchor after the declaration of the type, grouped declarations are skipped as a whole.
+// NewAnchorAfterType 创建位于类型声明之后的锚点，分组声明会被整体跳过。
+func NewAnchorAfterType(typeName string) *RegionAnchor {
+	return &RegionAnchor{typeName: typeName}
+}
+
+// NewAnchorAfterFunc creates an anchor after the function, methods are named like "Receiver.Method".
+// NewAnchorAfterFunc 创建位于函数之后的锚点，方法的名称形如 "Receiver.Method"。
+func NewAnchorAfterFunc(funcName string) *RegionAnchor {
+	return &RegionAnchor{funcName: funcName}
+}
+
+// locate returns the offset where the region is inserted, at the start of a line.
+// locate 返回插入区域的偏移量，位于行首。
+func (anchor *RegionAnchor) locate(source []byte) (int, error) {
+	if anchor == nil || (anchor.typeName == "" && anchor.funcName == "") {
+		return len(source), nil
+	}
+	astFile, err := parseSource(token.NewFileSet(), "", source)
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	for _, decl := range astFile.Decls {
+		switch item := decl.(type) {
+		case *ast.GenDecl:
+			if anchor.typeName == "" {
+				continue
+			}
+			for _, spec := range item.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == anchor.typeName {
+					return lineEnd(source, offsetOf(astFile, item.End())), nil
+				}
+			}
+		case *ast.FuncDecl:
+			if anchor.funcName != "" && funcDeclName(item) == anchor.funcName {
+				return lineEnd(source, offsetOf(astFile, item.End())), nil
+			}
+		}
+	}
+	if anchor.typeName != "" {
+		return 0, erero.Errorf("no type name = %s in the source", anchor.typeName)
+	}
+	return 0, erero.Errorf("no func name = %s in the source", anchor.funcName)
+}
+
+// funcDeclName returns the name of the function, or "Receiver.Method" of a method.
+// funcDeclName 返回函数的名称，方法返回 "Receiver.Method"。
+func funcDeclName(funcDecl *ast.FuncDecl) string {
+	if typeName, _, ok := utils.GetReceiverTypeName(funcDecl); ok {
+		return typeName + "." + funcDecl.Name.Name
+	}
+	return funcDecl.Name.Name
+}
+
+// withTrailingNewline returns the code ending with a newline, empty code stays empty.
+// withTrailingNewline 返回以换行结尾的代码，空代码保持为空。
+func withTrailingNewline(code []byte) []byte {
+	if len(code) == 0 || code[len(code)-1] == '\n' {
+		return code
+	}
+	return append(append([]byte{}, code...), '\n')
+}
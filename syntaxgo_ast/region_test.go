@@ -0,0 +1,101 @@
+package syntaxgo_ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const regionSource = `package demo
+
+type Account struct {
+	Name string
+}
+
+// manual code, keep   it   as it is
+func (a *Account) Hello() string { return "hello" }
+
+// gen:begin getters
+func (a *Account) GetName() string { return "" }
+// gen:end getters
+
+type Role string
+`
+
+func TestRegionOptions_FindRegions(t *testing.T) {
+	regions := rese.V1(NewRegionOptions().FindRegions([]byte(regionSource)))
+	require.Len(t, regions, 1)
+	require.Equal(t, "getters", regions[0].Name)
+	require.Equal(t, "func (a *Account) GetName() string { return \"\" }\n", string(regions[0].Content([]byte(regionSource))))
+}
+
+func TestRegionOptions_FindRegions_Wrong(t *testing.T) {
+	options := NewRegionOptions()
+	_, err := options.FindRegions([]byte("// gen:begin a\n// gen:begin b\n// gen:end\n// gen:end\n"))
+	require.Error(t, err)
+	_, err = options.FindRegions([]byte("// gen:begin a\n"))
+	require.Error(t, err)
+	_, err = options.FindRegions([]byte("// gen:end\n"))
+	require.Error(t, err)
+	_, err = options.FindRegions([]byte("// gen:begin a\n// gen:end b\n"))
+	require.Error(t, err)
+	_, err = options.FindRegions([]byte("// gen:begin a\n// gen:end\n// gen:begin a\n// gen:end\n"))
+	require.Error(t, err)
+
+	regions := rese.V1(options.FindRegions([]byte("// gen:beginning a\n// gen:ended\n")))
+	require.Empty(t, regions)
+}
+
+func TestRegionOptions_ReplaceRegion(t *testing.T) {
+	code := "func (a *Account) GetName() string { return a.Name }"
+	source := rese.V1(NewRegionOptions().ReplaceRegion([]byte(regionSource), "getters", []byte(code)))
+	t.Log(string(source))
+	expected := `package demo
+
+type Account struct {
+	Name string
+}
+
+// manual code, keep   it   as it is
+func (a *Account) Hello() string { return "hello" }
+
+// gen:begin getters
+func (a *Account) GetName() string { return a.Name }
+// gen:end getters
+
+type Role string
+`
+	require.Equal(t, expected, string(source))
+
+	_, err := NewRegionOptions().ReplaceRegion([]byte(regionSource), "setters", []byte(code))
+	require.Error(t, err)
+}
+
+func TestRegionOptions_UpsertRegion(t *testing.T) {
+	options := NewRegionOptions()
+	code := []byte("func (a *Account) SetName(name string) { a.Name = name }\n")
+
+	source := rese.V1(options.UpsertRegion([]byte(regionSource), "setters", code, NewAnchorAfterType("Account")))
+	t.Log(string(source))
+	require.Contains(t, string(source), "type Account struct {\n\tName string\n}\n\n// gen:begin setters\n"+string(code)+"// gen:end\n\n// manual code")
+	// Upsert again only replaces the content.
+	// 再次执行只会替换内容。
+	require.Equal(t, string(source), string(rese.V1(options.UpsertRegion(source, "setters", code, NewAnchorAfterType("Account")))))
+
+	source = rese.V1(options.UpsertRegion([]byte(regionSource), "setters", code, NewAnchorFileEnd()))
+	require.Equal(t, regionSource+"\n// gen:begin setters\n"+string(code)+"// gen:end\n", string(source))
+
+	source = rese.V1(options.UpsertRegion([]byte(regionSource), "setters", code, NewAnchorAfterFunc("Account.Hello")))
+	require.Contains(t, string(source), "{ return \"hello\" }\n\n// gen:begin setters\n")
+
+	_, err := options.UpsertRegion([]byte(regionSource), "setters", code, NewAnchorAfterType("User"))
+	require.Error(t, err)
+}
+
+func TestRegionOptions_SetMarkers(t *testing.T) {
+	options := NewRegionOptions().SetBeginMarker("region:begin").SetEndMarker("region:end")
+	source := []byte("package demo\n\n// region:begin consts\nconst A = 1\n// region:end\n")
+	source = rese.V1(options.ReplaceRegion(source, "consts", []byte("const A = 2\n")))
+	require.Equal(t, "package demo\n\n// region:begin consts\nconst A = 2\n// region:end\n", string(source))
+}
@@ -0,0 +1,316 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// RenameResult is the result of renaming a declaration in the bundles of a package.
+// RenameResult 是在一个包的 AST 包中重命名声明的结果。
+type RenameResult struct {
+	Renamed    []token.Position       // Positions of the renamed declaration and references / 被重命名的声明和引用的位置
+	Unresolved []*UnresolvedReference // References not renamed since they can not be resolved safely / 无法安全解析因此未被重命名的引用
+}
+
+// UnresolvedReference is an identifier with the old name that might refer to the declaration, but is not renamed.
+// UnresolvedReference 是具有旧名称、可能引用该声明但未被重命名的标识符。
+type UnresolvedReference struct {
+	Position token.Position // Position of the identifier / 标识符的位置
+	Reason   string         // Why the identifier is not renamed / 标识符未被重命名的原因
+}
+
+// Rename renames the declaration in the bundle together with its references, see RenameInPackage.
+// Rename 重命名 AST 包中的声明及其引用，参见 RenameInPackage。
+func (ab *AstBundle) Rename(oldName string, newName string) (*RenameResult, error) {
+	return RenameInPackage([]*AstBundle{ab}, oldName, newName)
+}
+
+// RenameInPackage renames the declaration and all its references in the bundles, which are the files of one package.
+// The old name can be a function, type, var or const like "Name", or a method or field like "Type.Name", the new name is an identifier.
+// References are resolved with go/types, so shadowed locals and same named members of other types are untouched,
+// the doc comment starting with the old name is updated too. Identifiers with the old name that the type checker can not resolve,
+// like selectors on values of unknown types, are not renamed and are reported in the result.
+// The bundles are reparsed, so nodes found before the call are no longer part of the bundles.
+// RenameInPackage 在 AST 包中重命名声明及其所有引用，这些 AST 包是同一个包的文件。
+// 旧名称可以是函数、类型、变量或常量，比如 "Name"，也可以是方法或字段，比如 "Type.Name"，新名称是一个标识符。
+// 引用通过 go/types 解析，因此被遮蔽的局部变量以及其它类型的同名成员都不会被修改，以旧名称开头的文档注释也会被更新。
+// 类型检查器无法解析的同名标识符（比如在未知类型的值上的选择器）不会被重命名，并会在结果中报告。
+// AST 包会被重新解析，因此调用之前找到的节点不再属于这些 AST 包。
+func RenameInPackage(astBundles []*AstBundle, oldName string, newName string) (*RenameResult, error) {
+	if !token.IsIdentifier(newName) {
+		return nil, erero.Errorf("new name %q is not an identifier", newName)
+	}
+	if len(astBundles) == 0 {
+		return nil, erero.New("no bundles to rename in")
+	}
+	renamer, err := newPackageRenamer(astBundles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	targets, err := renamer.lookupTargets(oldName, newName)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	result, err := renamer.rename(targets, newName)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return result, nil
+}
+
+// packageRenamer holds the files of a package parsed into one FileSet and checked with go/types.
+// packageRenamer 保存解析到同一个 FileSet 中并经过 go/types 检查的包文件。
+type packageRenamer struct {
+	astBundles []*AstBundle
+	fset       *token.FileSet
+	sources    [][]byte
+	astFiles   []*ast.File
+	pkg        *types.Package
+	info       *types.Info
+	bareName   string // Declaration or member name without the type name / 不带类型名称的声明或成员名称
+}
+
+func newPackageRenamer(astBundles []*AstBundle) (*packageRenamer, error) {
+	renamer := &packageRenamer{
+		astBundles: astBundles,
+		fset:       token.NewFileSet(),
+		info: &types.Info{
+			Defs: map[*ast.Ident]types.Object{},
+			Uses: map[*ast.Ident]types.Object{},
+		},
+	}
+	for _, astBundle := range astBundles {
+		source, err := astBundle.FormatSource()
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		astFile, err := parser.ParseFile(renamer.fset, astBundle.fileName(), source, parser.ParseComments)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		if packageName := renamer.packageName(); packageName != "" && astFile.Name.Name != packageName {
+			return nil, erero.Errorf("bundles of package %s and %s are not in one package", packageName, astFile.Name.Name)
+		}
+		renamer.sources = append(renamer.sources, source)
+		renamer.astFiles = append(renamer.astFiles, astFile)
+	}
+	// Type errors like missing imports are tolerated, the identifiers they leave unresolved are reported.
+	// 容忍缺失导入等类型错误，由此产生的未解析标识符会被报告。
+	config := &types.Config{Importer: importer.Default(), Error: func(err error) {}}
+	renamer.pkg, _ = config.Check(renamer.packageName(), renamer.fset, renamer.astFiles, renamer.info)
+	return renamer, nil
+}
+
+func (renamer *packageRenamer) packageName() string {
+	if len(renamer.astFiles) == 0 {
+		return ""
+	}
+	return renamer.astFiles[0].Name.Name
+}
+
+// lookupTargets finds the objects to rename, a type also renames the embedded fields of the type.
+// lookupTargets 查找需要重命名的对象，类型同时会重命名以该类型嵌入的字段。
+func (renamer *packageRenamer) lookupTargets(oldName string, newName string) (map[types.Object]bool, error) {
+	scope := renamer.pkg.Scope()
+	typeName, memberName, isMember := strings.Cut(oldName, ".")
+	if !isMember {
+		object := scope.Lookup(oldName)
+		if object == nil {
+			return nil, erero.Errorf("no declaration name = %s in the package", oldName)
+		}
+		if scope.Lookup(newName) != nil {
+			return nil, erero.Errorf("new name %s is already declared in the package", newName)
+		}
+		if types.Universe.Lookup(newName) != nil {
+			return nil, erero.Errorf("new name %s is a predeclared identifier", newName)
+		}
+		renamer.bareName = oldName
+		targets := map[types.Object]bool{object: true}
+		if _, ok := object.(*types.TypeName); ok {
+			for ident, def := range renamer.info.Defs {
+				if field, ok := def.(*types.Var); ok && field.Embedded() && renamer.info.Uses[ident] == object {
+					targets[field] = true
+				}
+			}
+		}
+		return targets, nil
+	}
+	object, ok := scope.Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, erero.Errorf("no type name = %s in the package", typeName)
+	}
+	member, index, _ := types.LookupFieldOrMethod(object.Type(), true, renamer.pkg, memberName)
+	if member == nil || len(index) != 1 {
+		return nil, erero.Errorf("no field or method name = %s declared by the type %s", memberName, typeName)
+	}
+	if other, _, _ := types.LookupFieldOrMethod(object.Type(), true, renamer.pkg, newName); other != nil {
+		return nil, erero.Errorf("new name %s is already a field or method of the type %s", newName, typeName)
+	}
+	renamer.bareName = memberName
+	return map[types.Object]bool{member: true}, nil
+}
+
+// rename renames the identifiers of the targets, then reloads the bundles.
+// rename 重命名目标对象的标识符，然后重新加载 AST 包。
+func (renamer *packageRenamer) rename(targets map[types.Object]bool, newName string) (*RenameResult, error) {
+	result := &RenameResult{}
+	for idx, astFile := range renamer.astFiles {
+		var idents []*ast.Ident
+		var err error
+		ast.Inspect(astFile, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok || err != nil || ident.Name != renamer.bareName || ident == astFile.Name {
+				return err == nil
+			}
+			object := renamer.info.Defs[ident]
+			if object == nil {
+				object = renamer.info.Uses[ident]
+			}
+			if object == nil {
+				result.Unresolved = append(result.Unresolved, &UnresolvedReference{
+					Position: renamer.fset.Position(ident.Pos()),
+					Reason:   "the identifier is not resolved by the type checker",
+				})
+				return true
+			}
+			if !targets[object] {
+				if reason, ok := renamer.implicitReason(object, targets); ok {
+					result.Unresolved = append(result.Unresolved, &UnresolvedReference{
+						Position: renamer.fset.Position(ident.Pos()),
+						Reason:   reason,
+					})
+				}
+				return true
+			}
+			if err = renamer.checkShadow(ident, object, newName); err != nil {
+				return false
+			}
+			idents = append(idents, ident)
+			return true
+		})
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		source := renamer.sources[idx]
+		type edit struct{ start, end int }
+		var edits []edit
+		for _, ident := range idents {
+			result.Renamed = append(result.Renamed, renamer.fset.Position(ident.Pos()))
+			start := offsetOf(astFile, ident.Pos())
+			edits = append(edits, edit{start: start, end: start + len(ident.Name)})
+			if doc := declDoc(astFile, ident); doc != nil && strings.HasPrefix(doc.List[0].Text, "// "+ident.Name+" ") {
+				start := offsetOf(astFile, doc.Pos()) + len("// ")
+				edits = append(edits, edit{start: start, end: start + len(ident.Name)})
+			}
+		}
+		sort.Slice(edits, func(i, j int) bool {
+			return edits[i].start > edits[j].start
+		})
+		for _, item := range edits {
+			source = replaceRange(source, item.start, item.end, []byte(newName))
+		}
+		renamer.sources[idx] = source
+	}
+	for idx, astBundle := range renamer.astBundles {
+		if err := astBundle.reload(renamer.sources[idx]); err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+	return result, nil
+}
+
+// implicitReason reports a same named method of an interface when renaming a method,
+// since types implementing the interface through the method stop implementing it.
+// implicitReason 在重命名方法时报告接口中的同名方法，
+// 因为通过该方法实现接口的类型将不再实现该接口。
+func (renamer *packageRenamer) implicitReason(object types.Object, targets map[types.Object]bool) (string, bool) {
+	method, ok := object.(*types.Func)
+	if !ok || method.Pkg() != renamer.pkg {
+		return "", false
+	}
+	signature, ok := method.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil || !types.IsInterface(signature.Recv().Type()) {
+		return "", false
+	}
+	for target := range targets {
+		if targetMethod, ok := target.(*types.Func); ok && targetMethod.Type().(*types.Signature).Recv() != nil {
+			return "the interface method with the same name is not renamed, types implementing the interface might stop implementing it", true
+		}
+	}
+	return "", false
+}
+
+// checkShadow checks that the new name is not declared in a scope between the reference and the package scope.
+// checkShadow 检查新名称没有在引用与包作用域之间的作用域中声明。
+func (renamer *packageRenamer) checkShadow(ident *ast.Ident, object types.Object, newName string) error {
+	if object.Parent() != renamer.pkg.Scope() {
+		return nil
+	}
+	scope := renamer.pkg.Scope().Innermost(ident.Pos())
+	if scope == nil {
+		return nil
+	}
+	if parent, other := scope.LookupParent(newName, ident.Pos()); other != nil && parent != renamer.pkg.Scope() {
+		return erero.Errorf("new name %s is shadowed by the declaration at %s", newName, renamer.fset.Position(other.Pos()))
+	}
+	return nil
+}
+
+// declDoc returns the doc comment of the declaration whose name is the identifier.
+// declDoc 返回以该标识符为名称的声明的文档注释。
+func declDoc(astFile *ast.File, ident *ast.Ident) *ast.CommentGroup {
+	var doc *ast.CommentGroup
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if doc != nil {
+			return false
+		}
+		switch item := node.(type) {
+		case *ast.FuncDecl:
+			if item.Name == ident {
+				doc = item.Doc
+			}
+		case *ast.GenDecl:
+			for _, spec := range item.Specs {
+				specDoc := specDoc(spec)
+				if specDoc == nil && item.Lparen == token.NoPos {
+					specDoc = item.Doc
+				}
+				if declaresIdent(spec, ident) {
+					doc = specDoc
+				}
+			}
+		case *ast.Field:
+			if indexOfIdent(item.Names, ident) >= 0 {
+				doc = item.Doc
+			}
+		}
+		return true
+	})
+	return doc
+}
+
+func declaresIdent(spec ast.Spec, ident *ast.Ident) bool {
+	switch item := spec.(type) {
+	case *ast.TypeSpec:
+		return item.Name == ident
+	case *ast.ValueSpec:
+		return indexOfIdent(item.Names, ident) >= 0
+	}
+	return false
+}
+
+func indexOfIdent(idents []*ast.Ident, ident *ast.Ident) int {
+	for idx, item := range idents {
+		if item == ident {
+			return idx
+		}
+	}
+	return -1
+}
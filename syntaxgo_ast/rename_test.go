@@ -0,0 +1,147 @@
+package syntaxgo_ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const renameSource = `package demo
+
+import "strings"
+
+// Base is the base.
+type Base struct{ ID int }
+
+// Account is an account.
+type Account struct {
+	*Base
+	// Name is the name.
+	Name string
+}
+
+// Greeter greets.
+type Greeter interface {
+	Greet() string
+}
+
+// Greet greets the account.
+func (a *Account) Greet() string { return "hello " + a.Name }
+
+// Upper returns the upper name.
+func Upper(a *Account) string {
+	name := strings.ToUpper(a.Name)
+	return name + a.Greet() + string(rune(a.Base.ID))
+}
+
+func Shadow() string {
+	Upper := func(s string) string { return s }
+	return Upper("x")
+}
+
+func Unknown() string {
+	return unknown.Value().Name
+}
+`
+
+func TestAstBundle_Rename(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(renameSource)))
+
+	result := rese.P1(astBundle.Rename("Upper", "ToUpper"))
+	require.Len(t, result.Renamed, 1)
+	require.Empty(t, result.Unresolved)
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "// ToUpper returns the upper name.\nfunc ToUpper(a *Account) string {")
+	require.Contains(t, source, "Upper := func(s string) string { return s }\n\treturn Upper(\"x\")")
+	require.Contains(t, source, "strings.ToUpper(a.Name)")
+}
+
+func TestAstBundle_Rename_Field(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(renameSource)))
+
+	result := rese.P1(astBundle.Rename("Account.Name", "Title"))
+	require.Len(t, result.Renamed, 3)
+	require.Len(t, result.Unresolved, 1)
+	require.Equal(t, 35, result.Unresolved[0].Position.Line)
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "\t// Title is the name.\n\tTitle string\n")
+	require.Contains(t, source, `"hello " + a.Title`)
+	require.Contains(t, source, "strings.ToUpper(a.Title)")
+	require.Contains(t, source, "unknown.Value().Name")
+}
+
+func TestAstBundle_Rename_Method(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(renameSource)))
+
+	result := rese.P1(astBundle.Rename("Account.Greet", "SayHello"))
+	require.Len(t, result.Renamed, 2)
+	require.Len(t, result.Unresolved, 1)
+	require.Equal(t, 17, result.Unresolved[0].Position.Line)
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	require.Contains(t, source, "// SayHello greets the account.\nfunc (a *Account) SayHello() string {")
+	require.Contains(t, source, "\tGreet() string\n")
+}
+
+func TestAstBundle_Rename_Type(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(renameSource)))
+
+	result := rese.P1(astBundle.Rename("Base", "Model"))
+	require.Len(t, result.Renamed, 3)
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "// Model is the base.\ntype Model struct{ ID int }")
+	require.Contains(t, source, "\t*Model\n")
+	require.Contains(t, source, "a.Model.ID")
+}
+
+func TestAstBundle_Rename_Conflict(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(renameSource)))
+
+	_, err := astBundle.Rename("Upper", "Account")
+	require.Error(t, err)
+	_, err = astBundle.Rename("Upper", "len")
+	require.Error(t, err)
+	_, err = astBundle.Rename("Account.Name", "Greet")
+	require.Error(t, err)
+	_, err = astBundle.Rename("Account.ID", "Key")
+	require.Error(t, err)
+	_, err = astBundle.Rename("Missing", "Other")
+	require.Error(t, err)
+	_, err = astBundle.Rename("Upper", "a-b")
+	require.Error(t, err)
+
+	const shadowSource = `package demo
+
+func Value() int { return 1 }
+
+func Use() int {
+	next := 2
+	return Value() + next
+}
+`
+	shadowBundle := rese.P1(NewAstBundleV1([]byte(shadowSource)))
+	_, err = shadowBundle.Rename("Value", "next")
+	require.Error(t, err)
+	require.Equal(t, shadowSource, string(rese.V1(shadowBundle.FormatSource())))
+}
+
+func TestRenameInPackage(t *testing.T) {
+	bundleA := rese.P1(NewAstBundleV1([]byte("package demo\n\n// Hello says hello.\nfunc Hello() string { return \"hello\" }\n")))
+	bundleB := rese.P1(NewAstBundleV1([]byte("package demo\n\nfunc Greet() string { return Hello() + \" world\" }\n")))
+
+	result := rese.P1(RenameInPackage([]*AstBundle{bundleA, bundleB}, "Hello", "SayHello"))
+	require.Len(t, result.Renamed, 2)
+	require.Contains(t, string(rese.V1(bundleA.FormatSource())), "// SayHello says hello.\nfunc SayHello() string {")
+	require.Contains(t, string(rese.V1(bundleB.FormatSource())), "return SayHello() + \" world\"")
+
+	bundleC := rese.P1(NewAstBundleV1([]byte("package other\n")))
+	_, err := RenameInPackage([]*AstBundle{bundleA, bundleC}, "SayHello", "Hello")
+	require.Error(t, err)
+}
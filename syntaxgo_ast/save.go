@@ -0,0 +1,157 @@
+package syntaxgo_ast
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/yyle88/erero"
+)
+
+// setOrigin remembers the file of the bundle and the hash of its content.
+// setOrigin 记录 AST 包的文件及其内容的哈希。
+func (ab *AstBundle) setOrigin(path string, source []byte) {
+	ab.path = path
+	ab.hash = sha256.Sum256(source)
+}
+
+// isSaveableMode tells whether the parser mode keeps the whole file with its comments, so the bundle can be written back.
+// isSaveableMode 判断解析模式是否保留了带注释的整个文件，从而可以将 AST 包写回。
+func isSaveableMode(mode parser.Mode) bool {
+	return mode&parser.ParseComments != 0 && mode&(parser.ImportsOnly|parser.PackageClauseOnly) == 0
+}
+
+// GetPath returns the file the bundle was parsed from or last saved to,
+// empty when it was parsed from bytes or with a mode dropping code or comments.
+// GetPath 返回 AST 包解析自或最近保存到的文件，从字节解析或者使用会丢弃代码或注释的解析模式解析时为空。
+func (ab *AstBundle) GetPath() string {
+	return ab.path
+}
+
+// Save formats the bundle and writes it back to the file it was parsed from, see SaveTo.
+// Save 格式化 AST 包并将其写回解析来源的文件，参见 SaveTo。
+func (ab *AstBundle) Save() error {
+	if ab.path == "" {
+		return erero.New("bundle has no file to save to, it was not fully parsed with comments from a file")
+	}
+	if err := ab.SaveTo(ab.path); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+// SaveTo formats the bundle and writes it to the path, the bundle then belongs to that file.
+// The formatted source must parse again, and it is written to a temp file renamed over the path, keeping the file mode.
+// Writing to the file the bundle was parsed from is refused when the file changed on disk since then,
+// and a bundle with syntax errors from a tolerant parse is never written.
+// SaveTo 格式化 AST 包并将其写入该路径，之后 AST 包归属于该文件。
+// 格式化后的源代码必须能够再次解析，先写入临时文件再重命名覆盖该路径，保持文件权限不变。
+// 写入 AST 包解析来源的文件时，若该文件在此之后在磁盘上被修改过，则拒绝写入，
+// 宽容解析得到的带有语法错误的 AST 包永远不会被写入。
+func (ab *AstBundle) SaveTo(path string) error {
+	// The parser repairs some syntax errors in the partial file, writing it would change the code without notice.
+	// 解析器会修复部分文件中的某些语法错误，写入它会在不被察觉的情况下改变代码。
+	if ab.HasDiagnostics() {
+		return erero.Errorf("bundle has syntax errors, it is not saved to %s: %v", path, ab.diagnostics)
+	}
+	source, err := ab.FormatSource()
+	if err != nil {
+		return erero.Wro(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), path, source, parser.ParseComments); err != nil {
+		return erero.WithMessagef(err, "formatted source of %s does not parse", path)
+	}
+	if ab.path != "" && isSamePath(ab.path, path) {
+		if err := ab.checkUnchanged(); err != nil {
+			return erero.Wro(err)
+		}
+	}
+	if err := writeFileAtomic(path, source); err != nil {
+		return erero.Wro(err)
+	}
+	ab.setOrigin(path, source)
+	return nil
+}
+
+// checkUnchanged checks that the file on disk has the content the bundle was parsed from.
+// checkUnchanged 检查磁盘上的文件内容与 AST 包解析时的内容一致。
+func (ab *AstBundle) checkUnchanged() error {
+	source, err := os.ReadFile(ab.path)
+	if err != nil {
+		return erero.WithMessagef(err, "file %s is not readable since it was parsed", ab.path)
+	}
+	if hash := sha256.Sum256(source); !bytes.Equal(hash[:], ab.hash[:]) {
+		return erero.Errorf("file %s changed on disk since it was parsed", ab.path)
+	}
+	return nil
+}
+
+// isSamePath reports whether the two paths are the same file, comparing the absolute paths when either does not exist.
+// isSamePath 判断两个路径是否是同一个文件，其中任一路径不存在时比较绝对路径。
+func isSamePath(path1 string, path2 string) bool {
+	info1, err1 := os.Stat(path1)
+	info2, err2 := os.Stat(path2)
+	if err1 == nil && err2 == nil {
+		return os.SameFile(info1, info2)
+	}
+	abs1, err1 := filepath.Abs(path1)
+	abs2, err2 := filepath.Abs(path2)
+	return err1 == nil && err2 == nil && abs1 == abs2
+}
+
+// writeFileAtomic writes the data to a temp file in the same directory, then renames it over the path.
+// The mode of the existing file is kept, new files get 0644. A symlink is followed, writing to the file it points to.
+// writeFileAtomic 将数据写入同一目录中的临时文件，然后重命名覆盖该路径。
+// 保持已有文件的权限，新文件使用 0644。会跟随符号链接，写入其指向的文件。
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if realPath, err := filepath.EvalSymlinks(path); err == nil {
+		path = realPath
+		info, err := os.Stat(path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return erero.Wro(err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return erero.Wro(err)
+	}
+	tempPath := tempFile.Name()
+	if err := writeAndClose(tempFile, data, mode); err != nil {
+		_ = os.Remove(tempPath)
+		return erero.Wro(err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return erero.Wro(err)
+	}
+	return nil
+}
+
+// writeAndClose writes the data to the file, syncs it to disk, sets the mode and closes it.
+// writeAndClose 将数据写入文件，同步到磁盘，设置权限并关闭文件。
+func writeAndClose(file *os.File, data []byte, mode os.FileMode) error {
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return erero.Wro(err)
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return erero.Wro(err)
+	}
+	if err := file.Chmod(mode); err != nil {
+		_ = file.Close()
+		return erero.Wro(err)
+	}
+	if err := file.Close(); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
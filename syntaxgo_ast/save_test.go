@@ -0,0 +1,128 @@
+package syntaxgo_ast
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+)
+
+func TestAstBundle_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n\nfunc A() {\nprintln(time.Now())\n}\n"), 0640))
+
+	astBundle := rese.P1(NewAstBundleV4(path))
+	require.Equal(t, path, astBundle.GetPath())
+	require.True(t, astBundle.AddImport("time"))
+	require.NoError(t, astBundle.Save())
+
+	data := rese.V1(os.ReadFile(path))
+	t.Log(string(data))
+	require.Equal(t, "package demo\n\nimport \"time\"\n\nfunc A() {\n\tprintln(time.Now())\n}\n", string(data))
+	require.Equal(t, os.FileMode(0640), rese.V1(os.Stat(path)).Mode().Perm())
+
+	// Saving again is fine, the bundle knows the content it wrote.
+	// 再次保存没有问题，AST 包知道自己写入的内容。
+	require.NoError(t, astBundle.Save())
+
+	entries := rese.V1(os.ReadDir(filepath.Dir(path)))
+	require.Len(t, entries, 1) // No temp file is left / 没有残留的临时文件
+}
+
+func TestAstBundle_Save_AfterEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n\nfunc A() {}\n"), 0644))
+
+	astBundle := rese.P1(NewAstBundleV4(path))
+	astFile, _ := astBundle.GetBundle()
+	require.NoError(t, astBundle.SetDocComment(astFile.Decls[0], "A does nothing."))
+
+	// The reparsed bundle keeps the file name of the positions and its origin file.
+	// 重新解析的 AST 包保持位置中的文件名以及其来源文件。
+	astFile, fset := astBundle.GetBundle()
+	require.Equal(t, path+":4:1", fset.Position(astFile.Decls[0].Pos()).String())
+	require.Equal(t, path, astBundle.GetPath())
+	require.NoError(t, astBundle.Save())
+	require.Equal(t, "package demo\n\n// A does nothing.\nfunc A() {}\n", string(rese.V1(os.ReadFile(path))))
+}
+
+func TestAstBundle_Save_ChangedOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n"), 0644))
+
+	astBundle := rese.P1(NewAstBundleV4(path))
+	done.Done(os.WriteFile(path, []byte("package demo\n\nfunc B() {}\n"), 0644))
+
+	require.Error(t, astBundle.Save())
+	require.Equal(t, "package demo\n\nfunc B() {}\n", string(rese.V1(os.ReadFile(path))))
+
+	require.NoError(t, os.Remove(path))
+	require.Error(t, astBundle.Save())
+}
+
+func TestAstBundle_Save_NoPath(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte("package demo\n")))
+	require.Empty(t, astBundle.GetPath())
+	require.Error(t, astBundle.Save())
+}
+
+func TestAstBundle_Save_PartialMode(t *testing.T) {
+	const source = "package demo\n\nimport \"fmt\"\n\n// A prints.\nfunc A() {\n\tfmt.Println()\n}\n"
+	path := filepath.Join(t.TempDir(), "a.go")
+	done.Done(os.WriteFile(path, []byte(source), 0644))
+
+	// A partial or comment-free parse has no origin file, saving it would cut the code or the comments.
+	// 不完整或不带注释的解析没有来源文件，保存它会删掉其中的代码或注释。
+	for _, mode := range []parser.Mode{parser.ImportsOnly, parser.ImportsOnly | parser.ParseComments, parser.PackageClauseOnly, 0} {
+		astBundle := rese.P1(NewAstBundleV6(path, mode))
+		require.Empty(t, astBundle.GetPath())
+		require.Error(t, astBundle.Save())
+		require.Equal(t, source, string(rese.V1(os.ReadFile(path))))
+
+		astBundle = rese.P1(NewLoader().SetMode(mode).LoadFile(token.NewFileSet(), path))
+		require.Empty(t, astBundle.GetPath())
+		require.Error(t, astBundle.Save())
+		require.Equal(t, source, string(rese.V1(os.ReadFile(path))))
+	}
+
+	astBundle := rese.P1(NewAstBundleV6(path, parser.ParseComments|parser.SkipObjectResolution))
+	require.Equal(t, path, astBundle.GetPath())
+	require.NoError(t, astBundle.Save())
+	require.Equal(t, source, string(rese.V1(os.ReadFile(path))))
+}
+
+func TestAstBundle_SaveTo(t *testing.T) {
+	root := t.TempDir()
+	astBundle := rese.P1(NewAstBundleV1([]byte("package demo\nfunc A() {}\n")))
+
+	path := filepath.Join(root, "b.go")
+	require.NoError(t, astBundle.SaveTo(path))
+	require.Equal(t, path, astBundle.GetPath())
+	require.Equal(t, "package demo\n\nfunc A() {}\n", string(rese.V1(os.ReadFile(path))))
+	require.Equal(t, os.FileMode(0644), rese.V1(os.Stat(path)).Mode().Perm())
+
+	// The bundle belongs to the saved file now, so the change on disk is detected.
+	// AST 包现在归属于保存的文件，因此能检测到磁盘上的修改。
+	done.Done(os.WriteFile(path, []byte("package other\n"), 0644))
+	require.Error(t, astBundle.Save())
+}
+
+func TestAstBundle_SaveTo_Symlink(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.go")
+	done.Done(os.WriteFile(path, []byte("package demo\n"), 0600))
+	link := filepath.Join(root, "link.go")
+	done.Done(os.Symlink(path, link))
+
+	astBundle := rese.P1(NewAstBundleV1([]byte("package demo\nfunc A() {}\n")))
+	require.NoError(t, astBundle.SaveTo(link))
+
+	require.Equal(t, "package demo\n\nfunc A() {}\n", string(rese.V1(os.ReadFile(path))))
+	require.Equal(t, os.FileMode(0600), rese.V1(os.Stat(path)).Mode().Perm())
+	require.Equal(t, os.ModeSymlink, rese.V1(os.Lstat(link)).Mode().Type())
+}
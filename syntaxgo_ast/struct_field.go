@@ -0,0 +1,344 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// The struct field functions take the struct type found in the astFile parsed from the source, like by syntaxgo_search.FindStructTypeByName.
+// The fields are rebuilt from their source code, so doc comments, line comments, tags and the comments between fields are kept,
+// and the result is formatted with gofmt to align the fields.
+// 结构体字段相关的函数接收在由源代码解析得到的 astFile 中找到的结构体类型（比如通过 syntaxgo_search.FindStructTypeByName 找到）。
+// 字段会根据其源代码重新构建，因此文档注释、行尾注释、标签以及字段之间的注释都会保留，并且结果会经过 gofmt 格式化以对齐字段。
+
+// AppendStructField appends the field code at the end of the struct, like "Age int `json:\"age\"` // age".
+// The code can contain several fields and their doc comments.
+// AppendStructField 在结构体末尾追加字段代码，比如 "Age int `json:\"age\"` // age"。
+// 代码可以包含多个字段及其文档注释。
+func AppendStructField(source []byte, astFile *ast.File, structType *ast.StructType, code string) ([]byte, error) {
+	return editStructFields(source, astFile, structType, func(units []*fieldUnit) ([]*fieldUnit, error) {
+		newUnits, err := parseFieldUnits(code)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		return append(units, newUnits...), nil
+	})
+}
+
+// InsertStructFieldAfter inserts the field code after the field with the name.
+// A field declaring several names like "A, B int" is split when the field is inserted between the names.
+// InsertStructFieldAfter 在指定名称的字段之后插入字段代码。
+// 当字段插入到声明多个名称的字段（比如 "A, B int"）的名称之间时，该字段会被拆分。
+func InsertStructFieldAfter(source []byte, astFile *ast.File, structType *ast.StructType, fieldName string, code string) ([]byte, error) {
+	return insertStructField(source, astFile, structType, fieldName, code, 1)
+}
+
+// InsertStructFieldBefore inserts the field code before the field with the name.
+// InsertStructFieldBefore 在指定名称的字段之前插入字段代码。
+func InsertStructFieldBefore(source []byte, astFile *ast.File, structType *ast.StructType, fieldName string, code string) ([]byte, error) {
+	return insertStructField(source, astFile, structType, fieldName, code, 0)
+}
+
+// ReorderStructFields reorders the fields by the names, "*" stands for the fields not in the names, in their original order.
+// Without "*" the fields not in the names go to the end. For example, {"ID", "*", "CreatedAt", "UpdatedAt"} puts ID first and timestamps last.
+// Fields declaring several names are split when some of their names are in the names.
+// ReorderStructFields 按名称重新排列字段，"*" 表示未在名称列表中的字段，保持其原有顺序。
+// 没有 "*" 时，未在名称列表中的字段排在最后。比如 {"ID", "*", "CreatedAt", "UpdatedAt"} 会把 ID 放在最前，时间戳放在最后。
+// 当声明多个名称的字段中有名称出现在列表中时，该字段会被拆分。
+func ReorderStructFields(source []byte, astFile *ast.File, structType *ast.StructType, names []string) ([]byte, error) {
+	return editStructFields(source, astFile, structType, func(units []*fieldUnit) ([]*fieldUnit, error) {
+		listed := map[string]bool{}
+		for _, name := range names {
+			if name == "*" {
+				continue
+			}
+			if _, ok := findFieldUnit(units, name); !ok {
+				return nil, erero.Errorf("no field name = %s in the struct", name)
+			}
+			if listed[name] {
+				return nil, erero.Errorf("duplicated field name = %s in the order", name)
+			}
+			listed[name] = true
+		}
+		var splitUnits []*fieldUnit
+		for _, unit := range units {
+			if len(unit.names) > 1 && hasListedName(unit.names, listed) {
+				splitUnits = append(splitUnits, unit.split()...)
+			} else {
+				splitUnits = append(splitUnits, unit)
+			}
+		}
+
+		var rest []*fieldUnit
+		for _, unit := range splitUnits {
+			if !listed[unit.key()] {
+				rest = append(rest, unit)
+			}
+		}
+		if !hasString(names, "*") {
+			names = append(append([]string{}, names...), "*")
+		}
+		var results []*fieldUnit
+		for _, name := range names {
+			if name == "*" {
+				results = append(results, rest...)
+				continue
+			}
+			idx, _ := findFieldUnit(splitUnits, name)
+			results = append(results, splitUnits[idx])
+		}
+		return results, nil
+	})
+}
+
+func insertStructField(source []byte, astFile *ast.File, structType *ast.StructType, fieldName string, code string, shift int) ([]byte, error) {
+	return editStructFields(source, astFile, structType, func(units []*fieldUnit) ([]*fieldUnit, error) {
+		newUnits, err := parseFieldUnits(code)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		idx, ok := findFieldUnit(units, fieldName)
+		if !ok {
+			return nil, erero.Errorf("no field name = %s in the struct", fieldName)
+		}
+		if len(units[idx].names) > 1 {
+			splitUnits := units[idx].split()
+			units = append(units[:idx], append(splitUnits, units[idx+1:]...)...)
+			idx, _ = findFieldUnit(units, fieldName)
+		}
+		idx += shift
+		return append(units[:idx], append(newUnits, units[idx:]...)...), nil
+	})
+}
+
+// AppendStructField appends the field code at the end of the struct in the bundle, see AppendStructField.
+// AppendStructField 在 AST 包中结构体的末尾追加字段代码，参见 AppendStructField。
+func (ab *AstBundle) AppendStructField(structName string, code string) error {
+	return ab.editStruct(structName, func(source []byte, astFile *ast.File, structType *ast.StructType) ([]byte, error) {
+		return AppendStructField(source, astFile, structType, code)
+	})
+}
+
+// InsertStructFieldAfter inserts the field code after the field of the struct in the bundle, see InsertStructFieldAfter.
+// InsertStructFieldAfter 在 AST 包中结构体的指定字段之后插入字段代码，参见 InsertStructFieldAfter。
+func (ab *AstBundle) InsertStructFieldAfter(structName string, fieldName string, code string) error {
+	return ab.editStruct(structName, func(source []byte, astFile *ast.File, structType *ast.StructType) ([]byte, error) {
+		return InsertStructFieldAfter(source, astFile, structType, fieldName, code)
+	})
+}
+
+// InsertStructFieldBefore inserts the field code before the field of the struct in the bundle, see InsertStructFieldBefore.
+// InsertStructFieldBefore 在 AST 包中结构体的指定字段之前插入字段代码，参见 InsertStructFieldBefore。
+func (ab *AstBundle) InsertStructFieldBefore(structName string, fieldName string, code string) error {
+	return ab.editStruct(structName, func(source []byte, astFile *ast.File, structType *ast.StructType) ([]byte, error) {
+		return InsertStructFieldBefore(source, astFile, structType, fieldName, code)
+	})
+}
+
+// ReorderStructFields reorders the fields of the struct in the bundle, see ReorderStructFields.
+// ReorderStructFields 重新排列 AST 包中结构体的字段，参见 ReorderStructFields。
+func (ab *AstBundle) ReorderStructFields(structName string, names []string) error {
+	return ab.editStruct(structName, func(source []byte, astFile *ast.File, structType *ast.StructType) ([]byte, error) {
+		return ReorderStructFields(source, astFile, structType, names)
+	})
+}
+
+// editStruct edits the source code of the bundle with the struct type found by name.
+// editStruct 使用按名称找到的结构体类型编辑 AST 包的源代码。
+func (ab *AstBundle) editStruct(structName string, edit func(source []byte, astFile *ast.File, structType *ast.StructType) ([]byte, error)) error {
+	return ab.editSource(nil, func(source []byte, astFile *ast.File, _ ast.Node) ([]byte, error) {
+		structType, ok := findStructType(astFile, structName)
+		if !ok {
+			return nil, erero.Errorf("no struct name = %s in the source", structName)
+		}
+		return edit(source, astFile, structType)
+	})
+}
+
+// fieldUnit is the source code of a struct field.
+// fieldUnit 是结构体字段的源代码。
+type fieldUnit struct {
+	blank    bool     // Whether a blank line is before the field / 字段之前是否有空行
+	leading  string   // Comments before the doc comment, separated with blank lines / 文档注释之前、以空行分隔的注释
+	doc      string   // Doc comment / 文档注释
+	names    []string // Field names, empty for an embedded field / 字段名称，嵌入字段为空
+	embedded string   // Type name of an embedded field / 嵌入字段的类型名称
+	kind     string   // Type and tag / 类型和标签
+	comment  string   // Line comment / 行尾注释
+}
+
+// key returns the first name, or the type name of an embedded field.
+// key 返回第一个名称，或者嵌入字段的类型名称。
+func (unit *fieldUnit) key() string {
+	if len(unit.names) > 0 {
+		return unit.names[0]
+	}
+	return unit.embedded
+}
+
+// split splits a field declaring several names into fields of one name,
+// the first one gets the blank line before the field and the doc comment, and the last one gets the line comment.
+// split 将声明多个名称的字段拆分为只有一个名称的字段，
+// 第一个字段获得字段之前的空行以及文档注释，最后一个字段获得行尾注释。
+func (unit *fieldUnit) split() []*fieldUnit {
+	var results []*fieldUnit
+	for idx, name := range unit.names {
+		item := &fieldUnit{names: []string{name}, kind: unit.kind}
+		if idx == 0 {
+			item.blank = unit.blank
+			item.leading = unit.leading
+			item.doc = unit.doc
+		}
+		if idx == len(unit.names)-1 {
+			item.comment = unit.comment
+		}
+		results = append(results, item)
+	}
+	return results
+}
+
+func (unit *fieldUnit) code() string {
+	var lines []string
+	if unit.leading != "" {
+		lines = append(lines, unit.leading, "")
+	}
+	if unit.doc != "" {
+		lines = append(lines, unit.doc)
+	}
+	line := unit.kind
+	if len(unit.names) > 0 {
+		line = strings.Join(unit.names, ", ") + " " + unit.kind
+	}
+	if unit.comment != "" {
+		line += " " + unit.comment
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// editStructFields rebuilds the fields of the struct with the edited field units, then formats the source.
+// editStructFields 使用编辑后的字段单元重新构建结构体的字段，然后格式化源代码。
+func editStructFields(source []byte, astFile *ast.File, structType *ast.StructType, edit func(units []*fieldUnit) ([]*fieldUnit, error)) ([]byte, error) {
+	if structType == nil || structType.Fields == nil {
+		return nil, erero.New("struct type is nil")
+	}
+	units, trailing := newFieldUnits(source, astFile.FileStart, structType)
+	units, err := edit(units)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var codes []string
+	for idx, unit := range units {
+		if idx > 0 && unit.blank {
+			codes = append(codes, "")
+		}
+		codes = append(codes, unit.code())
+	}
+	if trailing != "" {
+		codes = append(codes, trailing)
+	}
+	body := "{\n" + strings.Join(codes, "\n") + "\n}"
+	start := offsetOf(astFile, structType.Fields.Opening)
+	end := offsetOf(astFile, structType.Fields.Closing) + 1
+	newSource, err := format.Source(replaceRange(source, start, end, []byte(body)))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newSource, nil
+}
+
+// newFieldUnits takes the field units from the source code, the base is the position of the first byte of the source.
+// The comments after the last field are returned as the trailing text, starting with a blank line if there is one.
+// newFieldUnits 从源代码中取出字段单元，base 是源代码第一个字节的位置。
+// 最后一个字段之后的注释作为尾部文本返回，如果之前有空行，则以空行开头。
+func newFieldUnits(source []byte, base token.Pos, structType *ast.StructType) ([]*fieldUnit, string) {
+	text := func(pos token.Pos, end token.Pos) string {
+		return string(source[pos-base : end-base])
+	}
+	var units []*fieldUnit
+	prevEnd := structType.Fields.Opening + 1
+	for _, field := range structType.Fields.List {
+		unit := &fieldUnit{}
+		start := field.Pos()
+		if field.Doc != nil {
+			start = field.Doc.Pos()
+			unit.doc = text(field.Doc.Pos(), field.Doc.End())
+		}
+		unit.leading = text(prevEnd, start)
+		for _, ident := range field.Names {
+			unit.names = append(unit.names, ident.Name)
+		}
+		if len(field.Names) == 0 {
+			unit.embedded = GetEmbeddedFieldName(field.Type)
+		}
+		end := field.End()
+		unit.kind = text(field.Type.Pos(), end)
+		if field.Comment != nil {
+			unit.comment = text(field.Comment.Pos(), field.Comment.End())
+			end = field.Comment.End()
+		}
+		unit.blank, unit.leading = splitFieldGap(unit.leading)
+		units = append(units, unit)
+		prevEnd = end
+	}
+	blank, trailing := splitFieldGap(text(prevEnd, structType.Fields.Closing))
+	if blank && trailing != "" {
+		trailing = "\n" + trailing
+	}
+	return units, trailing
+}
+
+// splitFieldGap returns whether the text between fields starts with a blank line, and the comments in the text.
+// The semicolon of fields in one line is skipped, like "struct{ A int; B int }".
+// splitFieldGap 返回字段之间的文本是否以空行开头，以及其中的注释。
+// 写在同一行的字段之间的分号会被跳过，比如 "struct{ A int; B int }"。
+func splitFieldGap(gap string) (bool, string) {
+	gap = strings.TrimPrefix(strings.TrimLeft(gap, " \t"), ";")
+	comments := strings.TrimSpace(gap)
+	prefix := gap
+	if comments != "" {
+		prefix = gap[:strings.Index(gap, comments)]
+	}
+	return strings.Count(prefix, "\n") >= 2, comments
+}
+
+// parseFieldUnits parses the code of fields into field units.
+// parseFieldUnits 将字段代码解析为字段单元。
+func parseFieldUnits(code string) ([]*fieldUnit, error) {
+	source := []byte("package p\n\ntype _ struct {\n" + code + "\n}\n")
+	astFile, err := parseSource(token.NewFileSet(), "", source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	typeSpec := astFile.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	units, trailing := newFieldUnits(source, astFile.FileStart, typeSpec.Type.(*ast.StructType))
+	if trailing != "" {
+		return nil, erero.Errorf("comments after the last field are not supported in the field code: %s", trailing)
+	}
+	return units, nil
+}
+
+func findFieldUnit(units []*fieldUnit, fieldName string) (int, bool) {
+	for idx, unit := range units {
+		if unit.embedded == fieldName || hasString(unit.names, fieldName) {
+			return idx, true
+		}
+	}
+	return -1, false
+}
+
+func hasListedName(names []string, listed map[string]bool) bool {
+	for _, name := range names {
+		if listed[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasString(items []string, item string) bool {
+	return indexOfName(items, item) >= 0
+}
@@ -0,0 +1,116 @@
+package syntaxgo_ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const structFieldSource = `package demo
+
+// Account is an account.
+type Account struct {
+	// Name is the name.
+	Name string ` + "`json:\"name\"`" + ` // the name
+	A, B int
+	*Base
+
+	CreatedAt int64
+	ID        int // primary key
+	// trailing comment
+}
+
+type Base struct{ Code string }
+`
+
+func TestAppendStructField(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(structFieldSource)))
+	astFile := astBundle.file
+	structType, ok := findStructType(astFile, "Account")
+	require.True(t, ok)
+
+	source := string(rese.V1(AppendStructField([]byte(structFieldSource), astFile, structType, "// Age is the age.\nAge int `json:\"age\"` // years")))
+	t.Log(source)
+	require.Contains(t, source, "\tID        int // primary key\n\t// Age is the age.\n\tAge int `json:\"age\"` // years\n\t// trailing comment\n}")
+	require.Contains(t, source, "\tName string `json:\"name\"` // the name\n")
+}
+
+func TestInsertStructFieldAfter(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(structFieldSource)))
+	astFile := astBundle.file
+	structType, ok := findStructType(astFile, "Account")
+	require.True(t, ok)
+
+	source := string(rese.V1(InsertStructFieldAfter([]byte(structFieldSource), astFile, structType, "A", "Mid string")))
+	t.Log(source)
+	require.Contains(t, source, "\tA    int\n\tMid  string\n\tB    int\n\t*Base\n\n\tCreatedAt int64\n")
+
+	source = string(rese.V1(InsertStructFieldAfter([]byte(structFieldSource), astFile, structType, "Base", "Code string")))
+	require.Contains(t, source, "\t*Base\n\tCode string\n")
+
+	_, err := InsertStructFieldAfter([]byte(structFieldSource), astFile, structType, "Unknown", "X int")
+	require.Error(t, err)
+	_, err = InsertStructFieldAfter([]byte(structFieldSource), astFile, structType, "A", "X int = 1")
+	require.Error(t, err)
+}
+
+func TestInsertStructFieldBefore(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(structFieldSource)))
+	astFile := astBundle.file
+	structType, ok := findStructType(astFile, "Account")
+	require.True(t, ok)
+
+	source := string(rese.V1(InsertStructFieldBefore([]byte(structFieldSource), astFile, structType, "Name", "UUID string")))
+	t.Log(source)
+	require.Contains(t, source, "type Account struct {\n\tUUID string\n\t// Name is the name.\n\tName string `json:\"name\"` // the name\n")
+}
+
+func TestReorderStructFields(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(structFieldSource)))
+	astFile := astBundle.file
+	structType, ok := findStructType(astFile, "Account")
+	require.True(t, ok)
+
+	source := string(rese.V1(ReorderStructFields([]byte(structFieldSource), astFile, structType, []string{"ID", "*", "B", "CreatedAt"})))
+	t.Log(source)
+	require.Contains(t, source, "type Account struct {\n\tID int // primary key\n\t// Name is the name.\n\tName string `json:\"name\"` // the name\n\tA    int\n\t*Base\n\tB int\n\n\tCreatedAt int64\n\t// trailing comment\n}")
+
+	source = string(rese.V1(ReorderStructFields([]byte(structFieldSource), astFile, structType, []string{"Base"})))
+	require.Contains(t, source, "type Account struct {\n\t*Base\n\t// Name is the name.\n")
+
+	_, err := ReorderStructFields([]byte(structFieldSource), astFile, structType, []string{"ID", "ID"})
+	require.Error(t, err)
+	_, err = ReorderStructFields([]byte(structFieldSource), astFile, structType, []string{"Unknown"})
+	require.Error(t, err)
+}
+
+func TestInsertStructFieldAfter_SplitKeepsBlankLine(t *testing.T) {
+	const source = "package demo\n\ntype Point struct {\n\tID int\n\n\t// X and Y are the coordinates.\n\tX, Y int\n}\n"
+	astBundle := rese.P1(NewAstBundleV1([]byte(source)))
+	structType, ok := findStructType(astBundle.file, "Point")
+	require.True(t, ok)
+
+	newSource := string(rese.V1(InsertStructFieldAfter([]byte(source), astBundle.file, structType, "X", "Z int")))
+	t.Log(newSource)
+	require.Contains(t, newSource, "\tID int\n\n\t// X and Y are the coordinates.\n\tX int\n\tZ int\n\tY int\n}")
+
+	newSource = string(rese.V1(ReorderStructFields([]byte(source), astBundle.file, structType, []string{"ID", "Y"})))
+	t.Log(newSource)
+	require.Contains(t, newSource, "\tID int\n\tY  int\n\n\t// X and Y are the coordinates.\n\tX int\n}")
+}
+
+func TestAstBundle_AppendStructField(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(structFieldSource)))
+
+	require.NoError(t, astBundle.AppendStructField("Base", "Kind string `json:\"kind\"`"))
+	require.True(t, rese.V1(astBundle.DeleteField("Account", "B")))
+	require.NoError(t, astBundle.ReorderStructFields("Account", []string{"ID"}))
+	require.Error(t, astBundle.AppendStructField("Unknown", "X int"))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "type Base struct {\n\tCode string\n\tKind string `json:\"kind\"`\n}")
+	require.Contains(t, source, "type Account struct {\n\tID int // primary key\n")
+	require.Contains(t, source, "\tA    int\n\t*Base\n\n\tCreatedAt int64\n")
+}
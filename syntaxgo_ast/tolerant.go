@@ -0,0 +1,70 @@
+package syntaxgo_ast
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+
+	"github.com/yyle88/erero"
+)
+
+// NewAstBundleTolerant parses the source keeping the partial file when it has syntax errors, like a half-written file.
+// The syntax errors are the diagnostics of the bundle, the error is only returned when no file could be parsed at all.
+// The broken parts of the file are Bad nodes like *ast.BadExpr, and a bundle with diagnostics is not saved,
+// since the parser repairs some errors and writing it would change the code without notice.
+// NewAstBundleTolerant 解析源代码，在存在语法错误时保留部分文件，比如写了一半的文件。
+// 语法错误作为 AST 包的诊断信息，只有完全无法解析出文件时才返回错误。
+// 文件中损坏的部分是 *ast.BadExpr 之类的 Bad 节点，带有诊断信息的 AST 包不会被保存，因为解析器会修复某些错误，写入它会在不被察觉的情况下改变代码。
+func NewAstBundleTolerant(fset *token.FileSet, name string, source []byte) (*AstBundle, error) {
+	astFile, diagnostics, err := parseTolerant(fset, name, source, parser.ParseComments)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astBundle := NewAstBundle(fset, astFile)
+	astBundle.diagnostics = diagnostics
+	return astBundle, nil
+}
+
+// NewAstBundleTolerantFile reads and parses the file like NewAstBundleTolerant, the bundle remembers its origin file.
+// NewAstBundleTolerantFile 像 NewAstBundleTolerant 一样读取并解析文件，AST 包会记录其来源文件。
+func NewAstBundleTolerantFile(path string) (*AstBundle, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astBundle, err := NewAstBundleTolerant(token.NewFileSet(), path, source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astBundle.setOrigin(path, source)
+	return astBundle, nil
+}
+
+// GetDiagnostics returns the syntax errors of a tolerant parse sorted by position, empty when the source is valid.
+// GetDiagnostics 返回宽容解析得到的语法错误，按位置排序，源代码有效时为空。
+func (ab *AstBundle) GetDiagnostics() scanner.ErrorList {
+	return ab.diagnostics
+}
+
+// HasDiagnostics tells whether the file is partial because of syntax errors.
+// HasDiagnostics 判断文件是否因为语法错误而不完整。
+func (ab *AstBundle) HasDiagnostics() bool {
+	return len(ab.diagnostics) > 0
+}
+
+// parseTolerant parses the source with all the errors, keeping the partial file when the errors are syntax errors.
+// parseTolerant 解析源代码并报告所有错误，错误为语法错误时保留部分文件。
+func parseTolerant(fset *token.FileSet, name string, source []byte, mode parser.Mode) (*ast.File, scanner.ErrorList, error) {
+	astFile, err := parser.ParseFile(fset, name, source, mode|parser.AllErrors)
+	if err != nil {
+		var errorList scanner.ErrorList
+		if !errors.As(err, &errorList) || astFile == nil {
+			return nil, nil, erero.Wro(err)
+		}
+		return astFile, errorList, nil
+	}
+	return astFile, nil, nil
+}
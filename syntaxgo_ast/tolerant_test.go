@@ -0,0 +1,77 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+)
+
+const halfWrittenSource = "package demo\n\nfunc A() {\n\tfoo(1 2)\n}\n\nfunc B() {\n\tfmt.Println(\"b\" 2)\n}\n"
+
+func TestNewAstBundleTolerant(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleTolerant(token.NewFileSet(), "a.go", []byte(halfWrittenSource)))
+	require.True(t, astBundle.HasDiagnostics())
+	require.Equal(t, "demo", astBundle.GetPackageName())
+
+	diagnostics := astBundle.GetDiagnostics()
+	t.Log(diagnostics)
+	require.Len(t, diagnostics, 2)
+	require.Equal(t, "a.go:4:8", diagnostics[0].Pos.String())
+	require.Equal(t, "missing ',' in argument list", diagnostics[0].Msg)
+	require.Equal(t, 8, diagnostics[1].Pos.Line)
+
+	// The partial file still has both functions.
+	// 部分文件中仍然包含两个函数。
+	astFile, _ := astBundle.GetBundle()
+	require.Len(t, astFile.Decls, 2)
+	require.Equal(t, "B", astFile.Decls[1].(*ast.FuncDecl).Name.Name)
+
+	// The parser repaired the call into "foo(1, 2)", saving the partial file would change the code.
+	// 解析器将调用修复为 "foo(1, 2)"，保存部分文件会改变代码。
+	require.Error(t, astBundle.SaveTo(filepath.Join(t.TempDir(), "a.go")))
+}
+
+func TestNewAstBundleTolerant_Valid(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleTolerant(token.NewFileSet(), "a.go", []byte("package demo\n")))
+	require.False(t, astBundle.HasDiagnostics())
+	require.Empty(t, astBundle.GetDiagnostics())
+}
+
+func TestNewAstBundleTolerant_NotGo(t *testing.T) {
+	// Without a package clause the parser stops, the file is empty but still there.
+	// 没有包声明时解析器停止解析，文件为空但仍然存在。
+	astBundle := rese.P1(NewAstBundleTolerant(token.NewFileSet(), "a.txt", []byte("hello world\n")))
+	require.True(t, astBundle.HasDiagnostics())
+	astFile, _ := astBundle.GetBundle()
+	require.Empty(t, astFile.Decls)
+}
+
+func TestNewAstBundleTolerantFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	done.Done(os.WriteFile(path, []byte(halfWrittenSource), 0644))
+
+	astBundle := rese.P1(NewAstBundleTolerantFile(path))
+	require.Equal(t, path, astBundle.GetPath())
+	require.Len(t, astBundle.GetDiagnostics(), 2)
+
+	_, err := NewAstBundleTolerantFile(filepath.Join(t.TempDir(), "missing.go"))
+	require.Error(t, err)
+}
+
+func TestLoader_SetTolerant(t *testing.T) {
+	fsys := fstest.MapFS{"demo/a.go": {Data: []byte(halfWrittenSource)}}
+
+	_, err := NewLoader().SetFS(fsys).LoadFile(token.NewFileSet(), "demo/a.go")
+	require.Error(t, err)
+
+	astBundles := rese.V1(NewLoader().SetFS(fsys).SetTolerant(true).LoadPackage(token.NewFileSet(), "demo"))
+	require.Len(t, astBundles, 1)
+	require.Len(t, astBundles[0].GetDiagnostics(), 2)
+}
@@ -0,0 +1,342 @@
+package syntaxgo_ast
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// UpsertFunc replaces the function declared by the code, or appends it at the end of the file when it is missing.
+// The code is a function declaration with an optional doc comment, the package clause is optional,
+// and the imports in the code are added to the file.
+// The doc comment of the existing function is kept when the code has no doc comment.
+// UpsertFunc 替换代码中声明的函数，当函数不存在时将其追加到文件末尾。
+// 代码是带有可选文档注释的函数声明，包声明是可选的，代码中的导入会被添加到文件中。
+// 当代码没有文档注释时，保留已有函数的文档注释。
+func (ab *AstBundle) UpsertFunc(code []byte) error {
+	snippet, err := parseSnippet(code)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	funcDecl, ok := snippet.onlyFuncDecl()
+	if !ok || funcDecl.Recv != nil {
+		return erero.New("code must declare exactly one function")
+	}
+	return ab.upsertSource(snippet, func(source []byte, astFile *ast.File) ([]byte, error) {
+		for _, decl := range astFile.Decls {
+			if item, ok := decl.(*ast.FuncDecl); ok && item.Recv == nil && item.Name.Name == funcDecl.Name.Name {
+				return formatEditedSource(replaceDecl(source, astFile, item, item.Doc, snippet, funcDecl, funcDecl.Doc))
+			}
+		}
+		return formatEditedSource(insertAfter(source, len(source), snippet.text(funcDecl, funcDecl.Doc)))
+	})
+}
+
+// UpsertMethod replaces the method of the receiver type, or inserts it after the last method of the receiver type,
+// or after the receiver type when the type has no method yet.
+// The code is a method declaration whose receiver type and name must match, the receiver can be a pointer or a value.
+// UpsertMethod 替换接收者类型的方法，当方法不存在时将其插入到接收者类型的最后一个方法之后，
+// 当该类型还没有方法时插入到接收者类型之后。
+// 代码是方法声明，其接收者类型和名称必须匹配，接收者可以是指针或值。
+func (ab *AstBundle) UpsertMethod(receiverName string, methodName string, code []byte) error {
+	snippet, err := parseSnippet(code)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	funcDecl, ok := snippet.onlyFuncDecl()
+	if !ok || funcDecl.Recv == nil {
+		return erero.New("code must declare exactly one method")
+	}
+	fullName := receiverName + "." + methodName
+	if name := funcDeclName(funcDecl); name != fullName {
+		return erero.Errorf("code declares method %s but not %s", name, fullName)
+	}
+	return ab.upsertSource(snippet, func(source []byte, astFile *ast.File) ([]byte, error) {
+		offset := len(source)
+		for _, decl := range astFile.Decls {
+			switch item := decl.(type) {
+			case *ast.FuncDecl:
+				name := funcDeclName(item)
+				if name == fullName {
+					return formatEditedSource(replaceDecl(source, astFile, item, item.Doc, snippet, funcDecl, funcDecl.Doc))
+				}
+				if item.Recv != nil && name == receiverName+"."+item.Name.Name {
+					offset = offsetOf(astFile, item.End())
+				}
+			case *ast.GenDecl:
+				if offset == len(source) && declaresType(item, receiverName) {
+					offset = offsetOf(astFile, item.End())
+				}
+			}
+		}
+		return formatEditedSource(insertAfter(source, offset, snippet.text(funcDecl, funcDecl.Doc)))
+	})
+}
+
+// UpsertType replaces the type declared by the code, or appends it at the end of the file when it is missing.
+// The code is a single type declaration, like "type Account struct{ Name string }".
+// UpsertType 替换代码中声明的类型，当类型不存在时将其追加到文件末尾。
+// 代码是单个类型声明，比如 "type Account struct{ Name string }"。
+func (ab *AstBundle) UpsertType(code []byte) error {
+	snippet, err := parseSnippet(code)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	genDecl, ok := snippet.onlyGenDecl(token.TYPE)
+	if !ok || len(genDecl.Specs) != 1 {
+		return erero.New("code must declare exactly one type")
+	}
+	return ab.upsertSpec(snippet, genDecl)
+}
+
+// UpsertConst replaces the consts declared by the code, or appends them after the last const declaration when they are missing.
+// A single const like "const A = 1" replaces the spec with the same name, even inside a group.
+// A const group replaces the whole group containing any of its names, so iota sequences stay intact,
+// it must declare all the names of that group, and the names must not be spread over several declarations.
+// UpsertConst 替换代码中声明的常量，当常量不存在时将其追加到最后一个常量声明之后。
+// 单个常量（比如 "const A = 1"）会替换同名的 spec，即使它位于分组声明中。
+// 常量分组会替换包含其任一名称的整个分组，因此 iota 序列保持完整，
+// 它必须声明该分组中的全部名称，并且这些名称不能分散在多个声明中。
+func (ab *AstBundle) UpsertConst(code []byte) error {
+	snippet, err := parseSnippet(code)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	genDecl, ok := snippet.onlyGenDecl(token.CONST)
+	if !ok {
+		return erero.New("code must be a const declaration")
+	}
+	if genDecl.Lparen == token.NoPos {
+		return ab.upsertSpec(snippet, genDecl)
+	}
+	newNames := declNames(genDecl)
+	return ab.upsertSource(snippet, func(source []byte, astFile *ast.File) ([]byte, error) {
+		offset := len(source)
+		var matchDecl *ast.GenDecl
+		for _, decl := range astFile.Decls {
+			item, ok := decl.(*ast.GenDecl)
+			if !ok || item.Tok != token.CONST {
+				continue
+			}
+			offset = offsetOf(astFile, item.End())
+			names := declNames(item)
+			if !slices.ContainsFunc(names, func(name string) bool { return slices.Contains(newNames, name) }) {
+				continue
+			}
+			if matchDecl != nil {
+				return nil, erero.Errorf("names %s are declared in several declarations, upsert them one by one", strings.Join(newNames, ", "))
+			}
+			for _, name := range names {
+				if !slices.Contains(newNames, name) {
+					return nil, erero.Errorf("%s is declared together with %s, upsert the whole group", strings.Join(newNames, ", "), name)
+				}
+			}
+			matchDecl = item
+		}
+		if matchDecl == nil {
+			return formatEditedSource(insertAfter(source, offset, snippet.text(genDecl, genDecl.Doc)))
+		}
+		return formatEditedSource(replaceDecl(source, astFile, matchDecl, matchDecl.Doc, snippet, genDecl, genDecl.Doc))
+	})
+}
+
+// upsertSpec replaces the spec declaring the names of the new spec, or inserts the declaration when it is missing.
+// The new declaration has a single spec without parentheses, it replaces the whole declaration or only the spec inside a group.
+// A spec like "const A, B = 1, 2" is matched by any of its names, and it is only replaced when the new spec declares all of them.
+// upsertSpec 替换声明了新 spec 中名称的 spec，当其不存在时插入该声明。
+// 新声明只有一个不带括号的 spec，它会替换整个声明，或者在分组中只替换该 spec。
+// 像 "const A, B = 1, 2" 这样的 spec 可以通过其任一名称匹配，仅当新 spec 声明了其全部名称时才会被替换。
+func (ab *AstBundle) upsertSpec(snippet *snippetFile, newDecl *ast.GenDecl) error {
+	newSpec := newDecl.Specs[0]
+	newSpecDoc := specDoc(newSpec)
+	if newSpecDoc == nil && newDecl.Lparen == token.NoPos {
+		newSpecDoc = newDecl.Doc
+	}
+	newNames := specNames(newSpec)
+	return ab.upsertSource(snippet, func(source []byte, astFile *ast.File) ([]byte, error) {
+		offset := len(source)
+		var matchDecl *ast.GenDecl
+		var matchSpec ast.Spec
+		for _, decl := range astFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != newDecl.Tok {
+				continue
+			}
+			if newDecl.Tok == token.CONST {
+				offset = offsetOf(astFile, genDecl.End())
+			}
+			for _, spec := range genDecl.Specs {
+				names := specNames(spec)
+				if !slices.ContainsFunc(names, func(name string) bool { return slices.Contains(newNames, name) }) {
+					continue
+				}
+				if matchSpec != nil {
+					return nil, erero.Errorf("names %s are declared in several specs, upsert them one by one", strings.Join(newNames, ", "))
+				}
+				for _, name := range names {
+					if !slices.Contains(newNames, name) {
+						return nil, erero.Errorf("%s is declared together with %s, upsert them together", strings.Join(newNames, ", "), name)
+					}
+				}
+				matchDecl, matchSpec = genDecl, spec
+			}
+		}
+		switch {
+		case matchSpec == nil:
+			return formatEditedSource(insertAfter(source, offset, snippet.text(newDecl, newDecl.Doc)))
+		case matchDecl.Lparen == token.NoPos:
+			return formatEditedSource(replaceDecl(source, astFile, matchDecl, matchDecl.Doc, snippet, newDecl, newDecl.Doc))
+		default:
+			return formatEditedSource(replaceDecl(source, astFile, matchSpec, specDoc(matchSpec), snippet, newSpec, newSpecDoc))
+		}
+	})
+}
+
+// upsertSource edits the formatted source code of the bundle, then adds the imports of the snippet.
+// upsertSource 编辑 AST 包格式化后的源代码，然后添加代码片段中的导入。
+func (ab *AstBundle) upsertSource(snippet *snippetFile, edit func(source []byte, astFile *ast.File) ([]byte, error)) error {
+	if err := ab.editSource(nil, func(source []byte, astFile *ast.File, _ ast.Node) ([]byte, error) {
+		return edit(source, astFile)
+	}); err != nil {
+		return erero.Wro(err)
+	}
+	for _, importSpec := range snippet.astFile.Imports {
+		path, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		if importSpec.Name != nil {
+			ab.AddNamedImport(importSpec.Name.Name, path)
+		} else {
+			ab.AddImport(path)
+		}
+	}
+	return nil
+}
+
+// snippetFile is the parsed code of a declaration.
+// snippetFile 是解析后的声明代码。
+type snippetFile struct {
+	source  []byte
+	astFile *ast.File
+}
+
+// parseSnippet parses the code, a package clause is added when the code has none.
+// parseSnippet 解析代码，当代码没有包声明时会补上包声明。
+func parseSnippet(code []byte) (*snippetFile, error) {
+	if _, err := parser.ParseFile(token.NewFileSet(), "", code, parser.PackageClauseOnly); err != nil {
+		code = append([]byte("package snippet\n\n"), code...)
+	}
+	astFile, err := parseSource(token.NewFileSet(), "", code)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return &snippetFile{source: code, astFile: astFile}, nil
+}
+
+func (snippet *snippetFile) onlyFuncDecl() (*ast.FuncDecl, bool) {
+	var result *ast.FuncDecl
+	for _, decl := range snippet.astFile.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			continue
+		}
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || result != nil {
+			return nil, false
+		}
+		result = funcDecl
+	}
+	return result, result != nil
+}
+
+func (snippet *snippetFile) onlyGenDecl(tok token.Token) (*ast.GenDecl, bool) {
+	var result *ast.GenDecl
+	for _, decl := range snippet.astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok == token.IMPORT {
+			continue
+		}
+		if result != nil || genDecl.Tok != tok {
+			return nil, false
+		}
+		result = genDecl
+	}
+	return result, result != nil
+}
+
+// text returns the code of the declaration together with the doc comment.
+// text 返回声明及其文档注释的代码。
+func (snippet *snippetFile) text(node ast.Node, doc *ast.CommentGroup) []byte {
+	return snippet.join(doc, snippet.slice(node))
+}
+
+// join joins the doc comment and the code with a newline, the doc comment can be nil.
+// join 用换行连接文档注释和代码，文档注释可以为 nil。
+func (snippet *snippetFile) join(doc *ast.CommentGroup, code []byte) []byte {
+	if doc == nil {
+		return code
+	}
+	return append(append(snippet.slice(doc), '\n'), code...)
+}
+
+func (snippet *snippetFile) slice(node ast.Node) []byte {
+	return append([]byte{}, snippet.source[offsetOf(snippet.astFile, node.Pos()):offsetOf(snippet.astFile, node.End())]...)
+}
+
+// replaceDecl replaces the old node and its doc comment with the new node, the old doc comment is kept when the new node has none.
+// replaceDecl 用新节点替换旧节点及其文档注释，当新节点没有文档注释时保留旧的文档注释。
+func replaceDecl(source []byte, astFile *ast.File, oldNode ast.Node, oldDoc *ast.CommentGroup, snippet *snippetFile, newNode ast.Node, newDoc *ast.CommentGroup) []byte {
+	start := oldNode.Pos()
+	if oldDoc != nil && newDoc != nil {
+		start = oldDoc.Pos()
+	}
+	return replaceRange(source, offsetOf(astFile, start), offsetOf(astFile, oldNode.End()), snippet.text(newNode, newDoc))
+}
+
+// insertAfter inserts the code after the line of the offset, separated with a blank line.
+// insertAfter 在偏移量所在行之后插入代码，用空行分隔。
+func insertAfter(source []byte, offset int, code []byte) []byte {
+	offset = lineEnd(source, offset)
+	prefix := "\n"
+	if offset > 0 && source[offset-1] != '\n' {
+		prefix = "\n\n"
+	}
+	return replaceRange(source, offset, offset, append(append([]byte(prefix), code...), '\n'))
+}
+
+func declaresType(genDecl *ast.GenDecl, typeName string) bool {
+	if genDecl.Tok != token.TYPE {
+		return false
+	}
+	for _, spec := range genDecl.Specs {
+		if slices.Contains(specNames(spec), typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// declNames returns the names declared by the specs of the declaration.
+// declNames 返回声明中各个 spec 声明的名称。
+func declNames(genDecl *ast.GenDecl) []string {
+	var names []string
+	for _, spec := range genDecl.Specs {
+		names = append(names, specNames(spec)...)
+	}
+	return names
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch item := spec.(type) {
+	case *ast.TypeSpec:
+		return item.Doc
+	case *ast.ValueSpec:
+		return item.Doc
+	}
+	return nil
+}
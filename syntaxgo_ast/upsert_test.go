@@ -0,0 +1,136 @@
+package syntaxgo_ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const upsertSource = `package demo
+
+// Kind is the kind.
+type Kind int
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+// Account is an account.
+type Account struct {
+	Name string
+}
+
+// GetName returns the name.
+func (a *Account) GetName() string { return "" }
+
+type Role string
+
+// Hello says hello.
+func Hello() string { return "hello" }
+`
+
+func TestAstBundle_UpsertFunc(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(upsertSource)))
+
+	require.NoError(t, astBundle.UpsertFunc([]byte(`func Hello() string { return "hi" }`)))
+	require.NoError(t, astBundle.UpsertFunc([]byte(`import "strings"
+
+// Upper returns the upper name.
+func Upper(name string) string { return strings.ToUpper(name) }`)))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "// Hello says hello.\nfunc Hello() string { return \"hi\" }\n")
+	require.NotContains(t, source, `"hello"`)
+	require.Contains(t, source, "import \"strings\"\n")
+	require.Contains(t, source, "return \"hi\" }\n\n// Upper returns the upper name.\nfunc Upper(name string) string")
+
+	require.Error(t, astBundle.UpsertFunc([]byte(`func (a *Account) Hello() {}`)))
+	require.Error(t, astBundle.UpsertFunc([]byte(`func A() {}; func B() {}`)))
+}
+
+func TestAstBundle_UpsertMethod(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(upsertSource)))
+
+	require.NoError(t, astBundle.UpsertMethod("Account", "GetName", []byte(`// GetName returns the name of the account.
+func (a *Account) GetName() string { return a.Name }`)))
+	require.NoError(t, astBundle.UpsertMethod("Account", "SetName", []byte(`func (a *Account) SetName(name string) { a.Name = name }`)))
+	require.NoError(t, astBundle.UpsertMethod("Role", "String", []byte(`func (r Role) String() string { return string(r) }`)))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "// GetName returns the name of the account.\nfunc (a *Account) GetName() string { return a.Name }\n\nfunc (a *Account) SetName(name string) { a.Name = name }\n\ntype Role string\n")
+	require.Contains(t, source, "type Role string\n\nfunc (r Role) String() string { return string(r) }\n\n// Hello says hello.")
+
+	require.Error(t, astBundle.UpsertMethod("Account", "Other", []byte(`func (a *Account) SetName(name string) {}`)))
+}
+
+func TestAstBundle_UpsertType(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(upsertSource)))
+
+	require.NoError(t, astBundle.UpsertType([]byte("type Account struct {\n\tName string\n\tAge int\n}")))
+	require.NoError(t, astBundle.UpsertType([]byte("// Group is a group.\ntype Group struct{}")))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "// Account is an account.\ntype Account struct {\n\tName string\n\tAge  int\n}\n")
+	require.Contains(t, source, "\n// Group is a group.\ntype Group struct{}\n")
+
+	require.Error(t, astBundle.UpsertType([]byte("const A = 1")))
+}
+
+func TestAstBundle_UpsertConst(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte(upsertSource)))
+
+	require.NoError(t, astBundle.UpsertConst([]byte("const KindB Kind = 5")))
+	require.NoError(t, astBundle.UpsertConst([]byte("// Limit is the limit.\nconst Limit = 10")))
+
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "const (\n\tKindA Kind = iota\n\tKindB Kind = 5\n)\n\n// Limit is the limit.\nconst Limit = 10\n")
+
+	require.NoError(t, astBundle.UpsertConst([]byte("const (\n\tKindA Kind = iota + 1\n\tKindB\n\tKindC\n)")))
+	source = string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Contains(t, source, "const (\n\tKindA Kind = iota + 1\n\tKindB\n\tKindC\n)\n")
+	require.NotContains(t, source, "KindB Kind = 5")
+}
+
+func TestAstBundle_UpsertConst_MultiName(t *testing.T) {
+	astBundle := rese.P1(NewAstBundleV1([]byte("package demo\n\nconst A, B = 1, 2\n\nconst (\n\tX, Y = 3, 4\n)\n")))
+
+	// A spec declaring several names is found by any of its names, instead of adding a duplicate.
+	// 声明多个名称的 spec 可以通过其任一名称找到，而不是添加重复的声明。
+	require.NoError(t, astBundle.UpsertConst([]byte("const B, A = 5, 6")))
+	require.NoError(t, astBundle.UpsertConst([]byte("const Y, X = 7, 8")))
+	source := string(rese.V1(astBundle.FormatSource()))
+	t.Log(source)
+	require.Equal(t, "package demo\n\nconst B, A = 5, 6\n\nconst (\n\tY, X = 7, 8\n)\n", source)
+
+	// Replacing only some names of the spec would drop the others.
+	// 只替换 spec 中的部分名称会丢掉其它名称。
+	require.Error(t, astBundle.UpsertConst([]byte("const B = 2")))
+	require.Error(t, astBundle.UpsertConst([]byte("const Y = 2")))
+	require.Error(t, astBundle.UpsertConst([]byte("const A, X = 1, 2")))
+	require.Equal(t, source, string(rese.V1(astBundle.FormatSource())))
+}
+
+func TestAstBundle_UpsertConst_GroupNames(t *testing.T) {
+	const source = "package demo\n\nconst (\n\tA = 1\n\tB = 2\n\tC = 3\n)\n\nconst D = 4\n"
+	astBundle := rese.P1(NewAstBundleV1([]byte(source)))
+
+	// A group replacing only some names of the old group would drop the others.
+	// 只替换旧分组中部分名称的分组会丢掉其它名称。
+	require.Error(t, astBundle.UpsertConst([]byte("const (\n\tA = 10\n)")))
+	// The names are spread over two declarations.
+	// 这些名称分散在两个声明中。
+	require.Error(t, astBundle.UpsertConst([]byte("const (\n\tA = 10\n\tB = 20\n\tC = 30\n\tD = 40\n)")))
+	require.Equal(t, source, string(rese.V1(astBundle.FormatSource())))
+
+	require.NoError(t, astBundle.UpsertConst([]byte("const (\n\tC = 30\n\tB = 20\n\tA = 10\n)")))
+	newSource := string(rese.V1(astBundle.FormatSource()))
+	t.Log(newSource)
+	require.Equal(t, "package demo\n\nconst (\n\tC = 30\n\tB = 20\n\tA = 10\n)\n\nconst D = 4\n", newSource)
+}
@@ -15,9 +15,12 @@ func SdxEdx(astNode ast.Node) (sdx, edx int) {
 }
 
 // GetCode returns the code corresponding to the given AST node from the source.
+// The end is limited to the source, since nodes of a partial file with syntax errors can end past it.
 // GetCode 从源代码中返回与给定 AST 节点对应的代码。
+// 结尾被限制在源代码之内，因为带有语法错误的部分文件中的节点可能结束于源代码之后。
 func GetCode(source []byte, astNode ast.Node) []byte {
-	return source[astNode.Pos()-1 : astNode.End()-1]
+	end := min(int(astNode.End()-1), len(source))
+	return source[min(int(astNode.Pos()-1), end):end]
 }
 
 // GetText returns the text corresponding to the given AST node from the source.
@@ -21,6 +21,11 @@ func TestGetCode(t *testing.T) {
 	require.Equal(t, "ab", string(code))
 }
 
+func TestGetCode_PastEnd(t *testing.T) {
+	require.Equal(t, "bc", string(GetCode([]byte("abc"), NewNode(2, 9))))
+	require.Equal(t, "", string(GetCode([]byte("abc"), NewNode(8, 9))))
+}
+
 func TestGetText(t *testing.T) {
 	node := NewNode(1, 3)
 	text := GetText([]byte("abc"), node)
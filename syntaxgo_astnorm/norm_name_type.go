@@ -2,6 +2,7 @@ package syntaxgo_astnorm
 
 import (
 	"go/ast"
+	"go/types"
 	"strings"
 
 	"github.com/yyle88/must"
@@ -48,6 +49,13 @@ func (element *NameTypeElement) AdjustTypeWithPackage(
 ) {
 	shortKind, adjusted := adjustKindWithPackage(strings.TrimSpace(element.Kind), packageName, genericTypeParams, element.IsEllipsis)
 	if !adjusted {
+		// Composite types like []A or map[string]*A are qualified through the AST.
+		// 像 []A 或 map[string]*A 这样的复合类型通过 AST 补充包名。
+		if element.Type != nil {
+			if qualifiedKind, ok := QualifyTypeWithPackage(element.Type, packageName, genericTypeParams); ok {
+				element.Kind = qualifiedKind
+			}
+		}
 		return
 	}
 	element.Kind = shortKind
@@ -115,6 +123,38 @@ func NewNameTypeElements(
 	return ExtractNameTypeElements(fieldList.List, nameFunc, source, packageName, genericTypeParams)
 }
 
+// NewNameTypeElementsV2 creates a list of NameTypeElements without source code, the types are printed from the AST.
+// It is suitable for merged package bundles, where node positions do not belong to a single source.
+// NewNameTypeElementsV2 在没有源代码的情况下创建 NameTypeElements 列表，类型文本由 AST 打印得到。
+// 适用于合并后的包语法树，因为这时节点位置并不属于同一份源代码。
+func NewNameTypeElementsV2(
+	fieldList *ast.FieldList, // AST field list for function parameters / AST 字段列表，表示函数参数
+	nameFunc MakeNameFunction, // Function to generate parameter names / 用于生成参数名称的函数
+	packageName string, // Package name for external types / 外部类型的包名
+	genericTypeParams map[string]ast.Expr, // Map of generic type parameters / 泛型类型参数的映射
+) NameTypeElements {
+	var elements = make(NameTypeElements, 0) // Create an empty elements list / 创建一个空的元素列表
+	if fieldList == nil {
+		return elements
+	}
+	var anonymousCount = 0 // Counter for anonymous fields / 匿名字段计数器
+	for _, field := range fieldList.List {
+		_, isVariadic := field.Type.(*ast.Ellipsis)
+		stringType := types.ExprString(field.Type) // Print the type, variadic types start with "..." / 打印类型，变参类型以 "..." 开头
+		if len(field.Names) > 0 {
+			for _, fieldName := range field.Names {
+				paramName := nameFunc(fieldName, stringType, len(elements), 0)
+				elements = append(elements, NewNameTypeElement(field, paramName, stringType, isVariadic, packageName, genericTypeParams))
+			}
+		} else {
+			paramName := nameFunc(nil, stringType, len(elements), anonymousCount)
+			elements = append(elements, NewNameTypeElement(field, paramName, stringType, isVariadic, packageName, genericTypeParams))
+			anonymousCount++
+		}
+	}
+	return elements
+}
+
 // ExtractNameTypeElements extracts NameTypeElements from the AST fields.
 // ExtractNameTypeElements 从 AST 字段中提取 NameTypeElements。
 func ExtractNameTypeElements(
@@ -0,0 +1,54 @@
+package syntaxgo_astnorm
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// QualifyTypeWithPackage prints the type expression with the package name added to each exported type, such as []A to []pkg.A.
+// Predeclared types, generic type parameters and types that already contain a package name are unchanged.
+// QualifyTypeWithPackage 打印类型表达式，并为其中每个可导出的类型补充包名，比如把 []A 变为 []pkg.A。
+// 预声明类型、泛型类型参数以及已经带包名的类型保持不变。
+func QualifyTypeWithPackage(typeExpr ast.Expr, packageName string, genericTypeParams map[string]ast.Expr) (string, bool) {
+	if ellipsis, ok := typeExpr.(*ast.Ellipsis); ok {
+		// The parser can not parse "...T" as an expression, so qualify the element type.
+		// 解析器无法把 "...T" 作为表达式解析，因此只处理元素类型。
+		elemKind, adjusted := QualifyTypeWithPackage(ellipsis.Elt, packageName, genericTypeParams)
+		return "..." + elemKind, adjusted
+	}
+	// Parse the printed type to get a copy of the expression, so the original AST is unchanged.
+	// 通过解析打印结果得到表达式的副本，以保证原始语法树不被修改。
+	exprCopy, err := parser.ParseExpr(types.ExprString(typeExpr))
+	if err != nil {
+		return types.ExprString(typeExpr), false
+	}
+	var adjusted = false
+	newExpr := astutil.Apply(exprCopy, func(c *astutil.Cursor) bool {
+		switch c.Parent().(type) {
+		case *ast.SelectorExpr:
+			return false // Already qualified, like pkg.A / 已经带有包名，比如 pkg.A
+		case *ast.Field:
+			if c.Name() == "Names" {
+				return false // Names of params or fields / 参数或字段的名称
+			}
+		}
+		ident, ok := c.Node().(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if !ident.IsExported() || types.Universe.Lookup(ident.Name) != nil {
+			return true // Not-exportable-type or basic-type / 非导出类型或基础类型
+		}
+		if _, ok := genericTypeParams[ident.Name]; ok {
+			return true // It's a generic type / 是泛型类型
+		}
+		c.Replace(&ast.SelectorExpr{X: ast.NewIdent(packageName), Sel: ast.NewIdent(ident.Name)})
+		adjusted = true
+		return true
+	}, nil)
+	return strings.TrimSpace(types.ExprString(newExpr.(ast.Expr))), adjusted
+}
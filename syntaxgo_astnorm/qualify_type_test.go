@@ -0,0 +1,40 @@
+package syntaxgo_astnorm
+
+import (
+	"go/ast"
+	"go/parser"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+func TestQualifyTypeWithPackage(t *testing.T) {
+	genericTypeParams := map[string]ast.Expr{"T": ast.NewIdent("any")}
+
+	check := func(kind string, expected string) {
+		typeExpr := rese.V1(parser.ParseExpr(kind))
+		qualifiedKind, _ := QualifyTypeWithPackage(typeExpr, "pkg", genericTypeParams)
+		t.Log(kind, "->", qualifiedKind)
+		require.Equal(t, expected, qualifiedKind)
+	}
+	check("[]A", "[]pkg.A")
+	check("map[string]*A", "map[string]*pkg.A")
+	check("func(a A, b int) (time.Time, error)", "func(a pkg.A, b int) (time.Time, error)")
+	check("chan<- []T", "chan<- []T")
+	check("List[A, T]", "pkg.List[pkg.A, T]")
+	check("struct{ Name A }", "struct{Name pkg.A}")
+	check("[]a", "[]a")
+}
+
+func TestNewNameTypeElementsV2(t *testing.T) {
+	funcType := rese.V1(parser.ParseExpr("func(a, b A, items []A, opts ...*Option) (map[string]A, error)")).(*ast.FuncType)
+
+	params := NewNameTypeElementsV2(funcType.Params, SimpleMakeNameFunction("arg"), "pkg", nil)
+	require.Equal(t, []string{"pkg.A", "pkg.A", "[]pkg.A", "...*pkg.Option"}, params.Kinds())
+	require.Equal(t, StatementParts{"a", "b", "items", "opts..."}, params.GenerateFunctionParams())
+
+	results := NewNameTypeElementsV2(funcType.Results, SimpleMakeNameFunction("res"), "pkg", nil)
+	require.Equal(t, []string{"map[string]pkg.A", "error"}, results.Kinds())
+	require.Equal(t, StatementParts{"res", "err1"}, results.Names())
+}
@@ -0,0 +1,161 @@
+package syntaxgo_astnorm
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// basicZeroValues maps predeclared type names to their zero-value expressions.
+// basicZeroValues 记录预声明类型名称与其零值表达式的映射。
+var basicZeroValues = map[string]string{
+	"bool":       "false",
+	"string":     `""`,
+	"int":        "0",
+	"int8":       "0",
+	"int16":      "0",
+	"int32":      "0",
+	"int64":      "0",
+	"uint":       "0",
+	"uint8":      "0",
+	"uint16":     "0",
+	"uint32":     "0",
+	"uint64":     "0",
+	"uintptr":    "0",
+	"byte":       "0",
+	"rune":       "0",
+	"float32":    "0",
+	"float64":    "0",
+	"complex64":  "0",
+	"complex128": "0",
+	"error":      "nil",
+	"any":        "nil",
+}
+
+// ZeroValue returns the zero-value expression of the element's type, such as 0, "", false, nil, T{} or *new(T).
+// ZeroValue 返回元素类型的零值表达式，比如 0、""、false、nil、T{} 或者 *new(T)。
+func (element *NameTypeElement) ZeroValue(genericTypeParams map[string]ast.Expr) string {
+	return element.ZeroValueWithTypesInfo(genericTypeParams, nil)
+}
+
+// ZeroValueWithTypesInfo returns the zero-value expression, using type-checker info to resolve named types when available.
+// ZeroValueWithTypesInfo 返回零值表达式，当提供类型检查信息时使用它来解析具名类型。
+func (element *NameTypeElement) ZeroValueWithTypesInfo(genericTypeParams map[string]ast.Expr, info *types.Info) string {
+	if element.IsEllipsis {
+		return "nil" // Variadic parameter is a slice / 变参实际是切片
+	}
+	return MakeZeroValue(element.Type, strings.TrimSpace(element.Kind), genericTypeParams, info)
+}
+
+// ZeroValues returns the zero-value expressions of the elements, useful for statements like "return res, err".
+// ZeroValues 返回所有元素的零值表达式，便于生成类似 "return res, err" 的语句。
+func (elements NameTypeElements) ZeroValues(genericTypeParams map[string]ast.Expr) StatementParts {
+	return elements.ZeroValuesWithTypesInfo(genericTypeParams, nil)
+}
+
+// ZeroValuesWithTypesInfo returns the zero-value expressions of the elements, using type-checker info when available.
+// ZeroValuesWithTypesInfo 返回所有元素的零值表达式，当提供类型检查信息时使用它。
+func (elements NameTypeElements) ZeroValuesWithTypesInfo(genericTypeParams map[string]ast.Expr, info *types.Info) StatementParts {
+	var results = make([]string, 0, len(elements))
+	for _, element := range elements {
+		results = append(results, element.ZeroValueWithTypesInfo(genericTypeParams, info))
+	}
+	return results
+}
+
+// MakeZeroValue returns the zero-value expression of a type expression, where kind is the type text used in generated code.
+// Without type-checker info, named types declared in the same file are resolved through the AST, and unknown types fall back to *new(T).
+// MakeZeroValue 返回类型表达式的零值表达式，其中 kind 是生成代码时使用的类型文本。
+// 没有类型检查信息时，同文件内声明的具名类型通过 AST 解析，未知类型则退化为 *new(T)。
+func MakeZeroValue(typeExpr ast.Expr, kind string, genericTypeParams map[string]ast.Expr, info *types.Info) string {
+	if kind == "" && typeExpr != nil {
+		kind = types.ExprString(typeExpr)
+	}
+	if info != nil && typeExpr != nil {
+		if typ := info.TypeOf(typeExpr); typ != nil && typ != types.Typ[types.Invalid] {
+			return MakeTypesZeroValue(typ, kind)
+		}
+	}
+	return makeExprZeroValue(typeExpr, kind, genericTypeParams, map[*ast.TypeSpec]bool{})
+}
+
+// MakeTypesZeroValue returns the zero-value expression of a go/types type, where kind is the type text used in generated code.
+// MakeTypesZeroValue 返回 go/types 类型的零值表达式，其中 kind 是生成代码时使用的类型文本。
+func MakeTypesZeroValue(typ types.Type, kind string) string {
+	if kind == "" {
+		kind = typ.String()
+	}
+	if _, ok := typ.(*types.TypeParam); ok {
+		return "*new(" + kind + ")" // Type parameter has no literal zero value / 泛型参数没有字面零值
+	}
+	switch utp := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case utp.Info()&types.IsBoolean != 0:
+			return "false"
+		case utp.Info()&types.IsString != 0:
+			return `""`
+		case utp.Info()&types.IsNumeric != 0:
+			return "0"
+		default:
+			return "nil" // unsafe.Pointer and untyped nil / unsafe.Pointer 以及无类型 nil
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil"
+	case *types.Struct, *types.Array:
+		return kind + "{}"
+	default:
+		return "*new(" + kind + ")"
+	}
+}
+
+// makeExprZeroValue resolves the zero value through the syntax of the type expression.
+// makeExprZeroValue 通过类型表达式的语法解析零值。
+func makeExprZeroValue(typeExpr ast.Expr, kind string, genericTypeParams map[string]ast.Expr, visited map[*ast.TypeSpec]bool) string {
+	switch node := typeExpr.(type) {
+	case *ast.ParenExpr:
+		return makeExprZeroValue(node.X, kind, genericTypeParams, visited)
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.Ellipsis:
+		return "nil"
+	case *ast.ArrayType:
+		if node.Len == nil {
+			return "nil" // Slice type / 切片类型
+		}
+		return kind + "{}"
+	case *ast.StructType:
+		return kind + "{}"
+	case *ast.Ident:
+		if _, ok := genericTypeParams[node.Name]; ok {
+			return "*new(" + kind + ")"
+		}
+		if node.Obj != nil && node.Obj.Kind == ast.Typ {
+			if typeSpec, ok := node.Obj.Decl.(*ast.TypeSpec); ok {
+				return makeTypeSpecZeroValue(typeSpec, kind, genericTypeParams, visited)
+			}
+		}
+		if value, ok := basicZeroValues[node.Name]; ok && node.Obj == nil {
+			return value
+		}
+	case *ast.IndexExpr:
+		return makeExprZeroValue(node.X, kind, genericTypeParams, visited)
+	case *ast.IndexListExpr:
+		return makeExprZeroValue(node.X, kind, genericTypeParams, visited)
+	}
+	return "*new(" + kind + ")" // Unknown type, this expression is valid for any type / 未知类型，该表达式适用于任意类型
+}
+
+// makeTypeSpecZeroValue resolves the zero value of a named type through its declaration in the same file.
+// makeTypeSpecZeroValue 通过同文件内的类型声明解析具名类型的零值。
+func makeTypeSpecZeroValue(typeSpec *ast.TypeSpec, kind string, genericTypeParams map[string]ast.Expr, visited map[*ast.TypeSpec]bool) string {
+	if visited[typeSpec] {
+		return "*new(" + kind + ")" // Avoid infinite recursion on invalid recursive types / 避免非法递归类型导致无限递归
+	}
+	visited[typeSpec] = true
+	switch typeSpec.Type.(type) {
+	case *ast.StructType, *ast.ArrayType:
+		if typeSpec.TypeParams != nil && !strings.Contains(kind, "[") {
+			return "*new(" + kind + ")" // Generic type without type arguments / 泛型类型缺少类型实参
+		}
+	}
+	return makeExprZeroValue(typeSpec.Type, kind, genericTypeParams, visited)
+}
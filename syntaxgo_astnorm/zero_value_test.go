@@ -0,0 +1,85 @@
+package syntaxgo_astnorm
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+const zeroValueSource = `package demo
+
+import "time"
+
+type Account struct {
+	Name string
+}
+
+type Status int
+
+type Names []string
+
+type Reader interface {
+	Read() string
+}
+
+func Demo[T any](a int, s string, b bool, p *Account, v Account, st Status, ns Names, r Reader, m map[string]int, x [2]int, t T, tm time.Time, err error, args ...int) {
+}
+`
+
+func TestNameTypeElements_ZeroValues(t *testing.T) {
+	source := []byte(zeroValueSource)
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1(source))
+	astFile, _ := astBundle.GetBundle()
+
+	resFunc := syntaxgo_search.FindFunctionByName(astFile, "Demo")
+	require.NotNil(t, resFunc)
+
+	genericTypeParams := GetFuncGenericTypeParamsMap(resFunc)
+	elements := GetSimpleArgElements(resFunc.Type.Params.List, source)
+	zeroValues := elements.ZeroValues(genericTypeParams)
+	t.Log(zeroValues.MergeParts())
+	require.Equal(t, StatementParts{
+		"0", `""`, "false", "nil", "Account{}", "0", "nil", "nil", "nil", "[2]int{}", "*new(T)", "*new(time.Time)", "nil", "nil",
+	}, zeroValues)
+}
+
+func TestNameTypeElements_ZeroValuesWithTypesInfo(t *testing.T) {
+	fset := token.NewFileSet()
+	source := []byte(zeroValueSource)
+	astFile := rese.P1(parser.ParseFile(fset, "", source, parser.ParseComments))
+
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	config := &types.Config{Importer: importer.Default(), Error: func(err error) { t.Log(err) }}
+	_, _ = config.Check("demo", fset, []*ast.File{astFile}, info)
+
+	resFunc := syntaxgo_search.FindFunctionByName(astFile, "Demo")
+	require.NotNil(t, resFunc)
+
+	elements := GetSimpleArgElements(resFunc.Type.Params.List, source)
+	zeroValues := elements.ZeroValuesWithTypesInfo(GetFuncGenericTypeParamsMap(resFunc), info)
+	t.Log(zeroValues.MergeParts())
+	require.Equal(t, "Account{}", zeroValues[4])
+	require.Equal(t, "*new(T)", zeroValues[10])
+	require.Equal(t, "time.Time{}", zeroValues[11])
+}
+
+func TestNameTypeElement_ZeroValue(t *testing.T) {
+	source := []byte(zeroValueSource)
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1(source))
+	astFile, _ := astBundle.GetBundle()
+
+	resFunc := syntaxgo_search.FindFunctionByName(astFile, "Demo")
+	require.NotNil(t, resFunc)
+
+	elements := NewNameTypeElements(resFunc.Type.Params, SimpleMakeNameFunction("arg"), source, "demo", GetFuncGenericTypeParamsMap(resFunc))
+	require.Equal(t, "demo.Account{}", elements[4].ZeroValue(GetFuncGenericTypeParamsMap(resFunc)))
+	require.Equal(t, "nil", elements[3].ZeroValue(GetFuncGenericTypeParamsMap(resFunc)))
+}
@@ -0,0 +1,275 @@
+package syntaxgo_batch
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_diff"
+)
+
+/*
+Package `syntaxgo_batch` applies the same transformation to all Go files in a module tree, like a tag fix, an import injection or a rename.
+
+Key features include:
+  - Walking the module tree, skipping vendor, testdata, hidden directories, nested modules and generated files.
+  - Parsing and transforming the files concurrently with a bounded worker pool.
+  - Collecting the errors per file without aborting, a panic in the transformation is reported as the error of that file.
+  - Writing the results atomically, or computing unified diffs in dry-run mode, with a summary report.
+*/
+
+/*
+Package `syntaxgo_batch` 对模块树中的所有 Go 文件应用同一个转换，比如修正标签、注入导入或者重命名。
+
+主要功能包括：
+  - 遍历模块树，跳过 vendor、testdata、隐藏目录、嵌套模块以及生成的文件。
+  - 使用有界的工作池并发地解析和转换文件。
+  - 按文件收集错误而不中止，转换中的 panic 会作为该文件的错误报告。
+  - 以原子方式写入结果，或在试运行模式下计算统一差异，并提供汇总报告。
+*/
+
+// Transform transforms the bundle of a file, and reports whether it changed the bundle.
+// Files are only written when the bundle is changed, so untouched files keep their formatting.
+// Transform 转换一个文件的 AST 包，并报告是否修改了 AST 包。
+// 只有 AST 包被修改时才会写入文件，因此未被修改的文件保持其原有格式。
+type Transform func(astBundle *syntaxgo_ast.AstBundle) (bool, error)
+
+// Options configures the batch run.
+// Options 配置批量运行。
+type Options struct {
+	workers          int  // Number of files processed at the same time / 同时处理的文件数量
+	dryRun           bool // Compute the changes without writing / 只计算变更而不写入
+	includeGenerated bool // Transform the generated files too / 同时转换生成的文件
+	includeTests     bool // Transform the _test.go files too / 同时转换 _test.go 文件
+}
+
+// NewOptions creates Options that uses one worker per CPU and writes the changes, test files are included and generated files are skipped.
+// NewOptions 创建每个 CPU 使用一个工作者并写入变更的 Options，包含测试文件并跳过生成的文件。
+func NewOptions() *Options {
+	return &Options{workers: runtime.NumCPU(), includeTests: true}
+}
+
+// SetWorkers sets the number of files processed at the same time, at least 1.
+// SetWorkers 设置同时处理的文件数量，至少为 1。
+func (options *Options) SetWorkers(workers int) *Options {
+	options.workers = max(workers, 1)
+	return options
+}
+
+// SetDryRun sets whether to compute the changes without writing, the results have diffs to review.
+// SetDryRun 设置是否只计算变更而不写入，结果带有差异以供审阅。
+func (options *Options) SetDryRun(dryRun bool) *Options {
+	options.dryRun = dryRun
+	return options
+}
+
+// SetIncludeGenerated sets whether to transform the files with the "// Code generated ... DO NOT EDIT." header.
+// SetIncludeGenerated 设置是否转换带有 "// Code generated ... DO NOT EDIT." 头部的文件。
+func (options *Options) SetIncludeGenerated(includeGenerated bool) *Options {
+	options.includeGenerated = includeGenerated
+	return options
+}
+
+// SetIncludeTests sets whether to transform the _test.go files.
+// SetIncludeTests 设置是否转换 _test.go 文件。
+func (options *Options) SetIncludeTests(includeTests bool) *Options {
+	options.includeTests = includeTests
+	return options
+}
+
+// FileResult is the result of transforming a file.
+// FileResult 是转换一个文件的结果。
+type FileResult struct {
+	Path        string // File path / 文件路径
+	Original    []byte // Source before the transformation / 转换之前的源代码
+	Transformed []byte // Source after the transformation, nil when not changed / 转换之后的源代码，未变更时为 nil
+	Generated   bool   // Skipped as a generated file / 作为生成的文件被跳过
+	Err         error  // Error of parsing, transforming or writing the file / 解析、转换或写入文件时的错误
+}
+
+// IsChanged reports whether the transformation changed the file.
+// IsChanged 判断转换是否修改了该文件。
+func (result *FileResult) IsChanged() bool {
+	return result.Err == nil && result.Transformed != nil
+}
+
+// Diff returns the unified diff of the change, empty when the file is not changed.
+// Diff 返回该变更的统一差异格式文本，文件未变更时返回空。
+func (result *FileResult) Diff() string {
+	if !result.IsChanged() {
+		return ""
+	}
+	return syntaxgo_diff.Unified(result.Path, result.Original, result.Transformed)
+}
+
+// Report is the report of the batch run, the files are sorted by path.
+// Report 是批量运行的报告，文件按路径排序。
+type Report struct {
+	Files []*FileResult // Results of all the Go files found / 找到的所有 Go 文件的结果
+}
+
+// Changed returns the results of the changed files.
+// Changed 返回被修改的文件的结果。
+func (report *Report) Changed() []*FileResult {
+	return report.filter(func(result *FileResult) bool { return result.IsChanged() })
+}
+
+// Failed returns the results of the files with errors.
+// Failed 返回出错的文件的结果。
+func (report *Report) Failed() []*FileResult {
+	return report.filter(func(result *FileResult) bool { return result.Err != nil })
+}
+
+// Skipped returns the results of the generated files that are skipped.
+// Skipped 返回被跳过的生成文件的结果。
+func (report *Report) Skipped() []*FileResult {
+	return report.filter(func(result *FileResult) bool { return result.Generated })
+}
+
+func (report *Report) filter(match func(result *FileResult) bool) []*FileResult {
+	var results []*FileResult
+	for _, result := range report.Files {
+		if match(result) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Diff returns the unified diffs of all the changed files.
+// Diff 返回所有被修改文件的统一差异格式文本。
+func (report *Report) Diff() string {
+	var builder strings.Builder
+	for _, result := range report.Changed() {
+		builder.WriteString(result.Diff())
+	}
+	return builder.String()
+}
+
+// Summary returns the summary of the run, with a line for each failed file.
+// Summary 返回运行的汇总信息，每个出错的文件占一行。
+func (report *Report) Summary() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("files: %d, changed: %d, failed: %d, skipped generated: %d\n",
+		len(report.Files), len(report.Changed()), len(report.Failed()), len(report.Skipped())))
+	for _, result := range report.Failed() {
+		builder.WriteString(fmt.Sprintf("%s: %v\n", result.Path, result.Err))
+	}
+	return builder.String()
+}
+
+// Run applies the transformation to the Go files in the module tree of the root.
+// The errors of the files are collected in the report, the returned error is only for walking the tree.
+// Run 对 root 模块树中的 Go 文件应用转换。
+// 文件的错误收集在报告中，返回的错误只用于遍历目录树时的错误。
+func Run(root string, options *Options, transform Transform) (*Report, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+	paths, err := options.listGoFiles(root)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	results := make([]*FileResult, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(options.workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				results[idx] = options.runFile(paths[idx], transform)
+			}
+		}()
+	}
+	for idx := range paths {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+	return &Report{Files: results}, nil
+}
+
+// runFile parses, transforms and writes a file.
+// runFile 解析、转换并写入一个文件。
+func (options *Options) runFile(path string, transform Transform) *FileResult {
+	result := &FileResult{Path: path}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		result.Err = erero.Wro(err)
+		return result
+	}
+	result.Original = source
+	// Parse the source already read, so the original in the result is what the transformation works on.
+	// 解析已经读取的源代码，从而结果中的原始代码就是转换所处理的代码。
+	astBundle, err := syntaxgo_ast.NewAstBundleFromSource(token.NewFileSet(), path, source)
+	if err != nil {
+		result.Err = erero.Wro(err)
+		return result
+	}
+	if astFile, _ := astBundle.GetBundle(); !options.includeGenerated && ast.IsGenerated(astFile) {
+		result.Generated = true
+		return result
+	}
+	changed, err := safeTransform(transform, astBundle)
+	if err != nil {
+		result.Err = erero.Wro(err)
+		return result
+	}
+	if !changed {
+		return result
+	}
+	newSource, err := astBundle.FormatSource()
+	if err != nil {
+		result.Err = erero.Wro(err)
+		return result
+	}
+	if string(newSource) == string(source) {
+		return result
+	}
+	if !options.dryRun {
+		if err := astBundle.Save(); err != nil {
+			result.Err = erero.Wro(err)
+			return result
+		}
+	}
+	result.Transformed = newSource
+	return result
+}
+
+// safeTransform calls the transformation, a panic is returned as an error.
+// safeTransform 调用转换，panic 会作为错误返回。
+func safeTransform(transform Transform, astBundle *syntaxgo_ast.AstBundle) (changed bool, err error) {
+	defer func() {
+		if cause := recover(); cause != nil {
+			err = erero.Errorf("transform panics: %v", cause)
+		}
+	}()
+	return transform(astBundle)
+}
+
+// listGoFiles lists the Go files in the module tree in sorted order.
+// listGoFiles 按排序后的顺序列出模块树中的 Go 文件。
+func (options *Options) listGoFiles(root string) ([]string, error) {
+	paths, err := utils.ListGoFiles(root, true)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if options.includeTests {
+		return paths, nil
+	}
+	var results []string
+	for _, path := range paths {
+		if !strings.HasSuffix(path, "_test.go") {
+			results = append(results, path)
+		}
+	}
+	return results, nil
+}
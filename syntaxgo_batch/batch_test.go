@@ -0,0 +1,105 @@
+package syntaxgo_batch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+// newTestTree creates a module tree with normal, generated, broken and skipped files.
+// newTestTree 创建包含普通文件、生成文件、错误文件以及被跳过文件的模块树。
+func newTestTree(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/demo\n",
+		"a.go":                "package demo\n\nfunc A() string { return strings.ToUpper(\"a\") }\n",
+		"b.go":                "package demo\n\nimport \"strings\"\n\nfunc B() string { return strings.ToLower(\"B\") }\n",
+		"a_test.go":           "package demo\n\nfunc TestA() { _ = strings.ToUpper(\"a\") }\n",
+		"gen.go":              "// Code generated by demo. DO NOT EDIT.\n\npackage demo\n",
+		"broken.go":           "package demo\n\nfunc Broken( {\n",
+		"sub/panic.go":        "package sub\n\nfunc Panic() {}\n",
+		"sub/c.go":            "package sub\n\nfunc C() string { return strings.Repeat(\"c\", 2) }\n",
+		"vendor/v/v.go":       "package v\n",
+		"testdata/t.go":       "package t\n",
+		".hidden/h.go":        "package h\n",
+		"nested/go.mod":       "module example.com/nested\n",
+		"nested/n.go":         "package nested\n",
+		"sub/readme.txt":      "not go\n",
+		"sub/deep/deep_go.go": "package deep\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		done.Done(os.MkdirAll(filepath.Dir(path), 0755))
+		done.Done(os.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
+
+// addStrings adds the "strings" import, and panics on the file named panic.go.
+// addStrings 添加 "strings" 导入，并在名为 panic.go 的文件上 panic。
+func addStrings(astBundle *syntaxgo_ast.AstBundle) (bool, error) {
+	if filepath.Base(astBundle.GetPath()) == "panic.go" {
+		panic("unexpected file")
+	}
+	astFile, _ := astBundle.GetBundle()
+	if astFile.Name.Name == "deep" {
+		return false, nil
+	}
+	return astBundle.AddImport("strings"), nil
+}
+
+func TestRun(t *testing.T) {
+	root := newTestTree(t)
+
+	report := rese.P1(Run(root, NewOptions().SetWorkers(2), addStrings))
+	t.Log(report.Summary())
+
+	var paths []string
+	for _, result := range report.Files {
+		paths = append(paths, rese.V1(filepath.Rel(root, result.Path)))
+	}
+	require.Equal(t, []string{"a.go", "a_test.go", "b.go", "broken.go", "gen.go", "sub/c.go", "sub/deep/deep_go.go", "sub/panic.go"}, paths)
+
+	require.Len(t, report.Changed(), 3)
+	require.Len(t, report.Skipped(), 1)
+	require.Len(t, report.Failed(), 2)
+	require.Contains(t, report.Failed()[1].Err.Error(), "transform panics: unexpected file")
+	require.True(t, strings.HasPrefix(report.Summary(), "files: 8, changed: 3, failed: 2, skipped generated: 1\n"))
+
+	data := string(rese.V1(os.ReadFile(filepath.Join(root, "a.go"))))
+	require.Equal(t, "package demo\n\nimport \"strings\"\n\nfunc A() string { return strings.ToUpper(\"a\") }\n", data)
+	data = string(rese.V1(os.ReadFile(filepath.Join(root, "gen.go"))))
+	require.Equal(t, "// Code generated by demo. DO NOT EDIT.\n\npackage demo\n", data)
+	data = string(rese.V1(os.ReadFile(filepath.Join(root, "nested", "n.go"))))
+	require.Equal(t, "package nested\n", data)
+}
+
+func TestRun_DryRun(t *testing.T) {
+	root := newTestTree(t)
+
+	report := rese.P1(Run(root, NewOptions().SetDryRun(true).SetIncludeTests(false).SetIncludeGenerated(true), addStrings))
+	t.Log(report.Diff())
+
+	require.Len(t, report.Changed(), 3) // a.go, gen.go and sub/c.go / a.go、gen.go 以及 sub/c.go
+	require.Empty(t, report.Skipped())
+	require.Contains(t, report.Diff(), "--- a/"+filepath.Join(root, "a.go")+"\n")
+	require.Contains(t, report.Diff(), "+import \"strings\"\n")
+
+	data := string(rese.V1(os.ReadFile(filepath.Join(root, "a.go"))))
+	require.Equal(t, "package demo\n\nfunc A() string { return strings.ToUpper(\"a\") }\n", data)
+}
+
+func TestRun_Empty(t *testing.T) {
+	report := rese.P1(Run(t.TempDir(), nil, addStrings))
+	require.Empty(t, report.Files)
+	require.Equal(t, "files: 0, changed: 0, failed: 0, skipped generated: 0\n", report.Summary())
+
+	_, err := Run(filepath.Join(t.TempDir(), "missing"), nil, addStrings)
+	require.Error(t, err)
+}
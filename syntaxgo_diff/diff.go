@@ -0,0 +1,250 @@
+package syntaxgo_diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Package `syntaxgo_diff` computes line diffs between the original and the transformed source, without shelling out to `diff`.
+
+Key features include:
+  - Unified diffs with configurable context lines and file names, like the output of `diff -u`.
+  - A structured list of hunks and lines, with line numbers in both versions.
+  - Counting inserted and deleted lines, for dry-run modes, code review bots and golden tests.
+*/
+
+/*
+Package `syntaxgo_diff` 计算原始源代码与转换后源代码之间的行差异，不依赖外部的 `diff` 命令。
+
+主要功能包括：
+  - 统一差异格式（unified diff），可配置上下文行数和文件名称，与 `diff -u` 的输出相同。
+  - 结构化的差异块和行列表，包含两个版本中的行号。
+  - 统计插入和删除的行数，用于试运行模式、代码审查机器人以及黄金测试。
+*/
+
+// LineKind is the kind of a diff line.
+// LineKind 是差异行的类型。
+type LineKind string
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	LINE_EQUAL  LineKind = " " // Unchanged line / 未变更的行
+	LINE_DELETE LineKind = "-" // Line only in the original / 只在原始版本中的行
+	LINE_INSERT LineKind = "+" // Line only in the transformed / 只在转换后版本中的行
+)
+
+// Line is a line of the diff.
+// Line 是差异中的一行。
+type Line struct {
+	Kind    LineKind // Kind of the line / 行的类型
+	Text    string   // Text of the line, with the newline when the line has one / 行的文本，有换行符时包含换行符
+	OldLine int      // Line number in the original counted from 1, 0 for inserted lines / 在原始版本中的行号，从 1 开始，插入的行为 0
+	NewLine int      // Line number in the transformed counted from 1, 0 for deleted lines / 在转换后版本中的行号，从 1 开始，删除的行为 0
+}
+
+// Hunk is a group of changed lines with the unchanged lines around them.
+// Hunk 是一组变更的行以及其前后未变更的行。
+type Hunk struct {
+	OldStart int     // First line in the original / 在原始版本中的起始行
+	OldCount int     // Number of lines in the original / 在原始版本中的行数
+	NewStart int     // First line in the transformed / 在转换后版本中的起始行
+	NewCount int     // Number of lines in the transformed / 在转换后版本中的行数
+	Lines    []*Line // Lines of the hunk / 差异块中的行
+}
+
+// Header returns the hunk header, like "@@ -1,4 +1,5 @@".
+// Header 返回差异块的头部，比如 "@@ -1,4 +1,5 @@"。
+func (hunk *Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(hunk.OldStart, hunk.OldCount), hunkRange(hunk.NewStart, hunk.NewCount))
+}
+
+// hunkRange formats the range of a hunk header, the count is omitted when it is 1 like GNU diff.
+// hunkRange 格式化差异块头部中的范围，与 GNU diff 一样，行数为 1 时省略。
+func hunkRange(start int, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// Diff is the diff between the original and the transformed source.
+// Diff 是原始源代码与转换后源代码之间的差异。
+type Diff struct {
+	OldName string  // Name of the original in the unified diff / 统一差异中原始版本的名称
+	NewName string  // Name of the transformed in the unified diff / 统一差异中转换后版本的名称
+	Hunks   []*Hunk // Hunks in line order / 按行顺序排列的差异块
+}
+
+// IsEmpty reports whether the sources are the same.
+// IsEmpty 判断两份源代码是否相同。
+func (diff *Diff) IsEmpty() bool {
+	return len(diff.Hunks) == 0
+}
+
+// Stats returns the numbers of inserted and deleted lines.
+// Stats 返回插入和删除的行数。
+func (diff *Diff) Stats() (inserted int, deleted int) {
+	for _, hunk := range diff.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case LINE_INSERT:
+				inserted++
+			case LINE_DELETE:
+				deleted++
+			}
+		}
+	}
+	return inserted, deleted
+}
+
+// Unified returns the unified diff text, empty when the sources are the same.
+// Lines without a newline at the end of the file are marked with "\ No newline at end of file".
+// Unified 返回统一差异格式的文本，两份源代码相同时返回空。
+// 文件末尾没有换行符的行会被标记为 "\ No newline at end of file"。
+func (diff *Diff) Unified() string {
+	if diff.IsEmpty() {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("--- " + diff.OldName + "\n")
+	builder.WriteString("+++ " + diff.NewName + "\n")
+	for _, hunk := range diff.Hunks {
+		builder.WriteString(hunk.Header() + "\n")
+		for _, line := range hunk.Lines {
+			builder.WriteString(string(line.Kind))
+			builder.WriteString(line.Text)
+			if !strings.HasSuffix(line.Text, "\n") {
+				builder.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// String returns the unified diff text.
+// String 返回统一差异格式的文本。
+func (diff *Diff) String() string {
+	return diff.Unified()
+}
+
+// Options configures the diff, the defaults are 3 context lines and the names "a" and "b".
+// Options 配置差异，默认使用 3 行上下文以及名称 "a" 和 "b"。
+type Options struct {
+	context int    // Number of unchanged lines around the changes / 变更前后保留的未变更行数
+	oldName string // Name of the original / 原始版本的名称
+	newName string // Name of the transformed / 转换后版本的名称
+}
+
+// NewOptions creates Options with 3 context lines and the names "a" and "b".
+// NewOptions 创建使用 3 行上下文以及名称 "a" 和 "b" 的 Options。
+func NewOptions() *Options {
+	return &Options{context: 3, oldName: "a", newName: "b"}
+}
+
+// SetContext sets the number of unchanged lines around the changes.
+// SetContext 设置变更前后保留的未变更行数。
+func (options *Options) SetContext(context int) *Options {
+	options.context = max(context, 0)
+	return options
+}
+
+// SetNames sets the names of the original and the transformed, like "a/main.go" and "b/main.go".
+// SetNames 设置原始版本和转换后版本的名称，比如 "a/main.go" 和 "b/main.go"。
+func (options *Options) SetNames(oldName string, newName string) *Options {
+	options.oldName = oldName
+	options.newName = newName
+	return options
+}
+
+// Compare computes the diff between the original and the transformed source.
+// Compare 计算原始源代码与转换后源代码之间的差异。
+func (options *Options) Compare(oldSource []byte, newSource []byte) *Diff {
+	lines := diffLines(splitLines(string(oldSource)), splitLines(string(newSource)))
+	diff := &Diff{OldName: options.oldName, NewName: options.newName}
+	oldBefore, newBefore, done := 0, 0, 0 // Lines counted in oldBefore and newBefore are lines[:done] / 已计入的行为 lines[:done]
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].Kind == LINE_EQUAL {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+		// Extend the hunk while the unchanged lines between two changes are not more than twice the context.
+		// 当两处变更之间的未变更行不超过两倍的上下文行数时，继续扩展差异块。
+		end := start
+		for idx := start; idx < len(lines); idx++ {
+			if lines[idx].Kind != LINE_EQUAL {
+				end = idx + 1
+			} else if idx-end >= 2*options.context {
+				break
+			}
+		}
+		from, to := max(start-options.context, 0), min(end+options.context, len(lines))
+		for _, line := range lines[done:from] {
+			if line.Kind != LINE_INSERT {
+				oldBefore++
+			}
+			if line.Kind != LINE_DELETE {
+				newBefore++
+			}
+		}
+		diff.Hunks = append(diff.Hunks, newHunk(lines[from:to], oldBefore, newBefore))
+		start, done = to, from
+	}
+	return diff
+}
+
+// Compare computes the diff between the original and the transformed source with the default options.
+// Compare 使用默认选项计算原始源代码与转换后源代码之间的差异。
+func Compare(oldSource []byte, newSource []byte) *Diff {
+	return NewOptions().Compare(oldSource, newSource)
+}
+
+// Unified returns the unified diff of the file between the original and the transformed source,
+// with the names "a/<path>" and "b/<path>", empty when the sources are the same.
+// Unified 返回文件在原始源代码与转换后源代码之间的统一差异格式文本，
+// 名称为 "a/<path>" 和 "b/<path>"，两份源代码相同时返回空。
+func Unified(path string, oldSource []byte, newSource []byte) string {
+	return NewOptions().SetNames("a/"+path, "b/"+path).Compare(oldSource, newSource).Unified()
+}
+
+// newHunk creates the hunk of the lines, with the numbers of original and transformed lines before them.
+// newHunk 创建这些行的差异块，参数为这些行之前的原始行数和转换后行数。
+func newHunk(lines []*Line, oldBefore int, newBefore int) *Hunk {
+	hunk := &Hunk{Lines: lines}
+	for _, line := range lines {
+		if line.Kind != LINE_INSERT {
+			hunk.OldCount++
+		}
+		if line.Kind != LINE_DELETE {
+			hunk.NewCount++
+		}
+	}
+	// An empty range starts at the line before the hunk, like "@@ -0,0 +1 @@" for a new file.
+	// 空范围从差异块之前的那一行开始，比如新文件为 "@@ -0,0 +1 @@"。
+	hunk.OldStart, hunk.NewStart = oldBefore+1, newBefore+1
+	if hunk.OldCount == 0 {
+		hunk.OldStart = oldBefore
+	}
+	if hunk.NewCount == 0 {
+		hunk.NewStart = newBefore
+	}
+	return hunk
+}
+
+// splitLines splits the text into lines, each with its newline.
+// splitLines 将文本拆分为行，每行包含其换行符。
+func splitLines(text string) []string {
+	var lines []string
+	for text != "" {
+		idx := strings.IndexByte(text, '\n')
+		if idx < 0 {
+			lines = append(lines, text)
+			break
+		}
+		lines = append(lines, text[:idx+1])
+		text = text[idx+1:]
+	}
+	return lines
+}
@@ -0,0 +1,99 @@
+package syntaxgo_diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_tag"
+)
+
+func TestUnified(t *testing.T) {
+	oldSource := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	newSource := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+
+	text := Unified("x.go", []byte(oldSource), []byte(newSource))
+	t.Log(text)
+	require.Equal(t, "--- a/x.go\n+++ b/x.go\n"+
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n"+
+		"@@ -10,3 +10,4 @@\n j\n k\n l\n+m\n", text)
+
+	require.Empty(t, Unified("x.go", []byte(oldSource), []byte(oldSource)))
+	require.Equal(t, "--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+a\n", Unified("x.go", nil, []byte("a\n")))
+	require.Equal(t, "--- a/x.go\n+++ b/x.go\n@@ -1 +0,0 @@\n-a\n", Unified("x.go", []byte("a\n"), nil))
+}
+
+func TestUnified_NoNewlineAtEnd(t *testing.T) {
+	text := Unified("x.go", []byte("a\nb"), []byte("a\nb\n"))
+	t.Log(text)
+	require.Equal(t, "--- a/x.go\n+++ b/x.go\n"+
+		"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n", text)
+}
+
+func TestOptions_Compare(t *testing.T) {
+	oldSource := "a\nb\nc\nd\ne\nf\ng\n"
+	newSource := "a\nB\nc\nd\ne\nF\ng\n"
+
+	diff := NewOptions().SetContext(0).SetNames("old.go", "new.go").Compare([]byte(oldSource), []byte(newSource))
+	t.Log(diff)
+	require.Len(t, diff.Hunks, 2)
+	require.Equal(t, "@@ -2 +2 @@", diff.Hunks[0].Header())
+	require.Equal(t, "@@ -6 +6 @@", diff.Hunks[1].Header())
+	require.Equal(t, &Line{Kind: LINE_DELETE, Text: "f\n", OldLine: 6}, diff.Hunks[1].Lines[0])
+	require.Equal(t, &Line{Kind: LINE_INSERT, Text: "F\n", NewLine: 6}, diff.Hunks[1].Lines[1])
+	require.Equal(t, "--- old.go\n+++ new.go\n@@ -2 +2 @@\n-b\n+B\n@@ -6 +6 @@\n-f\n+F\n", diff.Unified())
+
+	inserted, deleted := diff.Stats()
+	require.Equal(t, 2, inserted)
+	require.Equal(t, 2, deleted)
+
+	// The unchanged lines between the changes are not more than twice the context, so the hunks are merged.
+	// 两处变更之间的未变更行不超过两倍的上下文行数，因此差异块被合并。
+	diff = NewOptions().SetContext(2).Compare([]byte(oldSource), []byte(newSource))
+	require.Len(t, diff.Hunks, 1)
+	require.Equal(t, "@@ -1,7 +1,7 @@", diff.Hunks[0].Header())
+}
+
+func TestOptions_Compare_PureInsert(t *testing.T) {
+	diff := NewOptions().SetContext(0).Compare([]byte("a\nb\nc\n"), []byte("a\nb\nX\nc\n"))
+	require.Len(t, diff.Hunks, 1)
+	require.Equal(t, "@@ -2,0 +3 @@", diff.Hunks[0].Header())
+
+	diff = NewOptions().SetContext(0).Compare([]byte("a\nb\nX\nc\n"), []byte("a\nb\nc\n"))
+	require.Len(t, diff.Hunks, 1)
+	require.Equal(t, "@@ -3 +2,0 @@", diff.Hunks[0].Header())
+}
+
+func TestCompare_InjectImports(t *testing.T) {
+	source := []byte("package demo\n\nfunc main() {\n\tfmt.Println(strings.ToUpper(\"a\"))\n}\n")
+	newSource := syntaxgo_ast.InjectImports(source, []string{"fmt", "strings"})
+
+	diff := Compare(source, newSource)
+	t.Log(diff)
+	inserted, deleted := diff.Stats()
+	require.Positive(t, inserted)
+	require.Zero(t, deleted)
+	require.Contains(t, diff.Unified(), "+import (\n+    \"fmt\"\n+    \"strings\"\n+)\n")
+}
+
+func TestCompare_FormatSource(t *testing.T) {
+	source := []byte("package demo\n\nfunc main() {\nprintln(\"a\")\n}\n")
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1(source))
+	newSource := rese.V1(astBundle.FormatSource())
+
+	diff := Compare(source, newSource)
+	t.Log(diff)
+	require.Equal(t, "--- a\n+++ b\n@@ -1,5 +1,5 @@\n package demo\n \n func main() {\n-println(\"a\")\n+\tprintln(\"a\")\n }\n", diff.Unified())
+}
+
+func TestCompare_SetTagFieldValue(t *testing.T) {
+	tag := `gorm:"column:name;type:text" json:"name"`
+	newTag := syntaxgo_tag.SetTagFieldValue(tag, "gorm", "column", "username", syntaxgo_tag.INSERT_LOCATION_END)
+
+	diff := Compare([]byte(tag), []byte(newTag))
+	t.Log(diff)
+	require.Len(t, diff.Hunks, 1)
+	require.Equal(t, LINE_DELETE, diff.Hunks[0].Lines[0].Kind)
+	require.Equal(t, LINE_INSERT, diff.Hunks[0].Lines[1].Kind)
+}
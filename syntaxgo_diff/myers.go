@@ -0,0 +1,130 @@
+package syntaxgo_diff
+
+// diffLines computes the line diff with the Myers algorithm, after skipping the common prefix and suffix.
+// The memory is O(D²) for D changes, since each round keeps only the diagonals it reaches.
+// diffLines 在跳过公共前缀和后缀之后，使用 Myers 算法计算行差异。
+// 对于 D 个变更，内存为 O(D²)，因为每一轮只保存其到达的对角线。
+func diffLines(oldLines []string, newLines []string) []*Line {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix && oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var lines []*Line
+	oldLine, newLine := 1, 1
+	add := func(kind LineKind, text string) {
+		line := &Line{Kind: kind, Text: text}
+		if kind != LINE_INSERT {
+			line.OldLine = oldLine
+			oldLine++
+		}
+		if kind != LINE_DELETE {
+			line.NewLine = newLine
+			newLine++
+		}
+		lines = append(lines, line)
+	}
+	for _, text := range oldLines[:prefix] {
+		add(LINE_EQUAL, text)
+	}
+	oldMid, newMid := oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix]
+	for _, step := range myersSteps(oldMid, newMid) {
+		switch step.kind {
+		case LINE_INSERT:
+			add(LINE_INSERT, newMid[step.newIdx])
+		default:
+			add(step.kind, oldMid[step.oldIdx])
+		}
+	}
+	for _, text := range oldLines[len(oldLines)-suffix:] {
+		add(LINE_EQUAL, text)
+	}
+	return lines
+}
+
+// myersStep is a step of the edit script, with the indexes of the line in the old and new lines.
+// myersStep 是编辑脚本中的一步，包含该行在新旧行中的下标。
+type myersStep struct {
+	kind   LineKind
+	oldIdx int
+	newIdx int
+}
+
+// myersSteps returns the shortest edit script from the old lines to the new lines, deletes come before inserts.
+// myersSteps 返回从旧行到新行的最短编辑脚本，删除排在插入之前。
+func myersSteps(oldLines []string, newLines []string) []myersStep {
+	n, m := len(oldLines), len(newLines)
+	if n+m == 0 {
+		return nil
+	}
+	offset := n + m + 1
+	// frontier[offset+k] is the furthest x reached on the diagonal k = x - y.
+	// trace[d] keeps the frontier before the round d on the diagonals -d-1 to d+1, the ones the round reads.
+	// frontier[offset+k] 是在对角线 k = x - y 上到达的最远 x。
+	// trace[d] 保存第 d 轮开始之前 frontier 在对角线 -d-1 到 d+1 上的值，也就是该轮读取的部分。
+	frontier := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), frontier[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && frontier[offset+k-1] < frontier[offset+k+1]) {
+				x = frontier[offset+k+1]
+			} else {
+				x = frontier[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && oldLines[x] == newLines[y] {
+				x++
+				y++
+			}
+			frontier[offset+k] = x
+			if x >= n && y >= m {
+				return myersBacktrack(trace, n, m)
+			}
+		}
+	}
+	return nil // Unreachable, the script has at most n+m edits / 不可达，编辑脚本最多有 n+m 步
+}
+
+// myersBacktrack walks the trace back from the end to build the edit script.
+// myersBacktrack 从终点沿 trace 回溯以构建编辑脚本。
+func myersBacktrack(trace [][]int, n int, m int) []myersStep {
+	var steps []myersStep
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		// The diagonal k is at k+d+1 in trace[d].
+		// 对角线 k 在 trace[d] 中的下标是 k+d+1。
+		frontier := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && frontier[k+d] < frontier[k+d+2]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := frontier[prevK+d+1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			steps = append(steps, myersStep{kind: LINE_EQUAL, oldIdx: x, newIdx: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				steps = append(steps, myersStep{kind: LINE_INSERT, oldIdx: prevX, newIdx: prevY})
+			} else {
+				steps = append(steps, myersStep{kind: LINE_DELETE, oldIdx: prevX, newIdx: prevY})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	return steps
+}
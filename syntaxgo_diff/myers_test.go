@@ -0,0 +1,91 @@
+package syntaxgo_diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	oldLines := strings.Split("a b c a b b a", " ")
+	newLines := strings.Split("c b a b a c", " ")
+
+	lines := diffLines(oldLines, newLines)
+	var oldText, newText []string
+	equal := 0
+	for _, line := range lines {
+		if line.Kind != LINE_INSERT {
+			oldText = append(oldText, line.Text)
+			require.Equal(t, len(oldText), line.OldLine)
+		}
+		if line.Kind != LINE_DELETE {
+			newText = append(newText, line.Text)
+			require.Equal(t, len(newText), line.NewLine)
+		}
+		if line.Kind == LINE_EQUAL {
+			equal++
+		}
+	}
+	require.Equal(t, oldLines, oldText)
+	require.Equal(t, newLines, newText)
+	// The longest common subsequence of the classic example has 4 lines.
+	// 经典示例的最长公共子序列有 4 行。
+	require.Equal(t, 4, equal)
+}
+
+func TestDiffLines_Empty(t *testing.T) {
+	require.Empty(t, diffLines(nil, nil))
+
+	lines := diffLines(nil, []string{"a", "b"})
+	require.Len(t, lines, 2)
+	require.Equal(t, LINE_INSERT, lines[0].Kind)
+	require.Equal(t, LINE_INSERT, lines[1].Kind)
+}
+
+func TestDiffLines_Random(t *testing.T) {
+	// The equal lines are a longest common subsequence, checked against dynamic programming.
+	// 相等的行是最长公共子序列，与动态规划的结果进行对比。
+	random := rand.New(rand.NewSource(1))
+	newRandomLines := func() []string {
+		lines := make([]string, random.Intn(12))
+		for idx := range lines {
+			lines[idx] = string(rune('a' + random.Intn(3)))
+		}
+		return lines
+	}
+	for round := 0; round < 500; round++ {
+		oldLines, newLines := newRandomLines(), newRandomLines()
+		var oldText, newText []string
+		equal := 0
+		for _, line := range diffLines(oldLines, newLines) {
+			if line.Kind != LINE_INSERT {
+				oldText = append(oldText, line.Text)
+			}
+			if line.Kind != LINE_DELETE {
+				newText = append(newText, line.Text)
+			}
+			if line.Kind == LINE_EQUAL {
+				equal++
+			}
+		}
+		require.Equal(t, strings.Join(oldLines, ""), strings.Join(oldText, ""))
+		require.Equal(t, strings.Join(newLines, ""), strings.Join(newText, ""))
+
+		lcs := make([][]int, len(oldLines)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(newLines)+1)
+		}
+		for i := len(oldLines) - 1; i >= 0; i-- {
+			for j := len(newLines) - 1; j >= 0; j-- {
+				if oldLines[i] == newLines[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		require.Equal(t, lcs[0][0], equal, "old = %v new = %v", oldLines, newLines)
+	}
+}
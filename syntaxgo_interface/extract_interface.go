@@ -0,0 +1,251 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_astnorm"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+// ExtractOptions configures the interface extracted from the methods of a struct.
+// ExtractOptions 配置从结构体方法中提取出的接口。
+type ExtractOptions struct {
+	InterfaceName string         // Name of the interface, default is the struct name with "Interface" suffix / 接口名称，默认是结构体名称加 "Interface" 后缀
+	PackageName   string         // Package name of the struct, set it when the interface is placed in another package / 结构体所在的包名，当接口放在其它包中时设置
+	ImportPath    string         // Import path of the struct package, imported when PackageName is set / 结构体所在包的导入路径，设置 PackageName 时会被导入
+	MethodPattern *regexp.Regexp // Only methods with matching names are included, nil means all / 只包含名称匹配的方法，为 nil 时包含全部方法
+}
+
+// ExtractInterfaceFromStruct generates an interface declaration containing the exported methods of the struct.
+// The doc comments of the methods are carried over, and types are qualified with the package name when PackageName is set.
+// A generic struct gives a generic interface, and an error is returned when PackageName is set but a method uses unexported local types.
+// ExtractInterfaceFromStruct 生成包含结构体所有可导出方法的接口声明。
+// 方法的文档注释会被保留，当设置 PackageName 时类型会加上包名。
+// 泛型结构体得到泛型接口，当设置 PackageName 而方法使用了未导出的本地类型时返回错误。
+func ExtractInterfaceFromStruct(astBundle *syntaxgo_ast.AstBundle, structName string, options *ExtractOptions) (*GeneratedCode, error) {
+	astFile, _ := astBundle.GetBundle()
+	typeSpec, ok := findTypeSpec(astFile, structName)
+	if !ok {
+		return nil, erero.Errorf("no struct name = %s in the bundle", structName)
+	}
+	if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+		return nil, erero.Errorf("no struct name = %s in the bundle", structName)
+	}
+	if options == nil {
+		options = &ExtractOptions{}
+	}
+	interfaceName := options.InterfaceName
+	if interfaceName == "" {
+		interfaceName = structName + "Interface"
+	}
+	// The interface has the same type params as the struct, so the methods of Stack[T] give StackInterface[T].
+	// 接口带有与结构体相同的类型参数，因此 Stack[T] 的方法得到 StackInterface[T]。
+	genericTypeParams := syntaxgo_astnorm.GetGenericTypeParamsMap(typeSpec.TypeParams)
+	imports := newImportsCollector(astFile, options.PackageName, options.ImportPath, genericTypeParams)
+
+	var typeParamsDecl []string
+	if typeSpec.TypeParams != nil {
+		for _, field := range typeSpec.TypeParams.List {
+			if names := unexportedLocalTypes(field.Type, options.PackageName, genericTypeParams); len(names) > 0 {
+				return nil, erero.Errorf("type params of struct %s use the unexported types %s, they can not be referenced from another package", structName, strings.Join(names, ", "))
+			}
+			imports.collect(field.Type, nil)
+			constraint := types.ExprString(field.Type)
+			if options.PackageName != "" {
+				constraint, _ = syntaxgo_astnorm.QualifyTypeWithPackage(field.Type, options.PackageName, genericTypeParams)
+			}
+			for _, name := range field.Names {
+				typeParamsDecl = append(typeParamsDecl, name.Name+" "+constraint)
+			}
+		}
+	}
+	var typeParams string
+	if len(typeParamsDecl) > 0 {
+		typeParams = "[" + strings.Join(typeParamsDecl, ", ") + "]"
+	}
+
+	ptx := utils.NewPTX()
+	ptx.Println("// " + interfaceName + " is extracted from the methods of " + structName + ".")
+	ptx.Println("type " + interfaceName + typeParams + " interface {")
+	var count = 0
+	for _, function := range syntaxgo_search.FindFunctions(astFile) {
+		if typeName, _, ok := utils.GetReceiverTypeName(function); !ok || typeName != structName || !function.Name.IsExported() {
+			continue
+		}
+		if options.MethodPattern != nil && !options.MethodPattern.MatchString(function.Name.Name) {
+			continue
+		}
+		funcType, err := renameReceiverTypeParams(function, typeSpec.TypeParams)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		if names := unexportedLocalTypes(funcType, options.PackageName, genericTypeParams); len(names) > 0 {
+			return nil, erero.Errorf("method %s of struct %s uses the unexported types %s, they can not be referenced from another package", function.Name.Name, structName, strings.Join(names, ", "))
+		}
+		if function.Doc != nil {
+			for _, comment := range function.Doc.List {
+				ptx.Println(comment.Text)
+			}
+		}
+		params := syntaxgo_astnorm.NewNameTypeElementsV2(funcType.Params, keepOriginalName, options.PackageName, genericTypeParams)
+		results := syntaxgo_astnorm.NewNameTypeElementsV2(funcType.Results, keepOriginalName, options.PackageName, genericTypeParams)
+		imports.collect(funcType, nil)
+
+		ptx.Println(function.Name.Name + "(" + formatFieldList(funcType.Params, params) + ")" + formatSignatureResults(funcType.Results, results))
+		count++
+	}
+	ptx.Println("}")
+	if count == 0 {
+		return nil, erero.Errorf("no exported methods of struct %s to extract", structName)
+	}
+	return newGeneratedCode(ptx.Bytes(), imports)
+}
+
+// renameReceiverTypeParams returns the signature of the method, with the type params named by the receiver, like E in (s *Stack[E]),
+// renamed to the names in the struct declaration.
+// renameReceiverTypeParams 返回方法的签名，其中由接收者命名的类型参数（比如 (s *Stack[E]) 中的 E）被改为结构体声明中的名称。
+func renameReceiverTypeParams(function *ast.FuncDecl, typeParams *ast.FieldList) (*ast.FuncType, error) {
+	var structNames []string
+	if typeParams != nil {
+		for _, field := range typeParams.List {
+			for _, name := range field.Names {
+				structNames = append(structNames, name.Name)
+			}
+		}
+	}
+	receiverNames := receiverTypeParams(function.Recv.List[0].Type)
+	if len(receiverNames) != len(structNames) {
+		return nil, erero.Errorf("receiver of method %s has %d type params, but the struct has %d", function.Name.Name, len(receiverNames), len(structNames))
+	}
+	renames := map[string]string{}
+	for idx, name := range receiverNames {
+		if name != structNames[idx] && name != "_" {
+			renames[name] = structNames[idx]
+		}
+	}
+	if len(renames) == 0 {
+		return function.Type, nil
+	}
+	// Parse the printed signature to get a copy of it, so the original AST is unchanged.
+	// 通过解析打印出的签名得到其副本，以保证原始语法树不被修改。
+	funcExpr, err := parser.ParseExpr(types.ExprString(function.Type))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	funcType := funcExpr.(*ast.FuncType)
+	ast.Inspect(funcType, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Field:
+			ast.Inspect(node.Type, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.SelectorExpr:
+					return false
+				case *ast.Ident:
+					if newName, ok := renames[node.Name]; ok {
+						node.Name = newName
+					}
+				}
+				return true
+			})
+			return false
+		}
+		return true
+	})
+	return funcType, nil
+}
+
+// receiverTypeParams returns the names of the type params in the receiver type, like [K, V] in (m *Map[K, V]).
+// receiverTypeParams 返回接收者类型中类型参数的名称，比如 (m *Map[K, V]) 中的 [K, V]。
+func receiverTypeParams(recvType ast.Expr) []string {
+	for {
+		switch node := recvType.(type) {
+		case *ast.ParenExpr:
+			recvType = node.X
+		case *ast.StarExpr:
+			recvType = node.X
+		case *ast.IndexExpr:
+			return identNames([]ast.Expr{node.Index})
+		case *ast.IndexListExpr:
+			return identNames(node.Indices)
+		default:
+			return nil
+		}
+	}
+}
+
+func identNames(exprs []ast.Expr) []string {
+	var names []string
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+// keepOriginalName keeps the names of params and results, anonymous ones stay anonymous.
+// keepOriginalName 保留参数和返回值的原始名称，匿名的依然保持匿名。
+func keepOriginalName(ident *ast.Ident, kind string, nameIndex int, anonymousIndex int) string {
+	if ident == nil {
+		return ""
+	}
+	return ident.Name
+}
+
+// formatFieldList formats the params or results, with names when the original list has names.
+// formatFieldList 格式化参数或返回值列表，当原始列表带名称时保留名称。
+func formatFieldList(fieldList *ast.FieldList, elements syntaxgo_astnorm.NameTypeElements) string {
+	if fieldList != nil && len(fieldList.List) > 0 && len(fieldList.List[0].Names) > 0 {
+		return elements.FormatNamesWithKinds().MergeParts()
+	}
+	return strings.Join(elements.Kinds(), ", ")
+}
+
+// formatSignatureResults formats the results part of a signature, keeping the result names when present.
+// formatSignatureResults 格式化签名中的返回值部分，存在返回值名称时保留名称。
+func formatSignatureResults(fieldList *ast.FieldList, results syntaxgo_astnorm.NameTypeElements) string {
+	if len(results) == 0 {
+		return ""
+	}
+	if len(results) == 1 && len(fieldList.List[0].Names) == 0 {
+		return " " + results[0].Kind
+	}
+	return " (" + formatFieldList(fieldList, results) + ")"
+}
+
+// unexportedLocalTypes returns the unexported local types referenced by the type expression when the interface goes to another package,
+// since the interface could not refer to them from there.
+// unexportedLocalTypes 当接口放到其它包中时，返回类型表达式引用的未导出本地类型，因为在那里接口无法引用它们。
+func unexportedLocalTypes(typeExpr ast.Expr, packageName string, genericTypeParams map[string]ast.Expr) []string {
+	if packageName == "" {
+		return nil
+	}
+	var names []string
+	var inspect func(node ast.Node) bool
+	inspect = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Field:
+			ast.Inspect(node.Type, inspect)
+			return false
+		case *ast.SelectorExpr:
+			return false
+		case *ast.Ident:
+			if _, ok := genericTypeParams[node.Name]; ok {
+				return true
+			}
+			if !node.IsExported() && node.Name != "_" && types.Universe.Lookup(node.Name) == nil {
+				names = append(names, node.Name)
+			}
+		}
+		return true
+	}
+	ast.Inspect(typeExpr, inspect)
+	return names
+}
@@ -0,0 +1,160 @@
+package syntaxgo_interface
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const structSource = `package demo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// FindByID finds the user by id.
+// It returns an error when the user does not exist.
+func (r *UserRepository) FindByID(ctx context.Context, id int) (*User, error) {
+	return nil, nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, names ...string) (users []*User, err error) {
+	return nil, nil
+}
+
+func (r UserRepository) Count(context.Context) int {
+	return 0
+}
+
+func (r *UserRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *UserRepository) scan() {}
+`
+
+func TestExtractInterfaceFromStruct(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(structSource)))
+
+	code, err := ExtractInterfaceFromStruct(astBundle, "UserRepository", nil)
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "type UserRepositoryInterface interface {")
+	require.Contains(t, string(code.Code), "\t// FindByID finds the user by id.\n\t// It returns an error when the user does not exist.\n\tFindByID(ctx context.Context, id int) (*User, error)\n")
+	require.Contains(t, string(code.Code), "\tFindAll(ctx context.Context, names ...string) (users []*User, err error)\n")
+	require.Contains(t, string(code.Code), "\tCount(context.Context) int\n")
+	require.NotContains(t, string(code.Code), "scan")
+	require.Equal(t, []string{"context", "gorm.io/gorm"}, code.Imports)
+}
+
+func TestExtractInterfaceFromStruct_OtherPackage(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(structSource)))
+
+	code, err := ExtractInterfaceFromStruct(astBundle, "UserRepository", &ExtractOptions{
+		InterfaceName: "UserFinder",
+		PackageName:   "demo",
+		ImportPath:    "example.com/demo",
+		MethodPattern: regexp.MustCompile(`^Find`),
+	})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "FindByID(ctx context.Context, id int) (*demo.User, error)")
+	require.Contains(t, string(code.Code), "FindAll(ctx context.Context, names ...string) (users []*demo.User, err error)")
+	require.NotContains(t, string(code.Code), "Count")
+	require.Equal(t, []string{"context", "example.com/demo"}, code.Imports)
+}
+
+func TestExtractInterfaceFromStruct_NotFound(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(structSource)))
+
+	_, err := ExtractInterfaceFromStruct(astBundle, "Account", nil)
+	require.Error(t, err)
+
+	_, err = ExtractInterfaceFromStruct(astBundle, "User", nil)
+	require.Error(t, err)
+}
+
+func TestExtractInterfaceFromStruct_Generic(t *testing.T) {
+	const source = `package demo
+
+type Stack[T Item, K comparable] struct {
+	items []T
+}
+
+func (s *Stack[E, _]) Push(item E) {}
+
+func (s *Stack[T, K]) Pop() (T, bool) {
+	var item T
+	return item, false
+}
+
+func (s Stack[V, K]) Keys() map[K][]*V {
+	return nil
+}
+
+type Item interface{}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	code, err := ExtractInterfaceFromStruct(astBundle, "Stack", nil)
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "type StackInterface[T Item, K comparable] interface {")
+	require.Contains(t, string(code.Code), "\tPush(item T)\n")
+	require.Contains(t, string(code.Code), "\tPop() (T, bool)\n")
+	require.Contains(t, string(code.Code), "\tKeys() map[K][]*T\n")
+
+	code, err = ExtractInterfaceFromStruct(astBundle, "Stack", &ExtractOptions{PackageName: "demo", ImportPath: "example.com/demo"})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "type StackInterface[T demo.Item, K comparable] interface {")
+	require.Contains(t, string(code.Code), "\tKeys() map[K][]*T\n")
+	require.Equal(t, []string{"example.com/demo"}, code.Imports)
+}
+
+func TestExtractInterfaceFromStruct_UnexportedTypes(t *testing.T) {
+	const source = `package demo
+
+type Store struct{}
+
+type record struct{}
+
+func (s *Store) Load(key string) ([]*record, error) {
+	return nil, nil
+}
+
+func (s *Store) Keys() []string {
+	return nil
+}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	// In the same package the unexported types are fine.
+	// 在同一个包中未导出的类型没有问题。
+	code, err := ExtractInterfaceFromStruct(astBundle, "Store", nil)
+	require.NoError(t, err)
+	require.Contains(t, string(code.Code), "\tLoad(key string) ([]*record, error)\n")
+
+	// In another package they can not be referenced.
+	// 在其它包中无法引用它们。
+	_, err = ExtractInterfaceFromStruct(astBundle, "Store", &ExtractOptions{PackageName: "demo", ImportPath: "example.com/demo"})
+	require.ErrorContains(t, err, "record")
+
+	code, err = ExtractInterfaceFromStruct(astBundle, "Store", &ExtractOptions{PackageName: "demo", ImportPath: "example.com/demo", MethodPattern: regexp.MustCompile(`^Keys$`)})
+	require.NoError(t, err)
+	require.Contains(t, string(code.Code), "\tKeys() []string\n")
+}
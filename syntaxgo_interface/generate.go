@@ -0,0 +1,265 @@
+package syntaxgo_interface
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_astnorm"
+)
+
+// GenerateOptions configures the code generated from an interface.
+// GenerateOptions 配置根据接口生成的代码。
+type GenerateOptions struct {
+	TypeName     string // Name of the generated type, default is the interface name with a suffix / 生成的类型名称，默认是接口名称加后缀
+	ReceiverName string // Receiver name of the generated methods, default is the first letter of the type name / 生成方法的接收者名称，默认是类型名称的首字母
+	PackageName  string // Package name of the interface, set it when the code is generated in another package / 接口所在的包名，在其它包中生成代码时设置
+	ImportPath   string // Import path of the interface package, imported when the generated code refers to it / 接口所在包的导入路径，生成的代码引用该包时会被导入
+}
+
+// GeneratedCode is the generated source code together with the import paths it requires.
+// GeneratedCode 是生成的源代码以及它所需要的导入路径。
+type GeneratedCode struct {
+	Code    []byte   // Formatted declarations / 格式化后的声明代码
+	Imports []string // Sorted import paths / 排好序的导入路径
+}
+
+// InjectImports adds the required import paths into the Go source code.
+// InjectImports 将所需的导入路径添加到 Go 源代码中。
+func (code *GeneratedCode) InjectImports(source []byte) []byte {
+	return syntaxgo_ast.InjectImports(source, code.Imports)
+}
+
+// GenerateStub generates a struct implementing the interface, the methods return zero values.
+// GenerateStub 生成实现该接口的结构体，其方法都返回零值。
+func GenerateStub(astBundle *syntaxgo_ast.AstBundle, interfaceName string, options *GenerateOptions) (*GeneratedCode, error) {
+	gen, err := newGenerator(astBundle, interfaceName, options, "Stub")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	ptx := utils.NewPTX()
+	ptx.Println("// " + gen.typeName + " is a stub implementation of " + gen.interfaceKind + ".")
+	ptx.Println("type " + gen.typeName + gen.typeParamsDecl + " struct{}")
+	ptx.Println()
+	for _, method := range gen.methods {
+		params, results := gen.elements(method)
+		ptx.Println(gen.methodHeader(method, params, results) + " {")
+		if len(results) > 0 {
+			ptx.Println("return " + results.ZeroValues(gen.genericTypeParams).MergeParts())
+		}
+		ptx.Println("}")
+		ptx.Println()
+	}
+	return gen.output(ptx.Bytes())
+}
+
+// GenerateDecorator generates a struct wrapping another implementation of the interface, the methods delegate to it.
+// GenerateDecorator 生成包装该接口另一实现的结构体，其方法委托给被包装的实现。
+func GenerateDecorator(astBundle *syntaxgo_ast.AstBundle, interfaceName string, options *GenerateOptions) (*GeneratedCode, error) {
+	gen, err := newGenerator(astBundle, interfaceName, options, "Decorator")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	ptx := utils.NewPTX()
+	ptx.Println("// " + gen.typeName + " wraps " + gen.interfaceKind + " and delegates each method to the wrapped one.")
+	ptx.Println("type " + gen.typeName + gen.typeParamsDecl + " struct {")
+	ptx.Println("next " + gen.interfaceType())
+	ptx.Println("}")
+	ptx.Println()
+	ptx.Println("// New" + gen.typeName + " creates a new " + gen.typeName + " wrapping the given implementation.")
+	ptx.Println("func New" + gen.typeName + gen.typeParamsDecl + "(next " + gen.interfaceType() + ") *" + gen.typeName + gen.typeParamsUsage + " {")
+	ptx.Println("return &" + gen.typeName + gen.typeParamsUsage + "{next: next}")
+	ptx.Println("}")
+	ptx.Println()
+	for _, method := range gen.methods {
+		params, results := gen.elements(method)
+		ptx.Println(gen.methodHeader(method, params, results) + " {")
+		call := gen.receiverName + ".next." + method.Name + "(" + params.GenerateFunctionParams().MergeParts() + ")"
+		if len(results) > 0 {
+			call = "return " + call
+		}
+		ptx.Println(call)
+		ptx.Println("}")
+		ptx.Println()
+	}
+	return gen.output(ptx.Bytes())
+}
+
+// GenerateMock generates a struct with a function field for each method, like "ReadFunc func(p []byte) (int, error)".
+// The methods call the function fields, and panic when the function field is not set.
+// GenerateMock 生成为每个方法提供一个函数字段的结构体，比如 "ReadFunc func(p []byte) (int, error)"。
+// An error is returned when a function field has the name of a method, like GetFunc for the methods Get and GetFunc.
+// 结构体的方法调用对应的函数字段，当函数字段未设置时触发 panic。
+// 当函数字段与某个方法同名时返回错误，比如方法 Get 和 GetFunc 中的 GetFunc。
+func GenerateMock(astBundle *syntaxgo_ast.AstBundle, interfaceName string, options *GenerateOptions) (*GeneratedCode, error) {
+	gen, err := newGenerator(astBundle, interfaceName, options, "Mock")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, method := range gen.methods {
+		if _, ok := gen.methods.FindMethod(method.Name + "Func"); ok {
+			return nil, erero.Errorf("function field %sFunc of method %s has the name of another method of interface %s", method.Name, method.Name, interfaceName)
+		}
+	}
+	ptx := utils.NewPTX()
+	ptx.Println("// " + gen.typeName + " is a mock of " + gen.interfaceKind + ", each method calls the function field with the same name.")
+	ptx.Println("type " + gen.typeName + gen.typeParamsDecl + " struct {")
+	for _, method := range gen.methods {
+		params, results := gen.elements(method)
+		ptx.Println(method.Name + "Func func(" + params.FormatNamesWithKinds().MergeParts() + ")" + formatResults(results))
+	}
+	ptx.Println("}")
+	ptx.Println()
+	for _, method := range gen.methods {
+		params, results := gen.elements(method)
+		ptx.Println(gen.methodHeader(method, params, results) + " {")
+		ptx.Println("if " + gen.receiverName + "." + method.Name + "Func == nil {")
+		ptx.Println(`panic("` + gen.typeName + "." + method.Name + `Func is not set")`)
+		ptx.Println("}")
+		call := gen.receiverName + "." + method.Name + "Func(" + params.GenerateFunctionParams().MergeParts() + ")"
+		if len(results) > 0 {
+			call = "return " + call
+		}
+		ptx.Println(call)
+		ptx.Println("}")
+		ptx.Println()
+	}
+	return gen.output(ptx.Bytes())
+}
+
+// generator holds the common information used to generate code from an interface.
+// generator 保存根据接口生成代码时使用的公共信息。
+type generator struct {
+	astFile           *ast.File
+	options           *GenerateOptions
+	methods           InterfaceMethods
+	typeName          string
+	receiverName      string
+	interfaceKind     string // Interface type used in generated code, like pkg.Repo[T] / 生成代码中使用的接口类型，比如 pkg.Repo[T]
+	typeParamsDecl    string // Like [T any] / 比如 [T any]
+	typeParamsUsage   string // Like [T] / 比如 [T]
+	genericTypeParams map[string]ast.Expr
+	reservedNames     map[string]bool
+	imports           *importsCollector
+}
+
+func newGenerator(astBundle *syntaxgo_ast.AstBundle, interfaceName string, options *GenerateOptions, suffix string) (*generator, error) {
+	astFile, _ := astBundle.GetBundle()
+	typeSpec, ok := FindInterfaceTypeSpec(astFile, interfaceName)
+	if !ok {
+		return nil, erero.Errorf("no interface name = %s in the bundle", interfaceName)
+	}
+	methods, err := ExtractInterfaceMethods(astBundle, interfaceName)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if options == nil {
+		options = &GenerateOptions{}
+	}
+	genericTypeParams := syntaxgo_astnorm.GetGenericTypeParamsMap(typeSpec.TypeParams)
+	gen := &generator{
+		astFile:           astFile,
+		options:           options,
+		methods:           methods,
+		typeName:          options.TypeName,
+		receiverName:      options.ReceiverName,
+		genericTypeParams: genericTypeParams,
+		imports:           newImportsCollector(astFile, options.PackageName, options.ImportPath, genericTypeParams),
+	}
+	if gen.typeName == "" {
+		gen.typeName = interfaceName + suffix
+	}
+	if gen.receiverName == "" {
+		gen.receiverName = string(unicode.ToLower([]rune(gen.typeName)[0]))
+	}
+	// Params with the same name as the receiver get generated names.
+	// 与接收者同名的参数会得到生成的名称。
+	gen.reservedNames = map[string]bool{gen.receiverName: true}
+
+	var paramsDecl, paramsUsage []string
+	if typeSpec.TypeParams != nil {
+		for _, field := range typeSpec.TypeParams.List {
+			constraint := gen.qualify(field.Type)
+			for _, name := range field.Names {
+				paramsDecl = append(paramsDecl, name.Name+" "+constraint)
+				paramsUsage = append(paramsUsage, name.Name)
+			}
+		}
+	}
+	if len(paramsDecl) > 0 {
+		gen.typeParamsDecl = "[" + strings.Join(paramsDecl, ", ") + "]"
+		gen.typeParamsUsage = "[" + strings.Join(paramsUsage, ", ") + "]"
+	}
+	gen.interfaceKind = interfaceName + gen.typeParamsUsage
+	if options.PackageName != "" {
+		gen.interfaceKind = options.PackageName + "." + gen.interfaceKind
+	}
+	return gen, nil
+}
+
+// interfaceType returns the interface type used in the generated code, and collects the import path of its package.
+// Comments use interfaceKind directly, so the import is only added when the code refers to the interface.
+// interfaceType 返回生成代码中使用的接口类型，并收集其所在包的导入路径。
+// 注释直接使用 interfaceKind，因此只有代码引用该接口时才会添加导入。
+func (gen *generator) interfaceType() string {
+	if gen.options.PackageName != "" {
+		gen.imports.add(gen.options.ImportPath)
+	}
+	return gen.interfaceKind
+}
+
+// elements returns the normalized params and results of the method, and collects the import paths they require.
+// elements 返回方法规范化后的参数和返回值，同时收集它们需要的导入路径。
+func (gen *generator) elements(method *InterfaceMethod) (params syntaxgo_astnorm.NameTypeElements, results syntaxgo_astnorm.NameTypeElements) {
+	params = syntaxgo_astnorm.NewNameTypeElementsV2(method.FuncType.Params, makeParamNameFunction("arg", gen.reservedNames), gen.options.PackageName, gen.genericTypeParams)
+	results = method.Results(gen.options.PackageName, gen.genericTypeParams)
+	gen.imports.collect(method.FuncType, method.imports)
+	return params, results
+}
+
+func (gen *generator) methodHeader(method *InterfaceMethod, params, results syntaxgo_astnorm.NameTypeElements) string {
+	return "func (" + gen.receiverName + " *" + gen.typeName + gen.typeParamsUsage + ") " + method.Name + "(" + params.FormatNamesWithKinds().MergeParts() + ")" + formatResults(results)
+}
+
+func (gen *generator) qualify(typeExpr ast.Expr) string {
+	gen.imports.collect(typeExpr, nil)
+	if gen.options.PackageName == "" {
+		return types.ExprString(typeExpr)
+	}
+	kind, _ := syntaxgo_astnorm.QualifyTypeWithPackage(typeExpr, gen.options.PackageName, gen.genericTypeParams)
+	return kind
+}
+
+func (gen *generator) output(code []byte) (*GeneratedCode, error) {
+	return newGeneratedCode(code, gen.imports)
+}
+
+// newGeneratedCode formats the code and attaches the collected import paths.
+// newGeneratedCode 格式化代码并附上收集到的导入路径。
+func newGeneratedCode(code []byte, imports *importsCollector) (*GeneratedCode, error) {
+	newCode, err := format.Source(bytes.TrimSpace(code))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	newCode = append(newCode, '\n')
+	return &GeneratedCode{Code: newCode, Imports: imports.sortedPaths()}, nil
+}
+
+// formatResults formats the results of a function signature, like "", " error" or " (int, error)".
+// formatResults 格式化函数签名中的返回值部分，比如 ""、" error" 或 " (int, error)"。
+func formatResults(results syntaxgo_astnorm.NameTypeElements) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + results[0].Kind
+	default:
+		return " (" + strings.Join(results.Kinds(), ", ") + ")"
+	}
+}
@@ -0,0 +1,128 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+func TestGenerateStub(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	code, err := GenerateStub(astBundle, "Repository", nil)
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "func (r *RepositoryStub) Find(ctx context.Context, name string) (*Account, error) {\n\treturn nil, nil\n}")
+	require.Contains(t, string(code.Code), "func (r *RepositoryStub) Count(arg context.Context) int {\n\treturn 0\n}")
+	require.Equal(t, []string{"context"}, code.Imports)
+
+	// The generated code passes the type check together with the interface.
+	// 生成的代码与接口一起能够通过类型检查。
+	source := append([]byte(interfaceSource), code.Code...)
+	source = append(source, []byte("\nvar _ Repository = (*RepositoryStub)(nil)\n")...)
+	fileSet := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fileSet, "", source, 0))
+	config := &types.Config{Importer: importer.Default()}
+	rese.P1(config.Check("demo", fileSet, []*ast.File{astFile}, nil))
+}
+
+func TestGenerateStub_OtherPackage(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	code, err := GenerateStub(astBundle, "Store", &GenerateOptions{
+		TypeName:    "MemoryStore",
+		PackageName: "demo",
+		ImportPath:  "example.com/demo",
+	})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "type MemoryStore[T any] struct{}")
+	require.Contains(t, string(code.Code), "func (m *MemoryStore[T]) Get(key string) (T, bool) {\n\treturn *new(T), false\n}")
+	// The stub does not refer to the interface package, so it is not imported.
+	// 桩实现没有引用接口所在的包，因此不会导入该包。
+	require.Empty(t, code.Imports)
+
+	code, err = GenerateStub(astBundle, "Finder", &GenerateOptions{PackageName: "demo", ImportPath: "example.com/demo"})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "(*demo.Account, error)")
+	require.Equal(t, []string{"context", "example.com/demo"}, code.Imports)
+}
+
+func TestGenerateStub_OtherPackage_Compiles(t *testing.T) {
+	const source = `package demo
+
+import "context"
+
+type Runner interface {
+	Run(Ctx context.Context, Name string) error
+}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	// The exported param names are not types of the interface package.
+	// 可导出的参数名称不是接口所在包的类型。
+	code, err := GenerateStub(astBundle, "Runner", &GenerateOptions{PackageName: "demo", ImportPath: "example.com/demo"})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Equal(t, []string{"context"}, code.Imports)
+
+	newSource := code.InjectImports([]byte("package stubs\n\n" + string(code.Code)))
+	t.Log(string(newSource))
+	fileSet := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fileSet, "", newSource, 0))
+	config := &types.Config{Importer: importer.Default()}
+	rese.P1(config.Check("stubs", fileSet, []*ast.File{astFile}, nil))
+
+	code, err = GenerateDecorator(astBundle, "Runner", &GenerateOptions{PackageName: "demo", ImportPath: "example.com/demo"})
+	require.NoError(t, err)
+	require.Contains(t, string(code.Code), "next demo.Runner")
+	require.Equal(t, []string{"context", "example.com/demo"}, code.Imports)
+}
+
+func TestGenerateDecorator(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	code, err := GenerateDecorator(astBundle, "Repository", &GenerateOptions{ReceiverName: "ctx"})
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "func NewRepositoryDecorator(next Repository) *RepositoryDecorator {")
+	require.Contains(t, string(code.Code), "func (ctx *RepositoryDecorator) Find(arg context.Context, name string) (*Account, error) {\n\treturn ctx.next.Find(arg, name)\n}")
+	require.Contains(t, string(code.Code), "return ctx.next.Save(arg, accounts...)")
+	require.Contains(t, string(code.Code), "\tctx.next.Reset(arg)\n")
+}
+
+func TestGenerateMock(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	code, err := GenerateMock(astBundle, "Repository", nil)
+	require.NoError(t, err)
+	t.Log(string(code.Code))
+	require.Contains(t, string(code.Code), "FindFunc  func(ctx context.Context, name string) (*Account, error)")
+	require.Contains(t, string(code.Code), "panic(\"RepositoryMock.FindFunc is not set\")")
+
+	newSource := code.InjectImports([]byte("package mocks\n\n" + string(code.Code)))
+	t.Log(string(newSource))
+	require.Contains(t, string(newSource), `import "context"`)
+}
+
+func TestGenerateMock_FuncNameCollision(t *testing.T) {
+	const source = `package demo
+
+type Getter interface {
+	Get(key string) string
+	GetFunc(key string) func() string
+}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	_, err := GenerateMock(astBundle, "Getter", nil)
+	require.ErrorContains(t, err, "GetFunc")
+}
@@ -0,0 +1,312 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+// ProblemKind describes why a method of the interface is not satisfied.
+// ProblemKind 描述接口中的方法未被满足的原因。
+type ProblemKind string
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	PROBLEM_MISSING_METHOD   ProblemKind = "MISSING_METHOD"   // The method does not exist / 方法不存在
+	PROBLEM_WRONG_SIGNATURE  ProblemKind = "WRONG_SIGNATURE"  // The method exists with another signature / 方法存在但签名不同
+	PROBLEM_POINTER_RECEIVER ProblemKind = "POINTER_RECEIVER" // The method has a pointer receiver, but T is checked / 方法是指针接收者，但检查的是 T
+	PROBLEM_AMBIGUOUS        ProblemKind = "AMBIGUOUS"        // The method is ambiguous through embedding / 方法因嵌入而产生歧义
+)
+
+// MethodProblem is a method of the interface which is not satisfied by the type.
+// MethodProblem 是类型未能满足的接口方法。
+type MethodProblem struct {
+	Kind             ProblemKind    // Kind of the problem / 问题类型
+	Name             string         // Method name / 方法名称
+	Expected         string         // Signature required by the interface / 接口要求的签名
+	Actual           string         // Signature found on the type, empty when missing / 类型上找到的签名，缺失时为空
+	ExpectedPosition token.Position // Position of the interface method / 接口方法的位置
+	ActualPosition   token.Position // Position of the method found on the type / 类型上找到的方法的位置
+}
+
+// String returns a readable description of the problem.
+// String 返回问题的可读描述。
+func (problem *MethodProblem) String() string {
+	switch problem.Kind {
+	case PROBLEM_MISSING_METHOD:
+		return "missing method " + problem.Expected + positionSuffix(problem.ExpectedPosition)
+	case PROBLEM_WRONG_SIGNATURE:
+		return "wrong signature of method " + problem.Name + ", expected " + problem.Expected + positionSuffix(problem.ExpectedPosition) + ", actual " + problem.Actual + positionSuffix(problem.ActualPosition)
+	case PROBLEM_POINTER_RECEIVER:
+		return "method " + problem.Name + " has pointer receiver" + positionSuffix(problem.ActualPosition)
+	case PROBLEM_AMBIGUOUS:
+		return "ambiguous selector " + problem.Actual
+	default:
+		return string(problem.Kind) + " " + problem.Name
+	}
+}
+
+// ImplementsResult is the result of checking whether a type implements an interface.
+// ImplementsResult 是检查类型是否实现接口的结果。
+type ImplementsResult struct {
+	TypeName      string           // Checked type, like "T" or "*T" / 被检查的类型，比如 "T" 或 "*T"
+	InterfaceName string           // Name of the interface / 接口名称
+	Problems      []*MethodProblem // Problems of unsatisfied methods, in interface method order / 未满足方法的问题，按接口方法顺序排列
+}
+
+// Satisfied returns whether the type implements the interface.
+// Satisfied 返回类型是否实现了接口。
+func (result *ImplementsResult) Satisfied() bool {
+	return len(result.Problems) == 0
+}
+
+// Report returns a readable report listing each problem on its own line.
+// Report 返回可读的报告，每个问题占一行。
+func (result *ImplementsResult) Report() string {
+	if result.Satisfied() {
+		return result.TypeName + " implements " + result.InterfaceName
+	}
+	ptx := utils.NewPTX()
+	ptx.Println(result.TypeName + " does not implement " + result.InterfaceName + ":")
+	for _, problem := range result.Problems {
+		ptx.Println("\t" + problem.String())
+	}
+	return strings.TrimSuffix(ptx.String(), "\n")
+}
+
+// Error returns nil when the type implements the interface, otherwise an error containing the report.
+// Error 当类型实现接口时返回 nil，否则返回包含报告的错误。
+func (result *ImplementsResult) Error() error {
+	if result.Satisfied() {
+		return nil
+	}
+	return erero.New(result.Report())
+}
+
+// Assertion returns the compile-time assertion statement, like "var _ Repository = (*UserRepository)(nil)".
+// Assertion 返回编译期断言语句，比如 "var _ Repository = (*UserRepository)(nil)"。
+func (result *ImplementsResult) Assertion() string {
+	if name, ok := strings.CutPrefix(result.TypeName, "*"); ok {
+		return "var _ " + result.InterfaceName + " = (*" + name + ")(nil)"
+	}
+	return "var _ " + result.InterfaceName + " = *new(" + result.TypeName + ")"
+}
+
+// Implements checks whether the type implements the interface, the type name can be "T" or "*T".
+// Each missing or mismatched method is reported with the expected and actual signatures and positions.
+// The signatures are compared by the structure of the type expressions, ignoring the names of params and results
+// and taking any as interface{}, but the names are not resolved, so other aliases or different import names of a package mismatch.
+// Implements 检查类型是否实现了接口，类型名称可以是 "T" 或 "*T"。
+// 每个缺失或签名不匹配的方法都会报告期望和实际的签名及位置。
+// 签名按类型表达式的结构进行比较，忽略参数和返回值的名称，并将 any 视为 interface{}，
+// 但不会解析名称，因此其它别名或者同一个包的不同导入名称会被视为不匹配。
+// Generic interfaces are not instantiated, so they get an error instead of misleading problems.
+// 泛型接口不会被实例化，因此返回错误而不是给出误导性的问题。
+func Implements(astBundle *syntaxgo_ast.AstBundle, typeName string, interfaceName string) (*ImplementsResult, error) {
+	name, pointer := strings.CutPrefix(typeName, "*")
+	astFile, _ := astBundle.GetBundle()
+	if typeSpec, ok := FindInterfaceTypeSpec(astFile, interfaceName); ok && typeSpec.TypeParams != nil {
+		return nil, erero.Errorf("interface %s is generic, its methods can not be checked without the type arguments", interfaceName)
+	}
+	methods, err := ExtractInterfaceMethods(astBundle, interfaceName)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	methodSet, err := ComputeMethodSet(astBundle, name, pointer)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	result := &ImplementsResult{TypeName: typeName, InterfaceName: interfaceName}
+	for _, method := range methods {
+		problem := &MethodProblem{
+			Name:             method.Name,
+			Expected:         method.Signature(),
+			ExpectedPosition: method.Position,
+		}
+		if entry, ok := methodSet.Lookup(method.Name); ok {
+			if sameSignature(method.FuncType, entry.FuncType) {
+				continue
+			}
+			problem.Kind = PROBLEM_WRONG_SIGNATURE
+			problem.Actual = entry.Signature()
+			problem.ActualPosition = entry.Position
+		} else if entry, ok := methodSet.LookupExcluded(method.Name); ok {
+			problem.Kind = PROBLEM_POINTER_RECEIVER
+			problem.Actual = entry.Signature()
+			problem.ActualPosition = entry.Position
+		} else if ambiguous, ok := findAmbiguous(methodSet, method.Name); ok {
+			problem.Kind = PROBLEM_AMBIGUOUS
+			problem.Actual = strings.Join(ambiguous.Candidates, ", ")
+		} else {
+			problem.Kind = PROBLEM_MISSING_METHOD
+		}
+		result.Problems = append(result.Problems, problem)
+	}
+	return result, nil
+}
+
+func findAmbiguous(methodSet *MethodSet, name string) (*AmbiguousSelector, bool) {
+	for _, ambiguous := range methodSet.Ambiguous {
+		if ambiguous.Name == name {
+			return ambiguous, true
+		}
+	}
+	return nil, false
+}
+
+// sameSignature compares the types of params and results, the names are ignored, see sameType.
+// sameSignature 比较参数和返回值的类型，忽略名称，参见 sameType。
+func sameSignature(a, b *ast.FuncType) bool {
+	return sameTypes(fieldTypes(a.Params), fieldTypes(b.Params)) && sameTypes(fieldTypes(a.Results), fieldTypes(b.Results))
+}
+
+// fieldTypes returns the type of each param or result, grouped names are expanded.
+// fieldTypes 返回每个参数或返回值的类型，分组的名称会被展开。
+func fieldTypes(fieldList *ast.FieldList) []ast.Expr {
+	var results []ast.Expr
+	if fieldList == nil {
+		return results
+	}
+	for _, field := range fieldList.List {
+		for idx := 0; idx < max(len(field.Names), 1); idx++ {
+			results = append(results, field.Type)
+		}
+	}
+	return results
+}
+
+func sameTypes(a, b []ast.Expr) bool {
+	return slices.EqualFunc(a, b, sameType)
+}
+
+// sameType compares two type expressions by their structure, like Go type identity without resolving the names.
+// The names of params and results in func types are ignored, any is the same as interface{},
+// and the methods of interface types are compared in name order.
+// sameType 按结构比较两个类型表达式，类似不解析名称的 Go 类型一致性。
+// 函数类型中参数和返回值的名称会被忽略，any 与 interface{} 相同，接口类型的方法按名称顺序比较。
+func sameType(a, b ast.Expr) bool {
+	a, b = unwrapTypeExpr(a), unwrapTypeExpr(b)
+	switch x := a.(type) {
+	case *ast.StarExpr:
+		y, ok := b.(*ast.StarExpr)
+		return ok && sameType(x.X, y.X)
+	case *ast.Ellipsis:
+		y, ok := b.(*ast.Ellipsis)
+		return ok && sameType(x.Elt, y.Elt)
+	case *ast.ArrayType:
+		y, ok := b.(*ast.ArrayType)
+		return ok && (x.Len == nil) == (y.Len == nil) && (x.Len == nil || types.ExprString(x.Len) == types.ExprString(y.Len)) && sameType(x.Elt, y.Elt)
+	case *ast.MapType:
+		y, ok := b.(*ast.MapType)
+		return ok && sameType(x.Key, y.Key) && sameType(x.Value, y.Value)
+	case *ast.ChanType:
+		y, ok := b.(*ast.ChanType)
+		return ok && x.Dir == y.Dir && sameType(x.Value, y.Value)
+	case *ast.FuncType:
+		y, ok := b.(*ast.FuncType)
+		return ok && sameSignature(x, y)
+	case *ast.IndexExpr:
+		y, ok := b.(*ast.IndexExpr)
+		return ok && sameType(x.X, y.X) && sameType(x.Index, y.Index)
+	case *ast.IndexListExpr:
+		y, ok := b.(*ast.IndexListExpr)
+		return ok && sameType(x.X, y.X) && sameTypes(x.Indices, y.Indices)
+	case *ast.StructType:
+		y, ok := b.(*ast.StructType)
+		return ok && sameStructFields(x.Fields, y.Fields)
+	case *ast.InterfaceType:
+		y, ok := b.(*ast.InterfaceType)
+		return ok && sameInterfaceMethods(x.Methods, y.Methods)
+	default:
+		return types.ExprString(a) == types.ExprString(b)
+	}
+}
+
+// unwrapTypeExpr removes the parentheses, and turns any into an empty interface type.
+// unwrapTypeExpr 去掉括号，并将 any 转换为空接口类型。
+func unwrapTypeExpr(expr ast.Expr) ast.Expr {
+	for {
+		parenExpr, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = parenExpr.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name == "any" {
+		return &ast.InterfaceType{Methods: &ast.FieldList{}}
+	}
+	return expr
+}
+
+// sameStructFields compares the fields in order, the names, embedding and tags of the fields are part of the type.
+// sameStructFields 按顺序比较字段，字段的名称、嵌入方式以及标签都是类型的一部分。
+func sameStructFields(a, b *ast.FieldList) bool {
+	type structField struct {
+		name string
+		tag  string
+		kind ast.Expr
+	}
+	expand := func(fieldList *ast.FieldList) []structField {
+		var fields []structField
+		for _, field := range fieldList.List {
+			tag := ""
+			if field.Tag != nil {
+				tag, _ = strconv.Unquote(field.Tag.Value)
+			}
+			if len(field.Names) == 0 {
+				fields = append(fields, structField{tag: tag, kind: field.Type})
+			}
+			for _, name := range field.Names {
+				fields = append(fields, structField{name: name.Name, tag: tag, kind: field.Type})
+			}
+		}
+		return fields
+	}
+	return slices.EqualFunc(expand(a), expand(b), func(x, y structField) bool {
+		return x.name == y.name && x.tag == y.tag && sameType(x.kind, y.kind)
+	})
+}
+
+// sameInterfaceMethods compares the methods by name and the embedded types by their printed text, the order is ignored.
+// sameInterfaceMethods 按名称比较方法，按打印文本比较嵌入类型，忽略顺序。
+func sameInterfaceMethods(a, b *ast.FieldList) bool {
+	split := func(fieldList *ast.FieldList) (map[string]*ast.FuncType, []string) {
+		methods := map[string]*ast.FuncType{}
+		var embeds []string
+		for _, field := range fieldList.List {
+			if funcType, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+				methods[field.Names[0].Name] = funcType
+			} else {
+				embeds = append(embeds, types.ExprString(field.Type))
+			}
+		}
+		slices.Sort(embeds)
+		return methods, embeds
+	}
+	methodsA, embedsA := split(a)
+	methodsB, embedsB := split(b)
+	if !slices.Equal(embedsA, embedsB) || len(methodsA) != len(methodsB) {
+		return false
+	}
+	for name, funcType := range methodsA {
+		if other, ok := methodsB[name]; !ok || !sameSignature(funcType, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func positionSuffix(position token.Position) string {
+	if !position.IsValid() {
+		return ""
+	}
+	return " (" + position.String() + ")"
+}
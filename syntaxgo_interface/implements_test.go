@@ -0,0 +1,144 @@
+package syntaxgo_interface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const implementsSource = `package demo
+
+import "context"
+
+type Repository interface {
+	Find(ctx context.Context, id int) (string, error)
+	Save(ctx context.Context, id int, name string) error
+	Close() error
+}
+
+type Closer struct{}
+
+func (c *Closer) Close() error { return nil }
+
+type UserRepository struct {
+	Closer
+}
+
+func (r *UserRepository) Find(ctx context.Context, id int) (string, error) { return "", nil }
+
+func (r *UserRepository) Save(ctx context.Context, id int, name string) error { return nil }
+
+type BrokenRepository struct{}
+
+func (r BrokenRepository) Find(ctx context.Context, id int64) (string, error) { return "", nil }
+
+func (r *BrokenRepository) Close() error { return nil }
+`
+
+func TestImplements(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(implementsSource)))
+
+	result := rese.P1(Implements(astBundle, "*UserRepository", "Repository"))
+	require.True(t, result.Satisfied())
+	require.NoError(t, result.Error())
+	require.Equal(t, "var _ Repository = (*UserRepository)(nil)", result.Assertion())
+	t.Log(result.Report())
+}
+
+func TestImplements_ValueType(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(implementsSource)))
+
+	result := rese.P1(Implements(astBundle, "UserRepository", "Repository"))
+	require.False(t, result.Satisfied())
+	require.Len(t, result.Problems, 3)
+	for _, problem := range result.Problems {
+		require.Equal(t, PROBLEM_POINTER_RECEIVER, problem.Kind)
+		require.True(t, problem.ActualPosition.IsValid())
+	}
+	require.Equal(t, "var _ Repository = *new(UserRepository)", result.Assertion())
+}
+
+func TestImplements_Problems(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(implementsSource)))
+
+	result := rese.P1(Implements(astBundle, "*BrokenRepository", "Repository"))
+	require.False(t, result.Satisfied())
+	require.Error(t, result.Error())
+	require.Len(t, result.Problems, 2)
+
+	mismatched := result.Problems[0]
+	require.Equal(t, PROBLEM_WRONG_SIGNATURE, mismatched.Kind)
+	require.Equal(t, "Find", mismatched.Name)
+	require.Equal(t, "Find(ctx context.Context, id int) (string, error)", mismatched.Expected)
+	require.Equal(t, "Find(ctx context.Context, id int64) (string, error)", mismatched.Actual)
+	require.Equal(t, 6, mismatched.ExpectedPosition.Line)
+	require.Equal(t, 25, mismatched.ActualPosition.Line)
+
+	missing := result.Problems[1]
+	require.Equal(t, PROBLEM_MISSING_METHOD, missing.Kind)
+	require.Equal(t, "Save", missing.Name)
+	require.Empty(t, missing.Actual)
+	t.Log(result.Report())
+}
+
+func TestImplements_NotFound(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(implementsSource)))
+
+	_, err := Implements(astBundle, "*UserRepository", "Unknown")
+	require.Error(t, err)
+}
+
+func TestImplements_EquivalentTypes(t *testing.T) {
+	const source = `package demo
+
+type Handler interface {
+	Handle(value any, visit func(key string, n int) bool) (result interface{ Name() string }, err error)
+	Store(items map[string]interface{}, tagged struct{ ID int ` + "`json:\"id\"`" + ` })
+}
+
+type Impl struct{}
+
+func (Impl) Handle(v interface{}, fn func(string, int) bool) (interface{ Name() string }, error) { return nil, nil }
+
+func (Impl) Store(m map[string]any, s struct{ ID int ` + "`json:\"id\"`" + ` }) {}
+
+type Wrong struct{}
+
+func (Wrong) Handle(v interface{}, fn func(string, int64) bool) (interface{ Name() string }, error) { return nil, nil }
+
+func (Wrong) Store(m map[string]any, s struct{ Key int ` + "`json:\"id\"`" + ` }) {}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	// Param names, nested or not, and any against interface{} do not make a mismatch.
+	// 参数名称（无论是否嵌套）以及 any 与 interface{} 的差异不会造成不匹配。
+	result := rese.P1(Implements(astBundle, "Impl", "Handler"))
+	require.True(t, result.Satisfied(), result.Report())
+
+	result = rese.P1(Implements(astBundle, "Wrong", "Handler"))
+	t.Log(result.Report())
+	require.Len(t, result.Problems, 2)
+	require.Equal(t, PROBLEM_WRONG_SIGNATURE, result.Problems[0].Kind)
+	require.Equal(t, PROBLEM_WRONG_SIGNATURE, result.Problems[1].Kind)
+}
+
+func TestImplements_GenericInterface(t *testing.T) {
+	const source = `package demo
+
+type Repo[T any] interface {
+	Get(id int) (T, error)
+}
+
+type User struct{}
+
+type UserRepo struct{}
+
+func (r *UserRepo) Get(id int) (*User, error) { return nil, nil }
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(source)))
+
+	_, err := Implements(astBundle, "*UserRepo", "Repo")
+	require.ErrorContains(t, err, "generic")
+}
@@ -0,0 +1,77 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+	"golang.org/x/exp/maps"
+)
+
+// importsCollector collects the import paths required by the types used in generated code.
+// importsCollector 收集生成代码中所用类型需要的导入路径。
+type importsCollector struct {
+	importPaths       map[string]string // Package names to import paths of the source file / 源文件中包名到导入路径的映射
+	packageName       string            // Package name of the source types when generating in another package / 在其它包中生成代码时源类型所在的包名
+	importPath        string            // Import path of the source package / 源类型所在包的导入路径
+	genericTypeParams map[string]ast.Expr
+	imports           map[string]bool
+}
+
+func newImportsCollector(astFile *ast.File, packageName string, importPath string, genericTypeParams map[string]ast.Expr) *importsCollector {
+	return &importsCollector{
+		importPaths:       syntaxgo_search.MapImportPathsByName(astFile),
+		packageName:       packageName,
+		importPath:        importPath,
+		genericTypeParams: genericTypeParams,
+		imports:           map[string]bool{},
+	}
+}
+
+// collect collects the import paths of the packages referenced by the type expression.
+// The extraImports take priority over the imports of the source file.
+// Only the types of the fields are walked, so names of params, results and struct fields like Ctx are not taken as types.
+// collect 收集类型表达式引用到的包的导入路径，其中 extraImports 优先于源文件中的导入。
+// 只遍历字段的类型，因此参数、返回值以及结构体字段的名称（比如 Ctx）不会被当作类型。
+func (c *importsCollector) collect(typeExpr ast.Expr, extraImports map[string]string) {
+	var inspect func(node ast.Node) bool
+	inspect = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Field:
+			ast.Inspect(node.Type, inspect)
+			return false
+		case *ast.SelectorExpr:
+			if pkgIdent, ok := node.X.(*ast.Ident); ok {
+				if path, ok := extraImports[pkgIdent.Name]; ok {
+					c.add(path)
+				} else if path, ok := c.importPaths[pkgIdent.Name]; ok {
+					c.add(path)
+				}
+			}
+			return false
+		case *ast.Ident:
+			// Exported local types are referenced through the source package.
+			// 可导出的本地类型通过源类型所在的包引用。
+			if c.packageName != "" && node.IsExported() && types.Universe.Lookup(node.Name) == nil {
+				if _, ok := c.genericTypeParams[node.Name]; !ok {
+					c.add(c.importPath)
+				}
+			}
+		}
+		return true
+	}
+	ast.Inspect(typeExpr, inspect)
+}
+
+func (c *importsCollector) add(path string) {
+	if path != "" {
+		c.imports[path] = true
+	}
+}
+
+func (c *importsCollector) sortedPaths() []string {
+	paths := maps.Keys(c.imports)
+	slices.Sort(paths)
+	return paths
+}
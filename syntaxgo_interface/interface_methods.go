@@ -0,0 +1,300 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_astnorm"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+/*
+Package `syntaxgo_interface` provides tools for working with Go interfaces on top of the syntaxgo AST bundles.
+
+Key features include:
+  - Extracting the method signatures of an interface, including methods of embedded interfaces.
+  - Generating stub implementations, delegating decorators and function-field mocks of an interface.
+
+The bundles can be single files or merged package bundles, so embedded interfaces declared in other files of the same package are resolved too.
+*/
+
+/*
+Package `syntaxgo_interface` 基于 syntaxgo 的 AST 包提供处理 Go 接口的工具。
+
+主要功能包括：
+  - 提取接口的方法签名，包括嵌入接口中的方法。
+  - 为接口生成桩实现、委托装饰器以及函数字段形式的模拟实现。
+
+AST 包既可以是单个文件，也可以是合并后的整个包，因此同包其它文件中声明的嵌入接口也能被解析。
+*/
+
+// InterfaceMethod represents a method of an interface, including the methods from embedded interfaces.
+// InterfaceMethod 表示接口中的一个方法，包括来自嵌入接口的方法。
+type InterfaceMethod struct {
+	Name      string            // Method name / 方法名称
+	Interface string            // Name of the interface declaring the method / 声明该方法的接口名称
+	FuncType  *ast.FuncType     // Signature of the method / 方法签名
+	Doc       *ast.CommentGroup // Doc comment of the method / 方法的文档注释
+	Position  token.Position    // Position of the method, empty when it comes from an imported package / 方法位置，来自外部包时为空
+	imports   map[string]string // Package names to import paths used by methods from imported packages / 来自外部包的方法所用到的包名到导入路径的映射
+}
+
+// InterfaceMethods is a list of InterfaceMethod.
+// InterfaceMethods 是 InterfaceMethod 的列表。
+type InterfaceMethods []*InterfaceMethod
+
+// Signature returns the method signature without the "func" keyword, such as "Read(p []byte) (n int, err error)".
+// Signature 返回不含 "func" 关键字的方法签名，比如 "Read(p []byte) (n int, err error)"。
+func (method *InterfaceMethod) Signature() string {
+	return method.Name + strings.TrimPrefix(types.ExprString(method.FuncType), "func")
+}
+
+// Params returns the normalized params of the method, anonymous and blank params get generated names.
+// Params 返回方法规范化后的参数列表，匿名参数和空白标识符参数会得到生成的名称。
+func (method *InterfaceMethod) Params(packageName string, genericTypeParams map[string]ast.Expr) syntaxgo_astnorm.NameTypeElements {
+	return syntaxgo_astnorm.NewNameTypeElementsV2(method.FuncType.Params, makeParamNameFunction("arg", nil), packageName, genericTypeParams)
+}
+
+// Results returns the normalized results of the method.
+// Results 返回方法规范化后的返回值列表。
+func (method *InterfaceMethod) Results(packageName string, genericTypeParams map[string]ast.Expr) syntaxgo_astnorm.NameTypeElements {
+	return syntaxgo_astnorm.NewNameTypeElementsV2(method.FuncType.Results, syntaxgo_astnorm.SimpleMakeNameFunction("res"), packageName, genericTypeParams)
+}
+
+// Names returns the method names.
+// Names 返回方法名称列表。
+func (methods InterfaceMethods) Names() []string {
+	var names = make([]string, 0, len(methods))
+	for _, method := range methods {
+		names = append(names, method.Name)
+	}
+	return names
+}
+
+// FindMethod finds the method by name.
+// FindMethod 根据名称查找方法。
+func (methods InterfaceMethods) FindMethod(name string) (*InterfaceMethod, bool) {
+	for _, method := range methods {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+// ExtractInterfaceMethods extracts the methods of the interface, embedded interfaces declared in the bundle are expanded in place.
+// Embedded interfaces from imported packages are loaded through the compiler export data, and the predeclared "error" is supported.
+// ExtractInterfaceMethods 提取接口的方法列表，在当前包中声明的嵌入接口会在原位置展开。
+// 来自外部包的嵌入接口通过编译器导出数据加载，同时也支持预声明的 "error" 接口。
+func ExtractInterfaceMethods(astBundle *syntaxgo_ast.AstBundle, interfaceName string) (InterfaceMethods, error) {
+	astFile, fileSet := astBundle.GetBundle()
+	typeSpec, ok := FindInterfaceTypeSpec(astFile, interfaceName)
+	if !ok {
+		return nil, erero.Errorf("no interface name = %s in the bundle", interfaceName)
+	}
+	extractor := &methodsExtractor{
+		astFile:     astFile,
+		fileSet:     fileSet,
+		importPaths: syntaxgo_search.MapImportPathsByName(astFile),
+		importer:    importer.Default(),
+		visited:     map[string]bool{},
+		seen:        map[string]bool{},
+	}
+	if err := extractor.extract(typeSpec); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return extractor.methods, nil
+}
+
+// FindInterfaceTypeSpec finds the type spec of the interface by its name.
+// FindInterfaceTypeSpec 根据名称查找接口的类型声明。
+func FindInterfaceTypeSpec(astFile *ast.File, interfaceName string) (*ast.TypeSpec, bool) {
+	for _, typeSpec := range syntaxgo_search.FindTypes(astFile) {
+		if typeSpec.Name.Name == interfaceName {
+			if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+				return typeSpec, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// methodsExtractor collects interface methods and expands embedded interfaces.
+// methodsExtractor 收集接口方法并展开嵌入接口。
+type methodsExtractor struct {
+	astFile     *ast.File
+	fileSet     *token.FileSet
+	importPaths map[string]string
+	importer    types.Importer
+	visited     map[string]bool // Interfaces being expanded, to report cycles / 正在展开的接口，用于发现循环嵌入
+	seen        map[string]bool // Method names already collected / 已经收集到的方法名称
+	methods     InterfaceMethods
+}
+
+func (x *methodsExtractor) extract(typeSpec *ast.TypeSpec) error {
+	name := typeSpec.Name.Name
+	if x.visited[name] {
+		return erero.Errorf("interface %s embeds itself", name)
+	}
+	x.visited[name] = true
+	defer delete(x.visited, name)
+
+	interfaceType := typeSpec.Type.(*ast.InterfaceType)
+	for _, field := range interfaceType.Methods.List {
+		if len(field.Names) > 0 {
+			funcType, ok := field.Type.(*ast.FuncType)
+			if !ok {
+				return erero.Errorf("interface %s has a wrong method %s", name, field.Names[0].Name)
+			}
+			for _, methodName := range field.Names {
+				x.append(&InterfaceMethod{
+					Name:      methodName.Name,
+					Interface: name,
+					FuncType:  funcType,
+					Doc:       field.Doc,
+					Position:  x.fileSet.Position(methodName.Pos()),
+				})
+			}
+			continue
+		}
+		if err := x.extractEmbedded(name, field.Type); err != nil {
+			return erero.Wro(err)
+		}
+	}
+	return nil
+}
+
+func (x *methodsExtractor) extractEmbedded(name string, embedded ast.Expr) error {
+	switch node := embedded.(type) {
+	case *ast.Ident:
+		if typeSpec, ok := FindInterfaceTypeSpec(x.astFile, node.Name); ok {
+			if typeSpec.TypeParams != nil {
+				return erero.Errorf("interface %s embeds generic interface %s which is not supported", name, node.Name)
+			}
+			return x.extract(typeSpec)
+		}
+		if node.Name == "error" {
+			x.append(&InterfaceMethod{
+				Name:      "Error",
+				Interface: "error",
+				FuncType:  &ast.FuncType{Params: &ast.FieldList{}, Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}}},
+			})
+			return nil
+		}
+		if node.Name == "comparable" || node.Name == "any" {
+			return nil // Constraint elements without methods / 不包含方法的约束元素
+		}
+		return erero.Errorf("interface %s embeds interface %s which is not in the bundle", name, node.Name)
+	case *ast.SelectorExpr:
+		return x.extractImported(node)
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return erero.Errorf("interface %s embeds generic interface %s which is not supported", name, types.ExprString(embedded))
+	default:
+		return nil // Type constraint elements like ~int | ~string / 类型约束元素，比如 ~int | ~string
+	}
+}
+
+// extractImported loads the embedded interface of an imported package through the compiler export data.
+// extractImported 通过编译器导出数据加载外部包中的嵌入接口。
+func (x *methodsExtractor) extractImported(selectorExpr *ast.SelectorExpr) error {
+	object, err := lookupImportedType(x.importer, x.importPaths, selectorExpr)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	interfaceType, ok := object.Type().Underlying().(*types.Interface)
+	if !ok {
+		return erero.Errorf("type %s is not an interface", types.ExprString(selectorExpr))
+	}
+
+	imports := map[string]string{}
+	qualifier := newImportsQualifier(x.importPaths, imports)
+	for idx := 0; idx < interfaceType.NumMethods(); idx++ {
+		function := interfaceType.Method(idx)
+		funcType, err := newFuncTypeFromSignature(function.Type().(*types.Signature), qualifier)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		x.append(&InterfaceMethod{
+			Name:      function.Name(),
+			Interface: types.ExprString(selectorExpr),
+			FuncType:  funcType,
+			imports:   imports,
+		})
+	}
+	return nil
+}
+
+func (x *methodsExtractor) append(method *InterfaceMethod) {
+	if x.seen[method.Name] {
+		return // Duplicate methods through embedding are merged / 通过嵌入产生的重复方法只保留一个
+	}
+	x.seen[method.Name] = true
+	x.methods = append(x.methods, method)
+}
+
+// makeParamNameFunction makes names for params, anonymous params, blank params and reserved names get generated names.
+// makeParamNameFunction 为参数生成名称，匿名参数、空白标识符参数以及保留名称会得到生成的名称。
+func makeParamNameFunction(prefix string, reservedNames map[string]bool) syntaxgo_astnorm.MakeNameFunction {
+	simpleMakeName := syntaxgo_astnorm.SimpleMakeNameFunction(prefix)
+	return func(ident *ast.Ident, kind string, nameIndex int, anonymousIndex int) string {
+		if ident != nil && (ident.Name == "_" || reservedNames[ident.Name]) {
+			ident = nil
+		}
+		return simpleMakeName(ident, kind, nameIndex, anonymousIndex)
+	}
+}
+
+// lookupImportedType looks up the type named by the selector, like io.Reader, through the compiler export data.
+// lookupImportedType 通过编译器导出数据查找选择器表示的类型，比如 io.Reader。
+func lookupImportedType(typesImporter types.Importer, importPaths map[string]string, selectorExpr *ast.SelectorExpr) (*types.TypeName, error) {
+	pkgIdent, ok := selectorExpr.X.(*ast.Ident)
+	if !ok {
+		return nil, erero.Errorf("wrong type %s", types.ExprString(selectorExpr))
+	}
+	importPath, ok := importPaths[pkgIdent.Name]
+	if !ok {
+		return nil, erero.Errorf("type %s is used without import", types.ExprString(selectorExpr))
+	}
+	pkg, err := typesImporter.Import(importPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	object, ok := pkg.Scope().Lookup(selectorExpr.Sel.Name).(*types.TypeName)
+	if !ok {
+		return nil, erero.Errorf("no type %s in package %s", selectorExpr.Sel.Name, importPath)
+	}
+	return object, nil
+}
+
+// newImportsQualifier qualifies the types of imported packages with the names used in the file, and records the used imports.
+// newImportsQualifier 使用文件中的包名来限定外部包中的类型，并记录用到的导入。
+func newImportsQualifier(importPaths map[string]string, imports map[string]string) types.Qualifier {
+	return func(pkg *types.Package) string {
+		for pkgName, path := range importPaths {
+			if path == pkg.Path() {
+				imports[pkgName] = path
+				return pkgName
+			}
+		}
+		imports[pkg.Name()] = pkg.Path()
+		return pkg.Name()
+	}
+}
+
+// newFuncTypeFromSignature converts the go/types signature into an AST function type.
+// newFuncTypeFromSignature 把 go/types 的函数签名转换为 AST 函数类型。
+func newFuncTypeFromSignature(signature *types.Signature, qualifier types.Qualifier) (*ast.FuncType, error) {
+	// The receiver is not printed, since it is not part of the function type.
+	// 接收者不会被打印，因为它不是函数类型的一部分。
+	funcExpr, err := parser.ParseExpr(types.TypeString(types.NewSignatureType(nil, nil, nil, signature.Params(), signature.Results(), signature.Variadic()), qualifier))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return funcExpr.(*ast.FuncType), nil
+}
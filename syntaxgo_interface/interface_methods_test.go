@@ -0,0 +1,83 @@
+package syntaxgo_interface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const interfaceSource = `package demo
+
+import (
+	"context"
+	"io"
+)
+
+type Account struct {
+	Name string
+}
+
+// Finder finds accounts.
+type Finder interface {
+	// Find returns the account by name.
+	Find(ctx context.Context, name string) (*Account, error)
+}
+
+type Repository interface {
+	Finder
+	io.Closer
+	error
+	Save(ctx context.Context, accounts ...*Account) error
+	Count(context.Context) int
+	Reset(_ bool)
+}
+
+type Store[T any] interface {
+	Get(key string) (T, bool)
+	Put(key string, value T)
+}
+`
+
+func TestExtractInterfaceMethods(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	methods, err := ExtractInterfaceMethods(astBundle, "Repository")
+	require.NoError(t, err)
+	for _, method := range methods {
+		t.Log(method.Interface, method.Signature(), method.Position)
+	}
+	require.Equal(t, []string{"Find", "Close", "Error", "Save", "Count", "Reset"}, methods.Names())
+
+	method, ok := methods.FindMethod("Find")
+	require.True(t, ok)
+	require.Equal(t, "Finder", method.Interface)
+	require.Equal(t, "Find(ctx context.Context, name string) (*Account, error)", method.Signature())
+	require.Equal(t, 15, method.Position.Line)
+	require.Equal(t, "Find returns the account by name.\n", method.Doc.Text())
+}
+
+func TestExtractInterfaceMethods_NotFound(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	_, err := ExtractInterfaceMethods(astBundle, "Account")
+	require.Error(t, err)
+	t.Log(err)
+}
+
+func TestInterfaceMethod_Params(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(interfaceSource)))
+
+	methods := rese.V1(ExtractInterfaceMethods(astBundle, "Repository"))
+	method, ok := methods.FindMethod("Save")
+	require.True(t, ok)
+
+	params := method.Params("demo", nil)
+	require.Equal(t, []string{"context.Context", "...*demo.Account"}, params.Kinds())
+	require.Equal(t, "ctx, accounts...", params.GenerateFunctionParams().MergeParts())
+
+	method, ok = methods.FindMethod("Reset")
+	require.True(t, ok)
+	require.Equal(t, "arg", method.Params("", nil).Names().MergeParts())
+}
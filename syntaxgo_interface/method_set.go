@@ -0,0 +1,471 @@
+package syntaxgo_interface
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+	"golang.org/x/exp/maps"
+)
+
+// MethodSetEntry is a method in the method set of a type, either declared on the type or promoted from embedded fields.
+// MethodSetEntry 是类型方法集中的一个方法，可能声明在类型本身上，也可能是从嵌入字段提升而来。
+type MethodSetEntry struct {
+	Name        string         // Method name / 方法名称
+	Receiver    string         // Type declaring the method, like "Base" or "io.Reader" / 声明该方法的类型，比如 "Base" 或 "io.Reader"
+	PointerRecv bool           // Whether the method is declared with a pointer receiver / 方法是否使用指针接收者声明
+	FuncType    *ast.FuncType  // Signature of the method / 方法签名
+	Path        []string       // Embedded fields leading to the method, empty for declared methods / 通向该方法的嵌入字段路径，直接声明的方法为空
+	Depth       int            // Embedding depth, 0 for declared methods / 嵌入深度，直接声明的方法为 0
+	Position    token.Position // Position of the method, empty when it comes from an imported package / 方法位置，来自外部包时为空
+	imports     map[string]string
+}
+
+// Signature returns the method signature without the "func" keyword, such as "Read(p []byte) (n int, err error)".
+// Signature 返回不含 "func" 关键字的方法签名，比如 "Read(p []byte) (n int, err error)"。
+func (entry *MethodSetEntry) Signature() string {
+	return entry.Name + strings.TrimPrefix(types.ExprString(entry.FuncType), "func")
+}
+
+// Selector returns the full selector of the method, like "Base.Logger.Print".
+// Selector 返回方法的完整选择器，比如 "Base.Logger.Print"。
+func (entry *MethodSetEntry) Selector() string {
+	return strings.Join(append(slices.Clone(entry.Path), entry.Name), ".")
+}
+
+// AmbiguousSelector is a selector reached through several embedded fields at the same depth, so it is not promoted.
+// AmbiguousSelector 是在同一深度通过多个嵌入字段得到的选择器，因此不会被提升。
+type AmbiguousSelector struct {
+	Name       string   // Selector name / 选择器名称
+	Depth      int      // Embedding depth of the conflict / 冲突所在的嵌入深度
+	Candidates []string // Conflicting selectors, like "A.Close" and "B.Close" / 冲突的选择器，比如 "A.Close" 和 "B.Close"
+}
+
+// MethodSet is the method set of T or *T computed from the AST bundle.
+// MethodSet 是根据 AST 包计算得到的 T 或 *T 的方法集。
+type MethodSet struct {
+	TypeName   string               // Name of the type / 类型名称
+	Pointer    bool                 // Whether it is the method set of *T / 是否为 *T 的方法集
+	Methods    []*MethodSetEntry    // Methods sorted by name / 按名称排序的方法
+	Ambiguous  []*AmbiguousSelector // Ambiguous selectors at the same depth / 同一深度存在歧义的选择器
+	Unresolved []string             // Embedded types which can not be resolved / 无法解析的嵌入类型
+	excluded   map[string]*MethodSetEntry
+}
+
+// Lookup finds the method in the method set by name.
+// Lookup 根据名称在方法集中查找方法。
+func (methodSet *MethodSet) Lookup(name string) (*MethodSetEntry, bool) {
+	for _, entry := range methodSet.Methods {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// LookupExcluded finds the method which exists on the type but is not in the method set, like a pointer method of T.
+// LookupExcluded 查找类型上存在但不在方法集中的方法，比如 T 的指针接收者方法。
+func (methodSet *MethodSet) LookupExcluded(name string) (*MethodSetEntry, bool) {
+	entry, ok := methodSet.excluded[name]
+	return entry, ok
+}
+
+// Names returns the method names in the method set.
+// Names 返回方法集中的方法名称列表。
+func (methodSet *MethodSet) Names() []string {
+	var names = make([]string, 0, len(methodSet.Methods))
+	for _, entry := range methodSet.Methods {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+// ComputeMethodSet computes the method set of T, or *T when pointer is true, following the Go rules:
+//   - The method set of T contains methods with value receivers, the method set of *T contains all methods.
+//   - Methods of embedded fields are promoted, embedding *S promotes methods with receiver S and *S.
+//   - Methods of embedded interfaces are promoted.
+//   - The shallowest selector wins, and selectors conflicting at the same depth are reported as ambiguous.
+//
+// Types declared in the bundle are resolved through the AST, imported types are resolved through the compiler export data.
+// ComputeMethodSet 按照 Go 的规则计算 T 的方法集，当 pointer 为 true 时计算 *T 的方法集：
+//   - T 的方法集包含值接收者方法，*T 的方法集包含全部方法。
+//   - 嵌入字段的方法会被提升，嵌入 *S 时会提升接收者为 S 和 *S 的方法。
+//   - 嵌入接口的方法会被提升。
+//   - 最浅的选择器优先，同一深度冲突的选择器会被报告为歧义。
+//
+// 当前包中声明的类型通过 AST 解析，外部包中的类型通过编译器导出数据解析。
+func ComputeMethodSet(astBundle *syntaxgo_ast.AstBundle, typeName string, pointer bool) (*MethodSet, error) {
+	astFile, fileSet := astBundle.GetBundle()
+	typeSpec, ok := findTypeSpec(astFile, typeName)
+	if !ok {
+		return nil, erero.Errorf("no type name = %s in the bundle", typeName)
+	}
+	if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+		return nil, erero.Errorf("type %s is an interface", typeName)
+	}
+	computer := &methodSetComputer{
+		astBundle:   astBundle,
+		astFile:     astFile,
+		fileSet:     fileSet,
+		importPaths: syntaxgo_search.MapImportPathsByName(astFile),
+		importer:    importer.Default(),
+		functions:   syntaxgo_search.FindFunctions(astFile),
+	}
+	return computer.compute(typeName, pointer), nil
+}
+
+// embeddedType is a type reached through embedding during the breadth-first search.
+// embeddedType 是广度优先搜索过程中通过嵌入到达的类型。
+type embeddedType struct {
+	typeExpr    ast.Expr   // *ast.Ident for local types, *ast.SelectorExpr for imported types / 本地类型为 *ast.Ident，外部类型为 *ast.SelectorExpr
+	typ         types.Type // Type embedded inside an imported type, set instead of typeExpr / 嵌入在外部类型中的类型，设置时不使用 typeExpr
+	addressable bool       // Whether methods with pointer receivers are included / 是否包含指针接收者方法
+	path        []string
+}
+
+// selectorCandidate is a method or field found at one depth.
+// selectorCandidate 是在某个深度找到的方法或字段。
+type selectorCandidate struct {
+	entry    *MethodSetEntry // nil for fields / 字段时为 nil
+	selector string
+	included bool // Whether the method belongs to the method set / 方法是否属于方法集
+}
+
+type methodSetComputer struct {
+	astBundle   *syntaxgo_ast.AstBundle
+	astFile     *ast.File
+	fileSet     *token.FileSet
+	importPaths map[string]string
+	importer    types.Importer
+	functions   []*ast.FuncDecl
+}
+
+func (c *methodSetComputer) compute(typeName string, pointer bool) *MethodSet {
+	methodSet := &MethodSet{TypeName: typeName, Pointer: pointer, excluded: map[string]*MethodSetEntry{}}
+	taken := map[string]bool{}   // Selectors found at shallower depths / 在更浅深度找到的选择器
+	visited := map[string]bool{} // Types expanded at shallower depths / 在更浅深度展开过的类型
+	current := []*embeddedType{{typeExpr: ast.NewIdent(typeName), addressable: pointer}}
+	for depth := 0; len(current) > 0; depth++ {
+		candidates := map[string][]*selectorCandidate{}
+		var next []*embeddedType
+		for _, item := range current {
+			if visited[c.typeKey(item)] {
+				continue
+			}
+			next = append(next, c.expand(item, depth, candidates, methodSet)...)
+		}
+		for _, item := range current {
+			visited[c.typeKey(item)] = true
+		}
+		names := maps.Keys(candidates)
+		slices.Sort(names)
+		for _, name := range names {
+			if taken[name] {
+				continue // Shadowed by a shallower selector / 被更浅的选择器遮蔽
+			}
+			taken[name] = true
+			list := candidates[name]
+			if len(list) > 1 {
+				ambiguous := &AmbiguousSelector{Name: name, Depth: depth}
+				for _, candidate := range list {
+					ambiguous.Candidates = append(ambiguous.Candidates, candidate.selector)
+				}
+				methodSet.Ambiguous = append(methodSet.Ambiguous, ambiguous)
+				continue
+			}
+			if candidate := list[0]; candidate.entry != nil {
+				if candidate.included {
+					methodSet.Methods = append(methodSet.Methods, candidate.entry)
+				} else {
+					methodSet.excluded[name] = candidate.entry
+				}
+			}
+		}
+		current = next
+	}
+	slices.SortFunc(methodSet.Methods, func(a, b *MethodSetEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return methodSet
+}
+
+// typeKey names the type of the item, an imported type is named by its import path so that it is visited once.
+// typeKey 返回该项的类型名称，外部类型使用其导入路径命名，从而只被访问一次。
+func (c *methodSetComputer) typeKey(item *embeddedType) string {
+	if item.typ != nil {
+		return types.TypeString(item.typ, nil)
+	}
+	if selectorExpr, ok := item.typeExpr.(*ast.SelectorExpr); ok {
+		if pkgIdent, ok := selectorExpr.X.(*ast.Ident); ok {
+			if importPath, ok := c.importPaths[pkgIdent.Name]; ok {
+				return importPath + "." + selectorExpr.Sel.Name
+			}
+		}
+	}
+	return types.ExprString(item.typeExpr)
+}
+
+// expand collects the methods and fields of the type into candidates, and returns the embedded types of the next depth.
+// expand 把类型的方法和字段收集到候选集合中，并返回下一深度的嵌入类型。
+func (c *methodSetComputer) expand(item *embeddedType, depth int, candidates map[string][]*selectorCandidate, methodSet *MethodSet) []*embeddedType {
+	addCandidate := func(name string, candidate *selectorCandidate) {
+		candidate.selector = strings.Join(append(slices.Clone(item.path), name), ".")
+		candidates[name] = append(candidates[name], candidate)
+	}
+	if item.typ != nil {
+		return c.expandImported(item.typ, item, depth, addCandidate, methodSet)
+	}
+
+	typeExpr := item.typeExpr
+	// Follow the alias specs like "type A = B", the methods are those of the aliased type.
+	// 跟随类似 "type A = B" 的别名声明，其方法是被别名的类型的方法。
+	for seen := map[string]bool{}; ; {
+		ident, ok := typeExpr.(*ast.Ident)
+		if !ok {
+			break
+		}
+		typeSpec, ok := findTypeSpec(c.astFile, ident.Name)
+		if !ok || !typeSpec.Assign.IsValid() {
+			break
+		}
+		if seen[ident.Name] {
+			methodSet.Unresolved = append(methodSet.Unresolved, ident.Name)
+			return nil
+		}
+		seen[ident.Name] = true
+		if typeExpr, _ = unwrapEmbeddedType(typeSpec.Type); typeExpr == nil {
+			methodSet.Unresolved = append(methodSet.Unresolved, ident.Name)
+			return nil
+		}
+	}
+
+	switch typeExpr := typeExpr.(type) {
+	case *ast.SelectorExpr:
+		object, err := lookupImportedType(c.importer, c.importPaths, typeExpr)
+		if err != nil {
+			methodSet.Unresolved = append(methodSet.Unresolved, types.ExprString(typeExpr))
+			return nil
+		}
+		return c.expandImported(object.Type(), item, depth, addCandidate, methodSet)
+	case *ast.Ident:
+		typeSpec, ok := findTypeSpec(c.astFile, typeExpr.Name)
+		if !ok {
+			if typeExpr.Name == "error" {
+				addCandidate("Error", &selectorCandidate{included: true, entry: &MethodSetEntry{
+					Name:     "Error",
+					Receiver: "error",
+					FuncType: &ast.FuncType{Params: &ast.FieldList{}, Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}}},
+					Path:     item.path,
+					Depth:    depth,
+				}})
+				return nil
+			}
+			methodSet.Unresolved = append(methodSet.Unresolved, typeExpr.Name)
+			return nil
+		}
+		if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+			methods, err := ExtractInterfaceMethods(c.astBundle, typeExpr.Name)
+			if err != nil {
+				methodSet.Unresolved = append(methodSet.Unresolved, typeExpr.Name)
+				return nil
+			}
+			for _, method := range methods {
+				addCandidate(method.Name, &selectorCandidate{included: true, entry: &MethodSetEntry{
+					Name:     method.Name,
+					Receiver: typeExpr.Name,
+					FuncType: method.FuncType,
+					Path:     item.path,
+					Depth:    depth,
+					Position: method.Position,
+					imports:  method.imports,
+				}})
+			}
+			return nil
+		}
+		recvNames := c.receiverNames(typeExpr.Name)
+		for _, function := range c.functions {
+			recvName, isPointer, ok := syntaxgo_search.GetReceiverTypeName(function)
+			if !ok || !slices.Contains(recvNames, recvName) {
+				continue
+			}
+			addCandidate(function.Name.Name, &selectorCandidate{included: item.addressable || !isPointer, entry: &MethodSetEntry{
+				Name:        function.Name.Name,
+				Receiver:    typeExpr.Name,
+				PointerRecv: isPointer,
+				FuncType:    function.Type,
+				Path:        item.path,
+				Depth:       depth,
+				Position:    c.fileSet.Position(function.Name.Pos()),
+			}})
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return nil
+		}
+		var next []*embeddedType
+		for _, field := range structType.Fields.List {
+			for _, name := range field.Names {
+				addCandidate(name.Name, &selectorCandidate{})
+			}
+			if len(field.Names) > 0 {
+				continue
+			}
+			embeddedExpr, isPointer := unwrapEmbeddedType(field.Type)
+			if embeddedExpr == nil {
+				methodSet.Unresolved = append(methodSet.Unresolved, types.ExprString(field.Type))
+				continue
+			}
+			var name string
+			switch node := embeddedExpr.(type) {
+			case *ast.Ident:
+				name = node.Name
+			case *ast.SelectorExpr:
+				name = node.Sel.Name
+			}
+			addCandidate(name, &selectorCandidate{})
+			next = append(next, &embeddedType{
+				typeExpr:    embeddedExpr,
+				addressable: item.addressable || isPointer,
+				path:        append(slices.Clone(item.path), name),
+			})
+		}
+		return next
+	default:
+		methodSet.Unresolved = append(methodSet.Unresolved, types.ExprString(item.typeExpr))
+		return nil
+	}
+}
+
+// receiverNames returns the type name together with the names of its aliases, the methods can name any of them as receiver.
+// receiverNames 返回类型名称及其别名，方法可以使用其中任一名称作为接收者。
+func (c *methodSetComputer) receiverNames(typeName string) []string {
+	names := []string{typeName}
+	for idx := 0; idx < len(names); idx++ {
+		for _, typeSpec := range syntaxgo_search.FindTypes(c.astFile) {
+			if !typeSpec.Assign.IsValid() || slices.Contains(names, typeSpec.Name.Name) {
+				continue
+			}
+			if ident, ok := typeSpec.Type.(*ast.Ident); ok && ident.Name == names[idx] {
+				names = append(names, typeSpec.Name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// expandImported collects the methods and exported fields of an imported type through the compiler export data,
+// and returns its embedded fields of the next depth, the same as expand does for the local types.
+// expandImported 通过编译器导出数据收集外部类型的方法和导出字段，
+// 并返回其下一深度的嵌入字段，与 expand 对本地类型的处理相同。
+func (c *methodSetComputer) expandImported(typ types.Type, item *embeddedType, depth int, addCandidate func(string, *selectorCandidate), methodSet *MethodSet) []*embeddedType {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		methodSet.Unresolved = append(methodSet.Unresolved, types.TypeString(typ, nil))
+		return nil
+	}
+	receiver := named.Obj().Name()
+	if pkg := named.Obj().Pkg(); pkg != nil {
+		receiver = pkg.Name() + "." + receiver
+	}
+	imports := map[string]string{}
+	qualifier := newImportsQualifier(c.importPaths, imports)
+	addMethod := func(function *types.Func) {
+		if !function.Exported() {
+			return
+		}
+		signature := function.Type().(*types.Signature)
+		funcType, err := newFuncTypeFromSignature(signature, qualifier)
+		if err != nil {
+			methodSet.Unresolved = append(methodSet.Unresolved, receiver+"."+function.Name())
+			return
+		}
+		var isPointer bool
+		if recv := signature.Recv(); recv != nil {
+			_, isPointer = recv.Type().(*types.Pointer)
+		}
+		addCandidate(function.Name(), &selectorCandidate{included: item.addressable || !isPointer, entry: &MethodSetEntry{
+			Name:        function.Name(),
+			Receiver:    receiver,
+			PointerRecv: isPointer,
+			FuncType:    funcType,
+			Path:        item.path,
+			Depth:       depth,
+			imports:     imports,
+		}})
+	}
+	if interfaceType, ok := named.Underlying().(*types.Interface); ok {
+		for idx := 0; idx < interfaceType.NumMethods(); idx++ {
+			addMethod(interfaceType.Method(idx))
+		}
+		return nil
+	}
+	for idx := 0; idx < named.NumMethods(); idx++ {
+		addMethod(named.Method(idx))
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var next []*embeddedType
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		field := structType.Field(idx)
+		// The unexported names of another package never conflict with the names in this package.
+		// 其他包中未导出的名称永远不会与本包中的名称冲突。
+		if field.Exported() {
+			addCandidate(field.Name(), &selectorCandidate{})
+		}
+		if !field.Embedded() {
+			continue
+		}
+		fieldType, isPointer := field.Type(), false
+		if pointer, ok := fieldType.(*types.Pointer); ok {
+			fieldType, isPointer = pointer.Elem(), true
+		}
+		next = append(next, &embeddedType{
+			typ:         fieldType,
+			addressable: item.addressable || isPointer,
+			path:        append(slices.Clone(item.path), field.Name()),
+		})
+	}
+	return next
+}
+
+// unwrapEmbeddedType returns the type name expression of the embedded field, and whether it is embedded as a pointer.
+// unwrapEmbeddedType 返回嵌入字段的类型名称表达式，以及是否以指针形式嵌入。
+func unwrapEmbeddedType(typeExpr ast.Expr) (ast.Expr, bool) {
+	var isPointer = false
+	for {
+		switch node := typeExpr.(type) {
+		case *ast.StarExpr:
+			isPointer = true
+			typeExpr = node.X
+		case *ast.ParenExpr:
+			typeExpr = node.X
+		case *ast.IndexExpr:
+			typeExpr = node.X
+		case *ast.IndexListExpr:
+			typeExpr = node.X
+		case *ast.Ident, *ast.SelectorExpr:
+			return node, isPointer
+		default:
+			return nil, false
+		}
+	}
+}
+
+// findTypeSpec finds the type spec by its name.
+// findTypeSpec 根据名称查找类型声明。
+func findTypeSpec(astFile *ast.File, typeName string) (*ast.TypeSpec, bool) {
+	for _, typeSpec := range syntaxgo_search.FindTypes(astFile) {
+		if typeSpec.Name.Name == typeName {
+			return typeSpec, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+package syntaxgo_interface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const methodSetSource = `package demo
+
+import (
+	"bytes"
+	"io"
+)
+
+type Logger interface {
+	Print(message string)
+}
+
+type Base struct {
+	Logger
+	ID int
+}
+
+func (b Base) Key() string { return "" }
+
+func (b *Base) SetKey(key string) {}
+
+func (b *Base) Close() error { return nil }
+
+type Cache struct{}
+
+func (c *Cache) Close() error { return nil }
+
+func (c *Cache) Flush() {}
+
+type Service struct {
+	Base
+	*Cache
+	io.Reader
+	buffer *bytes.Buffer
+}
+
+func (s *Service) Run() {}
+
+func (s Service) Name() string { return "" }
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(item T) {}
+
+func (s Stack[T]) Size() int { return len(s.items) }
+`
+
+func TestComputeMethodSet(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(methodSetSource)))
+
+	methodSet, err := ComputeMethodSet(astBundle, "Service", false)
+	require.NoError(t, err)
+	for _, entry := range methodSet.Methods {
+		t.Log(entry.Depth, entry.Selector(), entry.Signature(), entry.Receiver, entry.PointerRecv)
+	}
+	// Base is embedded as value, so *Base methods are not promoted into the method set of Service.
+	// Base 以值的方式嵌入，因此 *Base 的方法不会被提升到 Service 的方法集中。
+	require.Equal(t, []string{"Flush", "Key", "Name", "Print", "Read"}, methodSet.Names())
+	require.Len(t, methodSet.Ambiguous, 1)
+	require.Equal(t, "Close", methodSet.Ambiguous[0].Name)
+	require.Equal(t, []string{"Base.Close", "Cache.Close"}, methodSet.Ambiguous[0].Candidates)
+
+	entry, ok := methodSet.LookupExcluded("Run")
+	require.True(t, ok)
+	require.True(t, entry.PointerRecv)
+
+	entry, ok = methodSet.Lookup("Print")
+	require.True(t, ok)
+	require.Equal(t, "Base.Logger.Print", entry.Selector())
+	require.Equal(t, 2, entry.Depth)
+}
+
+func TestComputeMethodSet_Pointer(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(methodSetSource)))
+
+	methodSet, err := ComputeMethodSet(astBundle, "Service", true)
+	require.NoError(t, err)
+	t.Log(methodSet.Names())
+	require.Equal(t, []string{"Flush", "Key", "Name", "Print", "Read", "Run", "SetKey"}, methodSet.Names())
+	require.Empty(t, methodSet.Unresolved)
+
+	methodSet, err = ComputeMethodSet(astBundle, "Stack", true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Push", "Size"}, methodSet.Names())
+
+	methodSet, err = ComputeMethodSet(astBundle, "Stack", false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Size"}, methodSet.Names())
+}
+
+func TestComputeMethodSet_Interface(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(methodSetSource)))
+
+	_, err := ComputeMethodSet(astBundle, "Logger", false)
+	require.Error(t, err)
+}
+
+const promotedSource = `package demo
+
+import "bufio"
+
+type Inner struct{}
+
+func (Inner) Read(p []byte) (int, error) { return 0, nil }
+
+type Alias = Inner
+
+func (Alias) Peek() {}
+
+type Deep struct{ Level }
+
+type Level struct{}
+
+func (Level) Reader() {}
+
+type T struct {
+	Inner
+	*bufio.ReadWriter
+}
+
+type U struct {
+	*bufio.ReadWriter
+	Deep
+}
+
+type V struct{ Alias }
+`
+
+func TestComputeMethodSet_ImportedDepth(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(promotedSource)))
+
+	// Inner.Read at depth 1 wins over bufio.Reader.Read at depth 2.
+	// 深度 1 的 Inner.Read 优先于深度 2 的 bufio.Reader.Read。
+	methodSet := rese.P1(ComputeMethodSet(astBundle, "T", true))
+	entry, ok := methodSet.Lookup("Read")
+	require.True(t, ok)
+	require.Equal(t, "Inner.Read", entry.Selector())
+	require.Equal(t, 1, entry.Depth)
+
+	entry, ok = methodSet.Lookup("ReadString")
+	require.True(t, ok)
+	require.Equal(t, "ReadWriter.Reader.ReadString", entry.Selector())
+	require.Equal(t, 2, entry.Depth)
+	require.Equal(t, "bufio.Reader", entry.Receiver)
+
+	// The field ReadWriter.Reader at depth 1 shadows the method Deep.Level.Reader at depth 2.
+	// 深度 1 的字段 ReadWriter.Reader 遮蔽了深度 2 的方法 Deep.Level.Reader。
+	methodSet = rese.P1(ComputeMethodSet(astBundle, "U", true))
+	_, ok = methodSet.Lookup("Reader")
+	require.False(t, ok)
+	require.Contains(t, methodSet.Names(), "Flush")
+	require.Empty(t, methodSet.Unresolved)
+
+	// The alias is followed, the methods can be declared with the alias as receiver.
+	// 会跟随别名，方法可以使用别名作为接收者声明。
+	methodSet = rese.P1(ComputeMethodSet(astBundle, "V", false))
+	require.Equal(t, []string{"Peek", "Read"}, methodSet.Names())
+	entry, ok = methodSet.Lookup("Read")
+	require.True(t, ok)
+	require.Equal(t, "Alias.Read", entry.Selector())
+}
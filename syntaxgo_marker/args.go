@@ -0,0 +1,233 @@
+package syntaxgo_marker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// Arg is an argument of a marker, like "table=users", "soft-delete" or "columns={id,name}".
+// Arg 是标记的参数，比如 "table=users"、"soft-delete" 或 "columns={id,name}"。
+type Arg struct {
+	Key    string   // Argument key, empty for the anonymous argument like "+gen:maximum=10" / 参数键，形如 "+gen:maximum=10" 的匿名参数为空
+	Value  string   // Unquoted value, "true" for a flag, items joined with "," for a list / 去掉引号后的值，标志为 "true"，列表为用 "," 连接的条目
+	Values []string // Items of a list, or the single value / 列表的条目，或者单个值
+	IsFlag bool     // Whether the argument is a flag without value / 参数是否为没有值的标志
+	IsList bool     // Whether the value is a list like {a,b} or a;b / 值是否为形如 {a,b} 或 a;b 的列表
+}
+
+// Args is the list of arguments of a marker, in source order.
+// Args 是标记的参数列表，按源码顺序排列。
+type Args []*Arg
+
+// ParseArgs parses the arguments separated by spaces or commas.
+// Values can be bare words, quoted strings, or lists like {a,"b c"} or a;b.
+// ParseArgs 解析以空格或逗号分隔的参数。
+// 值可以是单词、带引号的字符串，或者形如 {a,"b c"} 或 a;b 的列表。
+func ParseArgs(content string) (Args, error) {
+	var args Args
+	reader := &argsReader{content: content}
+	for {
+		reader.skipSeparators()
+		if reader.done() {
+			return args, nil
+		}
+		key := reader.readWord("=")
+		arg := &Arg{Key: key}
+		if reader.peek() == '=' {
+			reader.pos++
+			if err := reader.readValue(arg); err != nil {
+				return nil, erero.WithMessagef(err, "wrong value of arg %q", key)
+			}
+		} else {
+			if key == "" {
+				return nil, erero.Errorf("wrong char %q at %d in %q", reader.peek(), reader.pos, content)
+			}
+			arg.IsFlag = true
+			arg.Value = "true"
+			arg.Values = []string{"true"}
+		}
+		args = append(args, arg)
+	}
+}
+
+// Lookup returns the argument with the key.
+// Lookup 返回具有指定键的参数。
+func (args Args) Lookup(key string) (*Arg, bool) {
+	for _, arg := range args {
+		if arg.Key == key {
+			return arg, true
+		}
+	}
+	return nil, false
+}
+
+// Has returns whether the argument with the key exists.
+// Has 返回是否存在具有指定键的参数。
+func (args Args) Has(key string) bool {
+	_, ok := args.Lookup(key)
+	return ok
+}
+
+// Keys returns the keys of the arguments.
+// Keys 返回参数的键。
+func (args Args) Keys() []string {
+	var keys []string
+	for _, arg := range args {
+		keys = append(keys, arg.Key)
+	}
+	return keys
+}
+
+// GetString returns the value of the argument, or the default value when it does not exist.
+// GetString 返回参数的值，当参数不存在时返回默认值。
+func (args Args) GetString(key string, defaultValue string) string {
+	if arg, ok := args.Lookup(key); ok {
+		return arg.Value
+	}
+	return defaultValue
+}
+
+// GetBool returns the bool value of the argument, a flag is true, a missing argument is false.
+// GetBool 返回参数的布尔值，标志为 true，不存在的参数为 false。
+func (args Args) GetBool(key string) (bool, error) {
+	arg, ok := args.Lookup(key)
+	if !ok {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(arg.Value)
+	if err != nil {
+		return false, erero.Wro(err)
+	}
+	return value, nil
+}
+
+// GetInt returns the int value of the argument, or the default value when it does not exist.
+// GetInt 返回参数的整数值，当参数不存在时返回默认值。
+func (args Args) GetInt(key string, defaultValue int) (int, error) {
+	arg, ok := args.Lookup(key)
+	if !ok {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(arg.Value)
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	return value, nil
+}
+
+// GetList returns the items of the argument, a single value is a list with one item.
+// GetList 返回参数的条目，单个值是只有一个条目的列表。
+func (args Args) GetList(key string) []string {
+	if arg, ok := args.Lookup(key); ok {
+		return arg.Values
+	}
+	return nil
+}
+
+// argsReader reads the arguments char by char.
+// argsReader 逐个字符读取参数。
+type argsReader struct {
+	content string
+	pos     int
+}
+
+func (reader *argsReader) done() bool {
+	return reader.pos >= len(reader.content)
+}
+
+func (reader *argsReader) peek() byte {
+	if reader.done() {
+		return 0
+	}
+	return reader.content[reader.pos]
+}
+
+func (reader *argsReader) skipSeparators() {
+	for !reader.done() && strings.IndexByte(" \t,", reader.peek()) >= 0 {
+		reader.pos++
+	}
+}
+
+// readWord reads until a space, a comma, or one of the stop chars.
+// readWord 读取到空格、逗号或任一停止字符为止。
+func (reader *argsReader) readWord(stops string) string {
+	start := reader.pos
+	for !reader.done() && strings.IndexByte(" \t,"+stops, reader.peek()) < 0 {
+		reader.pos++
+	}
+	return reader.content[start:reader.pos]
+}
+
+// readQuoted reads a quoted string starting at the current position and returns the unquoted value.
+// readQuoted 读取从当前位置开始的带引号字符串，并返回去掉引号后的值。
+func (reader *argsReader) readQuoted() (string, error) {
+	quote := reader.peek()
+	start := reader.pos
+	reader.pos++
+	for !reader.done() {
+		c := reader.peek()
+		reader.pos++
+		if c == '\\' && quote == '"' {
+			reader.pos++
+			continue
+		}
+		if c == quote {
+			value, err := strconv.Unquote(reader.content[start:reader.pos])
+			if err != nil {
+				return "", erero.Wro(err)
+			}
+			return value, nil
+		}
+	}
+	return "", erero.Errorf("unterminated quoted string %s", reader.content[start:])
+}
+
+func (reader *argsReader) readItem(stops string) (string, error) {
+	if c := reader.peek(); c == '"' || c == '`' {
+		return reader.readQuoted()
+	}
+	return reader.readWord(stops), nil
+}
+
+func (reader *argsReader) readValue(arg *Arg) error {
+	switch reader.peek() {
+	case '{':
+		reader.pos++
+		arg.IsList = true
+		for {
+			reader.skipSeparators()
+			if reader.done() {
+				return erero.Errorf("unterminated list in %q", reader.content)
+			}
+			if reader.peek() == '}' {
+				reader.pos++
+				break
+			}
+			item, err := reader.readItem("}")
+			if err != nil {
+				return erero.Wro(err)
+			}
+			arg.Values = append(arg.Values, item)
+		}
+		arg.Value = strings.Join(arg.Values, ",")
+	case '"', '`':
+		value, err := reader.readQuoted()
+		if err != nil {
+			return erero.Wro(err)
+		}
+		arg.Value = value
+		arg.Values = []string{value}
+	default:
+		arg.Value = reader.readWord("")
+		if strings.Contains(arg.Value, ";") {
+			arg.IsList = true
+			arg.Values = strings.Split(arg.Value, ";")
+			arg.Value = strings.Join(arg.Values, ",")
+		} else {
+			arg.Values = []string{arg.Value}
+		}
+	}
+	return nil
+}
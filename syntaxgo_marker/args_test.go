@@ -0,0 +1,40 @@
+package syntaxgo_marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+func TestParseArgs(t *testing.T) {
+	args := rese.V1(ParseArgs(`table=users, soft-delete title="User \"Table\"" raw=` + "`a b`" + ` columns={id, "full name"} tags=a;b;c`))
+	require.Equal(t, []string{"table", "soft-delete", "title", "raw", "columns", "tags"}, args.Keys())
+
+	require.Equal(t, "users", args.GetString("table", ""))
+	require.True(t, args[1].IsFlag)
+	require.Equal(t, `User "Table"`, args.GetString("title", ""))
+	require.Equal(t, "a b", args.GetString("raw", ""))
+	require.True(t, args[4].IsList)
+	require.Equal(t, []string{"id", "full name"}, args.GetList("columns"))
+	require.Equal(t, []string{"a", "b", "c"}, args.GetList("tags"))
+	require.Equal(t, "a,b,c", args.GetString("tags", ""))
+	require.Equal(t, "none", args.GetString("missing", "none"))
+}
+
+func TestParseArgs_Wrong(t *testing.T) {
+	_, err := ParseArgs(`title="users`)
+	require.Error(t, err)
+	_, err = ParseArgs(`columns={id,name`)
+	require.Error(t, err)
+	_, err = ParseArgs(`=`)
+	require.NoError(t, err)
+}
+
+func TestArgs_GetInt(t *testing.T) {
+	args := rese.V1(ParseArgs(`size=10 name=abc`))
+	require.Equal(t, 10, rese.V1(args.GetInt("size", 0)))
+	require.Equal(t, 5, rese.V1(args.GetInt("missing", 5)))
+	_, err := args.GetInt("name", 0)
+	require.Error(t, err)
+}
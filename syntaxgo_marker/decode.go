@@ -0,0 +1,131 @@
+package syntaxgo_marker
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// Decode decodes the arguments into the struct pointed to by target.
+// The argument key of a field comes from the `marker` tag, like `marker:"table,required"`, or else the field name matched case-insensitively.
+// The anonymous argument, like "10" in "+gen:maximum=10", goes to the field tagged `marker:",value"`.
+// Fields tagged `marker:"-"` are skipped, and an argument without a matching field is an error.
+// Supported field kinds are string, bool, ints, uints, floats and []string.
+// Decode 将参数解码到 target 指向的结构体中。
+// 字段对应的参数键来自 `marker` 标签，比如 `marker:"table,required"`，否则使用不区分大小写匹配的字段名称。
+// 匿名参数（比如 "+gen:maximum=10" 中的 "10"）对应带有 `marker:",value"` 标签的字段。
+// 带有 `marker:"-"` 标签的字段会被跳过，没有匹配字段的参数会报错。
+// 支持的字段类型有 string、bool、整数、无符号整数、浮点数以及 []string。
+func (args Args) Decode(target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return erero.Errorf("target must be a pointer to a struct, but it is %T", target)
+	}
+	structValue := value.Elem()
+	structType := structValue.Type()
+
+	used := map[*Arg]bool{}
+	for idx := 0; idx < structType.NumField(); idx++ {
+		field := structType.Field(idx)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("marker"), ",")
+		if name == "-" {
+			continue
+		}
+		optionSet := map[string]bool{}
+		for _, option := range strings.Split(options, ",") {
+			optionSet[option] = true
+		}
+		var arg *Arg
+		var ok bool
+		if optionSet["value"] {
+			arg, ok = args.Lookup("")
+		} else {
+			arg, ok = args.lookupField(name, field.Name)
+		}
+		if !ok {
+			if optionSet["required"] {
+				return erero.Errorf("missing required arg of field %s", field.Name)
+			}
+			continue
+		}
+		used[arg] = true
+		if err := setFieldValue(structValue.Field(idx), arg); err != nil {
+			return erero.WithMessagef(err, "wrong value of field %s", field.Name)
+		}
+	}
+	for _, arg := range args {
+		if !used[arg] {
+			return erero.Errorf("unknown arg %q for %s", arg.Key, structType.Name())
+		}
+	}
+	return nil
+}
+
+// Decode decodes the arguments of the marker into the struct pointed to by target, see Args.Decode.
+// Decode 将标记的参数解码到 target 指向的结构体中，参见 Args.Decode。
+func (marker *Marker) Decode(target any) error {
+	if err := marker.Args.Decode(target); err != nil {
+		return erero.WithMessagef(err, "wrong marker %s at %s", marker.Name, marker.Position)
+	}
+	return nil
+}
+
+func (args Args) lookupField(tagName string, fieldName string) (*Arg, bool) {
+	if tagName != "" {
+		return args.Lookup(tagName)
+	}
+	for _, arg := range args {
+		if strings.EqualFold(arg.Key, fieldName) {
+			return arg, true
+		}
+	}
+	return nil, false
+}
+
+func setFieldValue(fieldValue reflect.Value, arg *Arg) error {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(arg.Value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(arg.Value)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		fieldValue.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(arg.Value, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return erero.Wro(err)
+		}
+		fieldValue.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(arg.Value, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return erero.Wro(err)
+		}
+		fieldValue.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(arg.Value, fieldValue.Type().Bits())
+		if err != nil {
+			return erero.Wro(err)
+		}
+		fieldValue.SetFloat(value)
+	case reflect.Slice:
+		if fieldValue.Type().Elem().Kind() != reflect.String {
+			return erero.Errorf("unsupported slice type %s", fieldValue.Type())
+		}
+		values := reflect.MakeSlice(fieldValue.Type(), len(arg.Values), len(arg.Values))
+		for idx, item := range arg.Values {
+			values.Index(idx).SetString(item)
+		}
+		fieldValue.Set(values)
+	default:
+		return erero.Errorf("unsupported field type %s", fieldValue.Type())
+	}
+	return nil
+}
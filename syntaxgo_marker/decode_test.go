@@ -0,0 +1,50 @@
+package syntaxgo_marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+type repoOptions struct {
+	Table      string   `marker:"table,required"`
+	SoftDelete bool     `marker:"soft-delete"`
+	Columns    []string `marker:"columns"`
+	Limit      int
+	Ratio      float64
+	internal   string
+}
+
+func TestArgs_Decode(t *testing.T) {
+	args := rese.V1(ParseArgs(`table=users soft-delete columns={id,name} limit=20 ratio=0.5`))
+
+	var options repoOptions
+	require.NoError(t, args.Decode(&options))
+	require.Equal(t, "users", options.Table)
+	require.True(t, options.SoftDelete)
+	require.Equal(t, []string{"id", "name"}, options.Columns)
+	require.Equal(t, 20, options.Limit)
+	require.Equal(t, 0.5, options.Ratio)
+	require.Empty(t, options.internal)
+}
+
+func TestArgs_Decode_Wrong(t *testing.T) {
+	var options repoOptions
+	require.Error(t, rese.V1(ParseArgs(`soft-delete`)).Decode(&options))
+	require.Error(t, rese.V1(ParseArgs(`table=users unknown=1`)).Decode(&options))
+	require.Error(t, rese.V1(ParseArgs(`table=users limit=abc`)).Decode(&options))
+	require.Error(t, rese.V1(ParseArgs(`table=users`)).Decode(options))
+}
+
+func TestMarker_Decode(t *testing.T) {
+	marker, ok, err := NewScanner("+gen:").ParseComment("//+gen:maximum=10")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	var options struct {
+		Value int `marker:",value"`
+	}
+	require.NoError(t, marker.Decode(&options))
+	require.Equal(t, 10, options.Value)
+}
@@ -0,0 +1,283 @@
+package syntaxgo_marker
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+/*
+Package `syntaxgo_marker` finds marker comments on Go declarations, used to trigger code generation.
+
+A marker is a comment line starting with a configured prefix, followed by the marker name and its arguments:
+
+	//+gen:repo table=users soft-delete columns={id,name} title="User Table"
+
+Key features include:
+  - Scanning files or merged package bundles for markers on the package clause, types, functions, methods, values and struct fields.
+  - Parsing arguments as key=value pairs, flags, quoted strings and lists, like {a,b} or a;b.
+  - Typed getters of the arguments, and decoding the arguments into a struct with `marker` tags.
+*/
+
+/*
+Package `syntaxgo_marker` 查找 Go 声明上的标记注释，用于触发代码生成。
+
+标记是以配置的前缀开头的注释行，后面跟着标记名称及其参数：
+
+	//+gen:repo table=users soft-delete columns={id,name} title="User Table"
+
+主要功能包括：
+  - 在单个文件或合并后的整个包中，查找包声明、类型、函数、方法、变量常量以及结构体字段上的标记。
+  - 将参数解析为键值对、标志、带引号的字符串以及列表，比如 {a,b} 或 a;b。
+  - 提供参数的类型化读取方法，并支持将参数解码到带有 `marker` 标签的结构体中。
+*/
+
+// TargetKind is the kind of the declaration annotated by a marker.
+// TargetKind 是标记所注解声明的类型。
+type TargetKind string
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	TARGET_PACKAGE TargetKind = "PACKAGE" // Package clause / 包声明
+	TARGET_TYPE    TargetKind = "TYPE"    // Type declaration / 类型声明
+	TARGET_FUNC    TargetKind = "FUNC"    // Function declaration / 函数声明
+	TARGET_METHOD  TargetKind = "METHOD"  // Method declaration / 方法声明
+	TARGET_VALUE   TargetKind = "VALUE"   // Const or var declaration / 常量或变量声明
+	TARGET_FIELD   TargetKind = "FIELD"   // Struct field / 结构体字段
+)
+
+// Marker is a marker comment together with the declaration it annotates.
+// Marker 是标记注释及其所注解的声明。
+type Marker struct {
+	Prefix     string         // Matched prefix, like "+gen:" / 匹配到的前缀，比如 "+gen:"
+	Name       string         // Marker name, like "repo" / 标记名称，比如 "repo"
+	Args       Args           // Parsed arguments / 解析后的参数
+	Raw        string         // Raw comment text / 原始注释文本
+	Position   token.Position // Position of the comment / 注释的位置
+	Target     TargetKind     // Kind of the annotated declaration / 被注解声明的类型
+	TargetName string         // Name of the annotated declaration, like "User", "User.Save" or "User.Name" / 被注解声明的名称，比如 "User"、"User.Save" 或 "User.Name"
+	Node       ast.Node       // Annotated node, like *ast.TypeSpec, *ast.FuncDecl, *ast.ValueSpec, *ast.Field or *ast.File / 被注解的节点
+}
+
+// Markers is a list of Marker.
+// Markers 是 Marker 的列表。
+type Markers []*Marker
+
+// Filter returns the markers with the name.
+// Filter 返回具有指定名称的标记。
+func (markers Markers) Filter(name string) Markers {
+	var results Markers
+	for _, marker := range markers {
+		if marker.Name == name {
+			results = append(results, marker)
+		}
+	}
+	return results
+}
+
+// FilterTarget returns the markers annotating the kind of declarations.
+// FilterTarget 返回注解指定类型声明的标记。
+func (markers Markers) FilterTarget(target TargetKind) Markers {
+	var results Markers
+	for _, marker := range markers {
+		if marker.Target == target {
+			results = append(results, marker)
+		}
+	}
+	return results
+}
+
+// Scanner finds the markers with the configured prefixes.
+// Scanner 查找具有配置前缀的标记。
+type Scanner struct {
+	prefixes []string
+}
+
+// NewScanner creates a Scanner with the marker prefixes, like "+gen:".
+// NewScanner 使用标记前缀创建 Scanner，比如 "+gen:"。
+func NewScanner(prefixes ...string) *Scanner {
+	prefixes = append([]string{}, prefixes...)
+	// Longer prefixes are matched first, so "+gen:repo:" wins over "+gen:".
+	// 优先匹配更长的前缀，因此 "+gen:repo:" 优先于 "+gen:"。
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	return &Scanner{prefixes: prefixes}
+}
+
+// ScanBundle finds the markers in the AST bundle, the bundle can be a merged package bundle.
+// ScanBundle 在 AST 包中查找标记，该 AST 包可以是合并后的整个包。
+func (scanner *Scanner) ScanBundle(astBundle *syntaxgo_ast.AstBundle) (Markers, error) {
+	astFile, fset := astBundle.GetBundle()
+	return scanner.ScanFile(fset, astFile)
+}
+
+// ScanFile finds the markers in the file, in source order.
+// ScanFile 在文件中查找标记，按源码顺序排列。
+func (scanner *Scanner) ScanFile(fset *token.FileSet, astFile *ast.File) (Markers, error) {
+	var markers Markers
+	add := func(commentGroup *ast.CommentGroup, target TargetKind, targetName string, node ast.Node) error {
+		results, err := scanner.scanComments(fset, commentGroup)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		for _, marker := range results {
+			marker.Target = target
+			marker.TargetName = targetName
+			marker.Node = node
+		}
+		markers = append(markers, results...)
+		return nil
+	}
+
+	if err := add(astFile.Doc, TARGET_PACKAGE, astFile.Name.Name, astFile); err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, decl := range astFile.Decls {
+		switch item := decl.(type) {
+		case *ast.FuncDecl:
+			target, targetName := TARGET_FUNC, item.Name.Name
+			if receiverName, _, ok := syntaxgo_search.GetReceiverTypeName(item); ok {
+				target, targetName = TARGET_METHOD, receiverName+"."+item.Name.Name
+			}
+			if err := add(item.Doc, target, targetName, item); err != nil {
+				return nil, erero.Wro(err)
+			}
+		case *ast.GenDecl:
+			for _, spec := range item.Specs {
+				if err := scanner.scanSpec(item, spec, add); err != nil {
+					return nil, erero.Wro(err)
+				}
+			}
+		}
+	}
+	return markers, nil
+}
+
+func (scanner *Scanner) scanSpec(genDecl *ast.GenDecl, spec ast.Spec, add func(*ast.CommentGroup, TargetKind, string, ast.Node) error) error {
+	// A spec of an unparenthesized GenDecl uses the doc of the GenDecl, like "type A struct{}".
+	// 没有括号的 GenDecl 中的声明使用 GenDecl 的文档，比如 "type A struct{}"。
+	specDoc := func(doc *ast.CommentGroup) *ast.CommentGroup {
+		if doc == nil && genDecl.Lparen == token.NoPos {
+			return genDecl.Doc
+		}
+		return doc
+	}
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		if err := add(specDoc(spec.Doc), TARGET_TYPE, spec.Name.Name, spec); err != nil {
+			return erero.Wro(err)
+		}
+		if structType, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range structType.Fields.List {
+				if err := add(field.Doc, TARGET_FIELD, spec.Name.Name+"."+fieldName(field), field); err != nil {
+					return erero.Wro(err)
+				}
+			}
+		}
+	case *ast.ValueSpec:
+		var names []string
+		for _, ident := range spec.Names {
+			names = append(names, ident.Name)
+		}
+		if err := add(specDoc(spec.Doc), TARGET_VALUE, strings.Join(names, ","), spec); err != nil {
+			return erero.Wro(err)
+		}
+	}
+	return nil
+}
+
+// scanComments parses the marker lines in the comment group.
+// scanComments 解析注释组中的标记行。
+func (scanner *Scanner) scanComments(fset *token.FileSet, commentGroup *ast.CommentGroup) (Markers, error) {
+	if commentGroup == nil {
+		return nil, nil
+	}
+	var markers Markers
+	for _, comment := range commentGroup.List {
+		marker, ok, err := scanner.ParseComment(comment.Text)
+		if err != nil {
+			return nil, erero.WithMessagef(err, "wrong marker at %s", fset.Position(comment.Pos()))
+		}
+		if ok {
+			marker.Position = fset.Position(comment.Pos())
+			markers = append(markers, marker)
+		}
+	}
+	return markers, nil
+}
+
+// ParseComment parses a comment line like "//+gen:repo table=users", a single space after the slashes is allowed.
+// Returns false when the comment does not start with any of the prefixes.
+// ParseComment 解析形如 "//+gen:repo table=users" 的注释行，斜杠后允许有一个空格。
+// 当注释不以任何前缀开头时返回 false。
+func (scanner *Scanner) ParseComment(text string) (*Marker, bool, error) {
+	line, ok := strings.CutPrefix(text, "//")
+	if !ok {
+		return nil, false, nil
+	}
+	line = strings.TrimPrefix(line, " ")
+	for _, prefix := range scanner.prefixes {
+		content, ok := strings.CutPrefix(line, prefix)
+		if !ok {
+			continue
+		}
+		name, rest := cutMarkerName(content)
+		if name == "" {
+			return nil, false, erero.Errorf("no marker name in %q", text)
+		}
+		args, err := ParseArgs(rest)
+		if err != nil {
+			return nil, false, erero.Wro(err)
+		}
+		return &Marker{Prefix: prefix, Name: name, Args: args, Raw: text}, true, nil
+	}
+	return nil, false, nil
+}
+
+// cutMarkerName cuts the marker name, the name ends with a space, a comma or "=".
+// With "=" the rest is a single anonymous argument, like "+gen:maximum=10".
+// cutMarkerName 切出标记名称，名称以空格、逗号或 "=" 结尾。
+// 当以 "=" 结尾时，剩余部分是单个匿名参数，比如 "+gen:maximum=10"。
+func cutMarkerName(content string) (string, string) {
+	idx := strings.IndexAny(content, " \t,=")
+	if idx < 0 {
+		return content, ""
+	}
+	if content[idx] == '=' {
+		return content[:idx], content[idx:]
+	}
+	return content[:idx], content[idx+1:]
+}
+
+// fieldName returns the names of the field, or the type name of an embedded field.
+// fieldName 返回字段的名称，嵌入字段返回其类型名称。
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		var names []string
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	typeExpr := field.Type
+	if starExpr, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = starExpr.X
+	}
+	switch item := typeExpr.(type) {
+	case *ast.Ident:
+		return item.Name
+	case *ast.SelectorExpr:
+		return item.Sel.Name
+	case *ast.IndexExpr:
+		return fieldName(&ast.Field{Type: item.X})
+	case *ast.IndexListExpr:
+		return fieldName(&ast.Field{Type: item.X})
+	}
+	return ""
+}
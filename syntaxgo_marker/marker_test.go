@@ -0,0 +1,121 @@
+package syntaxgo_marker
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const markerSource = `// Package demo has markers.
+//+gen:module name=demo
+package demo
+
+// User is a user.
+//+gen:repo table=users soft-delete columns={id,name}
+// +gen:cache ttl=60
+type User struct {
+	//+gen:column name="user id" primary
+	ID   int
+	Name string
+	//+gen:column type=varchar;utf8
+	Email string
+}
+
+// Save saves the user.
+//+gen:trace
+func (u *User) Save() error { return nil }
+
+//+gen:handler path="/users"
+func ListUsers() {}
+
+const (
+	//+gen:enum
+	Admin = "admin"
+)
+
+// Unrelated comments are ignored.
+//+other:thing
+type Role string
+`
+
+func TestScanner_ScanBundle(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(markerSource)))
+
+	markers := rese.V1(NewScanner("+gen:").ScanBundle(astBundle))
+	var names []string
+	for _, marker := range markers {
+		names = append(names, string(marker.Target)+" "+marker.TargetName+" "+marker.Name)
+	}
+	require.Equal(t, []string{
+		"PACKAGE demo module",
+		"TYPE User repo",
+		"TYPE User cache",
+		"FIELD User.ID column",
+		"FIELD User.Email column",
+		"METHOD User.Save trace",
+		"FUNC ListUsers handler",
+		"VALUE Admin enum",
+	}, names)
+
+	repo := markers.Filter("repo")[0]
+	require.Equal(t, "users", repo.Args.GetString("table", ""))
+	require.True(t, rese.V1(repo.Args.GetBool("soft-delete")))
+	require.Equal(t, []string{"id", "name"}, repo.Args.GetList("columns"))
+	require.Equal(t, 6, repo.Position.Line)
+	typeSpec, ok := repo.Node.(*ast.TypeSpec)
+	require.True(t, ok)
+	require.Equal(t, "User", typeSpec.Name.Name)
+
+	columns := markers.FilterTarget(TARGET_FIELD)
+	require.Len(t, columns, 2)
+	require.Equal(t, "user id", columns[0].Args.GetString("name", ""))
+	require.True(t, columns[0].Args.Has("primary"))
+	require.Equal(t, []string{"varchar", "utf8"}, columns[1].Args.GetList("type"))
+
+	cache := markers.Filter("cache")[0]
+	require.Equal(t, 60, rese.V1(cache.Args.GetInt("ttl", 0)))
+}
+
+func TestScanner_Prefixes(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(markerSource)))
+
+	markers := rese.V1(NewScanner("+gen:", "+other:").ScanBundle(astBundle))
+	others := markers.Filter("thing")
+	require.Len(t, others, 1)
+	require.Equal(t, "+other:", others[0].Prefix)
+	require.Equal(t, "Role", others[0].TargetName)
+
+	require.Empty(t, rese.V1(NewScanner("+none:").ScanBundle(astBundle)))
+}
+
+func TestScanner_ScanBundle_WrongMarker(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(`package demo
+
+//+gen:repo table="users
+type User struct{}
+`)))
+
+	_, err := NewScanner("+gen:").ScanBundle(astBundle)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "3:1")
+}
+
+func TestScanner_ParseComment(t *testing.T) {
+	scanner := NewScanner("+kubebuilder:")
+
+	marker, ok, err := scanner.ParseComment("// +kubebuilder:validation:Maximum=10")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "validation:Maximum", marker.Name)
+	require.Equal(t, "10", marker.Args.GetString("", ""))
+
+	_, ok, err = scanner.ParseComment("// kubebuilder is not a marker")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = scanner.ParseComment("//+kubebuilder: table=users")
+	require.Error(t, err)
+}
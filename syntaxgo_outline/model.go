@@ -0,0 +1,150 @@
+package syntaxgo_outline
+
+import (
+	"encoding/json"
+
+	"github.com/yyle88/erero"
+)
+
+/*
+Package `syntaxgo_outline` exports the outline of a Go file or package as JSON, for tools not written in Go,
+like docs sites, LLM prompts and dashboards.
+
+The outline has the package name, imports, types with fields, tags and methods, functions with signatures,
+consts and vars, with doc comments and positions. The JSON follows a versioned schema named by SchemaVersion,
+fields are only added within a version, and the Go structs in this package are the model of the schema.
+*/
+
+/*
+Package `syntaxgo_outline` 将 Go 文件或包的大纲导出为 JSON，供不是用 Go 编写的工具使用，
+比如文档网站、LLM 提示词以及仪表盘。
+
+大纲包含包名、导入、带有字段、标签和方法的类型、带有签名的函数、常量以及变量，并附带文档注释和位置。
+JSON 遵循以 SchemaVersion 命名的版本化结构，同一版本内只会新增字段，本包中的 Go 结构体就是该结构的模型。
+*/
+
+// SchemaVersion is the version of the JSON schema, it changes when a field is renamed, removed or changes meaning.
+// SchemaVersion 是 JSON 结构的版本，当字段被重命名、删除或含义改变时会变更。
+const SchemaVersion = "syntaxgo.outline/v1"
+
+// Outline is the outline of a Go file or package.
+// Outline 是 Go 文件或包的大纲。
+type Outline struct {
+	Schema  string    `json:"schema"`        // Always SchemaVersion / 总是 SchemaVersion
+	Package string    `json:"package"`       // Package name / 包名
+	Doc     string    `json:"doc,omitempty"` // Package doc comment / 包的文档注释
+	Files   []string  `json:"files"`         // File names, empty names for sources not read from files / 文件名称，不是从文件读取的源代码名称为空
+	Imports []*Import `json:"imports"`       // Imports, the same import in several files is listed once / 导入，多个文件中相同的导入只列出一次
+	Types   []*Type   `json:"types"`         // Types with their methods / 类型及其方法
+	Funcs   []*Func   `json:"funcs"`         // Functions, and methods of types not in the outline / 函数，以及不在大纲中的类型的方法
+	Consts  []*Value  `json:"consts"`        // Consts / 常量
+	Vars    []*Value  `json:"vars"`          // Vars / 变量
+}
+
+// Position is the position of a declaration, the line and column count from 1.
+// Position 是声明的位置，行号和列号从 1 开始。
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// Import is an import of the file or package.
+// Import 是文件或包的一个导入。
+type Import struct {
+	Name     string    `json:"name,omitempty"` // Explicit name like "_", "." or an alias / 显式名称，比如 "_"、"." 或者别名
+	Path     string    `json:"path"`
+	Position *Position `json:"position"`
+}
+
+// Type is a type declaration.
+// Type 是一个类型声明。
+type Type struct {
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`                  // struct, interface, func, map, slice, array, chan, pointer or name / 底层类型的种类
+	Alias      bool      `json:"alias,omitempty"`       // Declared like "type A = B" / 以 "type A = B" 形式声明
+	Type       string    `json:"type"`                  // Type expression, with "struct{...}" and "interface{...}" elided / 类型表达式，省略结构体和接口的内容
+	TypeParams []*Param  `json:"type_params,omitempty"` // Type parameters / 类型参数
+	Exported   bool      `json:"exported"`
+	Doc        string    `json:"doc,omitempty"`
+	Fields     []*Field  `json:"fields,omitempty"`  // Fields of a struct / 结构体的字段
+	Embeds     []string  `json:"embeds,omitempty"`  // Embedded elements of an interface / 接口中嵌入的元素
+	Methods    []*Func   `json:"methods,omitempty"` // Methods of an interface, or methods declared on the type / 接口的方法，或者在该类型上声明的方法
+	Position   *Position `json:"position"`
+}
+
+// Field is a struct field, a field declaring several names is listed once for each name.
+// Field 是结构体字段，声明多个名称的字段会为每个名称各列出一次。
+type Field struct {
+	Name     string    `json:"name"` // Type name for an embedded field / 嵌入字段为类型名称
+	Type     string    `json:"type"`
+	Embedded bool      `json:"embedded,omitempty"`
+	Exported bool      `json:"exported"`
+	Tag      string    `json:"tag,omitempty"`  // Tag without the quotes / 不含引号的标签
+	Tags     []*Tag    `json:"tags,omitempty"` // Tag parsed as key:"value" pairs in order / 按顺序解析为 key:"value" 对的标签
+	Doc      string    `json:"doc,omitempty"`
+	Comment  string    `json:"comment,omitempty"` // Line comment / 行尾注释
+	Position *Position `json:"position"`
+}
+
+// Tag is a key:"value" pair of a struct tag.
+// Tag 是结构体标签中的一个 key:"value" 对。
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Func is a function, a method, or a method of an interface.
+// Func 是函数、方法或者接口的方法。
+type Func struct {
+	Name       string    `json:"name"`
+	Receiver   string    `json:"receiver,omitempty"` // Receiver type like "*Account", empty for functions / 接收者类型，比如 "*Account"，函数为空
+	Signature  string    `json:"signature"`          // Declaration without the body, like "func (a *Account) Save() error" / 不含函数体的声明
+	TypeParams []*Param  `json:"type_params,omitempty"`
+	Params     []*Param  `json:"params"`
+	Results    []*Param  `json:"results"`
+	Exported   bool      `json:"exported"`
+	Doc        string    `json:"doc,omitempty"`
+	Position   *Position `json:"position"`
+}
+
+// Param is a parameter, a result or a type parameter, a field declaring several names is listed once for each name.
+// Param 是参数、返回值或者类型参数，声明多个名称的字段会为每个名称各列出一次。
+type Param struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"` // Like "...int" for variadic parameters / 可变参数形如 "...int"
+}
+
+// Value is a const or a var, a spec declaring several names is listed once for each name.
+// Value 是常量或变量，声明多个名称的 spec 会为每个名称各列出一次。
+type Value struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type,omitempty"`  // Declared type / 声明的类型
+	Value    string    `json:"value,omitempty"` // Value expression, empty for implicit values like after iota / 值表达式，隐式值（比如 iota 之后）为空
+	Exported bool      `json:"exported"`
+	Doc      string    `json:"doc,omitempty"`
+	Position *Position `json:"position"`
+}
+
+// JSON encodes the outline as indented JSON.
+// JSON 将大纲编码为带缩进的 JSON。
+func (outline *Outline) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(outline, "", "  ")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return data, nil
+}
+
+// ParseJSON decodes the outline from JSON, the schema must be SchemaVersion.
+// ParseJSON 从 JSON 解码大纲，结构版本必须是 SchemaVersion。
+func ParseJSON(data []byte) (*Outline, error) {
+	var outline Outline
+	if err := json.Unmarshal(data, &outline); err != nil {
+		return nil, erero.Wro(err)
+	}
+	if outline.Schema != SchemaVersion {
+		return nil, erero.Errorf("outline schema %q is not %q", outline.Schema, SchemaVersion)
+	}
+	return &outline, nil
+}
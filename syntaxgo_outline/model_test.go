@@ -0,0 +1,48 @@
+package syntaxgo_outline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+func writeFile(t *testing.T, root string, name string, content string) string {
+	path := filepath.Join(root, name)
+	done.Done(os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestOutline_JSON(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(demoSource)))
+	outline := rese.P1(NewFileOutline(astBundle))
+
+	data := rese.V1(outline.JSON())
+	require.True(t, strings.HasPrefix(string(data), "{\n  \"schema\": \"syntaxgo.outline/v1\",\n  \"package\": \"demo\",\n"))
+	require.Contains(t, string(data), `"signature": "func (a *Account) Save(prefix string, values ...int) (n int, err error)"`)
+
+	decoded := rese.P1(ParseJSON(data))
+	require.Equal(t, outline, decoded)
+}
+
+func TestOutline_JSON_EmptyLists(t *testing.T) {
+	outline := rese.P1(NewFileOutline(rese.P1(syntaxgo_ast.NewAstBundleV1([]byte("package demo\n")))))
+
+	data := rese.V1(outline.JSON())
+	require.Contains(t, string(data), `"imports": []`)
+	require.Contains(t, string(data), `"funcs": []`)
+	require.NotContains(t, string(data), "null")
+}
+
+func TestParseJSON_Schema(t *testing.T) {
+	_, err := ParseJSON([]byte(`{"schema": "syntaxgo.outline/v0", "package": "demo"}`))
+	require.Error(t, err)
+
+	_, err = ParseJSON([]byte(`{`))
+	require.Error(t, err)
+}
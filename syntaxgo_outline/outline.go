@@ -0,0 +1,314 @@
+package syntaxgo_outline
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+// NewFileOutline builds the outline of a file.
+// NewFileOutline 构建一个文件的大纲。
+func NewFileOutline(astBundle *syntaxgo_ast.AstBundle) (*Outline, error) {
+	return NewPackageOutline([]*syntaxgo_ast.AstBundle{astBundle})
+}
+
+// NewPackageOutline builds the outline of a package from its files, the files must have the same package name.
+// Methods are listed with their types, even when declared in another file.
+// NewPackageOutline 根据包的文件构建该包的大纲，这些文件必须有相同的包名。
+// 方法与其类型一起列出，即使声明在另一个文件中。
+func NewPackageOutline(astBundles []*syntaxgo_ast.AstBundle) (*Outline, error) {
+	if len(astBundles) == 0 {
+		return nil, erero.New("no files to outline")
+	}
+	outline := &Outline{
+		Schema:  SchemaVersion,
+		Files:   []string{},
+		Imports: []*Import{},
+		Types:   []*Type{},
+		Funcs:   []*Func{},
+		Consts:  []*Value{},
+		Vars:    []*Value{},
+	}
+	builder := &outlineBuilder{outline: outline, imported: map[string]bool{}, typeMap: map[string]*Type{}}
+	for _, astBundle := range astBundles {
+		astFile, fset := astBundle.GetBundle()
+		// The file name of the positions also names the files loaded from an fs.FS or an overlay.
+		// 位置中的文件名同样可以命名从 fs.FS 或者覆盖层加载的文件。
+		fileName := fset.Position(astFile.Pos()).Filename
+		if outline.Package == "" {
+			outline.Package = astFile.Name.Name
+		} else if astFile.Name.Name != outline.Package {
+			return nil, erero.Errorf("package %s in %s is not %s", astFile.Name.Name, fileName, outline.Package)
+		}
+		outline.Files = append(outline.Files, fileName)
+		if astFile.Doc != nil && outline.Doc == "" {
+			outline.Doc = docText(astFile.Doc)
+		}
+		builder.fset = fset
+		builder.addFile(astFile)
+	}
+	// Methods come after all the types, since a method can be declared in a file before its type.
+	// 方法在所有类型之后处理，因为方法可以声明在其类型之前的文件中。
+	for _, method := range builder.methods {
+		typeName, _, _ := syntaxgo_search.GetReceiverTypeName(method.decl)
+		if typ, ok := builder.typeMap[typeName]; ok && typ.Kind != "interface" {
+			typ.Methods = append(typ.Methods, method.fn)
+		} else {
+			outline.Funcs = append(outline.Funcs, method.fn)
+		}
+	}
+	return outline, nil
+}
+
+// outlineBuilder collects the declarations of the files into the outline.
+// outlineBuilder 将文件中的声明收集到大纲中。
+type outlineBuilder struct {
+	outline  *Outline
+	fset     *token.FileSet
+	imported map[string]bool
+	typeMap  map[string]*Type
+	methods  []*methodItem
+}
+
+// methodItem is a method waiting for the type of its receiver.
+// methodItem 是一个等待其接收者类型的方法。
+type methodItem struct {
+	decl *ast.FuncDecl
+	fn   *Func
+}
+
+func (b *outlineBuilder) addFile(astFile *ast.File) {
+	for _, importSpec := range astFile.Imports {
+		item := &Import{Path: syntaxgo_search.GetImportPath(importSpec), Position: b.position(importSpec.Pos())}
+		if importSpec.Name != nil {
+			item.Name = importSpec.Name.Name
+		}
+		if key := item.Name + " " + item.Path; !b.imported[key] {
+			b.imported[key] = true
+			b.outline.Imports = append(b.outline.Imports, item)
+		}
+	}
+	for _, decl := range astFile.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			fn := b.newFunc(decl.Name, decl.Recv, decl.Type, decl.Doc, decl.Pos())
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				fn.Receiver = types.ExprString(decl.Recv.List[0].Type)
+				b.methods = append(b.methods, &methodItem{decl: decl, fn: fn})
+			} else {
+				b.outline.Funcs = append(b.outline.Funcs, fn)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					typ := b.newType(spec, specDoc(decl, spec.Doc))
+					b.typeMap[typ.Name] = typ
+					b.outline.Types = append(b.outline.Types, typ)
+				case *ast.ValueSpec:
+					values := b.newValues(spec, specDoc(decl, spec.Doc))
+					if decl.Tok == token.CONST {
+						b.outline.Consts = append(b.outline.Consts, values...)
+					} else {
+						b.outline.Vars = append(b.outline.Vars, values...)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (b *outlineBuilder) newType(spec *ast.TypeSpec, doc *ast.CommentGroup) *Type {
+	typ := &Type{
+		Name:       spec.Name.Name,
+		Kind:       typeKind(spec.Type),
+		Alias:      spec.Assign.IsValid(),
+		Type:       types.ExprString(spec.Type),
+		TypeParams: newParams(spec.TypeParams),
+		Exported:   spec.Name.IsExported(),
+		Doc:        docText(doc),
+		Position:   b.position(spec.Name.Pos()),
+	}
+	switch expr := spec.Type.(type) {
+	case *ast.StructType:
+		typ.Type = "struct{...}"
+		for _, field := range expr.Fields.List {
+			typ.Fields = append(typ.Fields, b.newFields(field)...)
+		}
+	case *ast.InterfaceType:
+		typ.Type = "interface{...}"
+		for _, field := range expr.Methods.List {
+			if funcType, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+				typ.Methods = append(typ.Methods, b.newFunc(field.Names[0], nil, funcType, field.Doc, field.Pos()))
+			} else {
+				typ.Embeds = append(typ.Embeds, types.ExprString(field.Type))
+			}
+		}
+	}
+	return typ
+}
+
+// newFields lists the field once for each name, an embedded field is named by its type name.
+// newFields 为字段的每个名称各列出一次，嵌入字段以其类型名称命名。
+func (b *outlineBuilder) newFields(field *ast.Field) []*Field {
+	template := Field{
+		Type:    types.ExprString(field.Type),
+		Doc:     docText(field.Doc),
+		Comment: docText(field.Comment),
+	}
+	if field.Tag != nil {
+		if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+			template.Tag = tag
+			for _, pair := range utils.ParseTagPairs(tag) {
+				template.Tags = append(template.Tags, &Tag{Key: pair.Key, Value: pair.Value})
+			}
+		}
+	}
+	if len(field.Names) == 0 {
+		item := template
+		item.Name = syntaxgo_ast.GetEmbeddedFieldName(field.Type)
+		item.Embedded = true
+		item.Exported = token.IsExported(item.Name)
+		item.Position = b.position(field.Type.Pos())
+		return []*Field{&item}
+	}
+	fields := make([]*Field, 0, len(field.Names))
+	for _, name := range field.Names {
+		item := template
+		item.Name = name.Name
+		item.Exported = name.IsExported()
+		item.Position = b.position(name.Pos())
+		fields = append(fields, &item)
+	}
+	return fields
+}
+
+func (b *outlineBuilder) newFunc(name *ast.Ident, recv *ast.FieldList, funcType *ast.FuncType, doc *ast.CommentGroup, pos token.Pos) *Func {
+	params := newParams(funcType.Params)
+	if params == nil {
+		params = []*Param{}
+	}
+	results := newParams(funcType.Results)
+	if results == nil {
+		results = []*Param{}
+	}
+	return &Func{
+		Name:       name.Name,
+		Signature:  signature(name, recv, funcType),
+		TypeParams: newParams(funcType.TypeParams),
+		Params:     params,
+		Results:    results,
+		Exported:   name.IsExported(),
+		Doc:        docText(doc),
+		Position:   b.position(pos),
+	}
+}
+
+// newValues lists the spec once for each name, the value is empty when the spec has fewer values than names.
+// newValues 为 spec 的每个名称各列出一次，当 spec 的值少于名称时值为空。
+func (b *outlineBuilder) newValues(spec *ast.ValueSpec, doc *ast.CommentGroup) []*Value {
+	values := make([]*Value, 0, len(spec.Names))
+	for idx, name := range spec.Names {
+		value := &Value{
+			Name:     name.Name,
+			Exported: name.IsExported(),
+			Doc:      docText(doc),
+			Position: b.position(name.Pos()),
+		}
+		if spec.Type != nil {
+			value.Type = types.ExprString(spec.Type)
+		}
+		if len(spec.Values) == len(spec.Names) {
+			value.Value = types.ExprString(spec.Values[idx])
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func (b *outlineBuilder) position(pos token.Pos) *Position {
+	position := b.fset.Position(pos)
+	return &Position{File: position.Filename, Line: position.Line, Column: position.Column}
+}
+
+// newParams lists the fields once for each name, it returns nil for a nil list.
+// newParams 为字段的每个名称各列出一次，列表为 nil 时返回 nil。
+func newParams(fieldList *ast.FieldList) []*Param {
+	if fieldList == nil {
+		return nil
+	}
+	params := []*Param{}
+	for _, field := range fieldList.List {
+		typeString := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, &Param{Type: typeString})
+		}
+		for _, name := range field.Names {
+			params = append(params, &Param{Name: name.Name, Type: typeString})
+		}
+	}
+	return params
+}
+
+// signature prints the declaration without the doc and the body.
+// signature 打印不含文档和函数体的声明。
+func signature(name *ast.Ident, recv *ast.FieldList, funcType *ast.FuncType) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), &ast.FuncDecl{Recv: recv, Name: name, Type: funcType}); err != nil {
+		return "func " + name.Name + strings.TrimPrefix(types.ExprString(funcType), "func")
+	}
+	return buf.String()
+}
+
+// typeKind returns the kind of the type expression, named and generic types are "name".
+// typeKind 返回类型表达式的种类，具名类型和泛型类型为 "name"。
+func typeKind(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.FuncType:
+		return "func"
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			return "slice"
+		}
+		return "array"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.StarExpr:
+		return "pointer"
+	case *ast.ParenExpr:
+		return typeKind(expr.X)
+	default:
+		return "name"
+	}
+}
+
+// specDoc returns the doc of the spec, or the doc of the declaration when it declares the spec alone.
+// specDoc 返回 spec 的文档，当声明只包含该 spec 时返回声明的文档。
+func specDoc(decl *ast.GenDecl, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc == nil && len(decl.Specs) == 1 {
+		return decl.Doc
+	}
+	return doc
+}
+
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
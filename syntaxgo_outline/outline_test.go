@@ -0,0 +1,164 @@
+package syntaxgo_outline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+const demoSource = `// Package demo is a demo.
+package demo
+
+import (
+	"fmt"
+	str "strings"
+)
+
+// Version is the version.
+const Version = "v1"
+
+const (
+	// Low is low.
+	Low Level = iota
+	High
+)
+
+var name, age = "demo", 18
+
+// Level is a level.
+type Level int
+
+// Account is an account.
+type Account struct {
+	Base
+	// ID is the id.
+	ID         int64  ` + "`json:\"id\" gorm:\"column:id;primaryKey\"`" + `
+	Name, Nick string // names
+}
+
+// Store saves accounts.
+type Store interface {
+	fmt.Stringer
+	// Save saves the account.
+	Save(account *Account) error
+}
+
+type List[T any] []T
+
+// Save saves the account.
+func (a *Account) Save(prefix string, values ...int) (n int, err error) {
+	return 0, nil
+}
+
+func (l List[T]) Len() int { return len(l) }
+
+func (s Other) Name() string { return str.ToUpper("x") }
+
+// NewAccount creates an account.
+func NewAccount[T any](value T) *Account {
+	return &Account{}
+}
+`
+
+func TestNewFileOutline(t *testing.T) {
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(demoSource)))
+
+	outline := rese.P1(NewFileOutline(astBundle))
+	require.Equal(t, SchemaVersion, outline.Schema)
+	require.Equal(t, "demo", outline.Package)
+	require.Equal(t, "Package demo is a demo.", outline.Doc)
+	require.Equal(t, []string{""}, outline.Files)
+
+	require.Len(t, outline.Imports, 2)
+	require.Equal(t, &Import{Path: "fmt", Position: &Position{Line: 5, Column: 2}}, outline.Imports[0])
+	require.Equal(t, "str", outline.Imports[1].Name)
+
+	require.Len(t, outline.Consts, 3)
+	require.Equal(t, &Value{Name: "Version", Value: `"v1"`, Exported: true, Doc: "Version is the version.", Position: &Position{Line: 10, Column: 7}}, outline.Consts[0])
+	require.Equal(t, "Level", outline.Consts[1].Type)
+	require.Equal(t, "iota", outline.Consts[1].Value)
+	require.Equal(t, "Low is low.", outline.Consts[1].Doc)
+	require.Equal(t, "", outline.Consts[2].Value)
+
+	require.Len(t, outline.Vars, 2)
+	require.Equal(t, "age", outline.Vars[1].Name)
+	require.Equal(t, "18", outline.Vars[1].Value)
+	require.False(t, outline.Vars[1].Exported)
+
+	require.Len(t, outline.Types, 4)
+	level := outline.Types[0]
+	require.Equal(t, "name", level.Kind)
+	require.Equal(t, "int", level.Type)
+
+	account := outline.Types[1]
+	require.Equal(t, "struct", account.Kind)
+	require.Equal(t, "struct{...}", account.Type)
+	require.Equal(t, "Account is an account.", account.Doc)
+	require.Len(t, account.Fields, 4)
+	require.Equal(t, "Base", account.Fields[0].Name)
+	require.True(t, account.Fields[0].Embedded)
+	require.Equal(t, "ID", account.Fields[1].Name)
+	require.Equal(t, "ID is the id.", account.Fields[1].Doc)
+	require.Equal(t, `json:"id" gorm:"column:id;primaryKey"`, account.Fields[1].Tag)
+	require.Equal(t, []*Tag{{Key: "json", Value: "id"}, {Key: "gorm", Value: "column:id;primaryKey"}}, account.Fields[1].Tags)
+	require.Equal(t, "Nick", account.Fields[3].Name)
+	require.Equal(t, "names", account.Fields[3].Comment)
+	require.Equal(t, &Position{Line: 28, Column: 8}, account.Fields[3].Position)
+
+	require.Len(t, account.Methods, 1)
+	save := account.Methods[0]
+	require.Equal(t, "*Account", save.Receiver)
+	require.Equal(t, "func (a *Account) Save(prefix string, values ...int) (n int, err error)", save.Signature)
+	require.Equal(t, []*Param{{Name: "prefix", Type: "string"}, {Name: "values", Type: "...int"}}, save.Params)
+	require.Equal(t, []*Param{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}}, save.Results)
+
+	store := outline.Types[2]
+	require.Equal(t, "interface", store.Kind)
+	require.Equal(t, []string{"fmt.Stringer"}, store.Embeds)
+	require.Len(t, store.Methods, 1)
+	require.Equal(t, "func Save(account *Account) error", store.Methods[0].Signature)
+	require.Equal(t, "Save saves the account.", store.Methods[0].Doc)
+
+	list := outline.Types[3]
+	require.Equal(t, "slice", list.Kind)
+	require.Equal(t, []*Param{{Name: "T", Type: "any"}}, list.TypeParams)
+	require.Len(t, list.Methods, 1)
+	require.Equal(t, "List[T]", list.Methods[0].Receiver)
+
+	require.Len(t, outline.Funcs, 2)
+	require.Equal(t, "NewAccount", outline.Funcs[0].Name)
+	require.Equal(t, "func NewAccount[T any](value T) *Account", outline.Funcs[0].Signature)
+	require.Equal(t, []*Param{{Name: "T", Type: "any"}}, outline.Funcs[0].TypeParams)
+	require.Equal(t, "Other", outline.Funcs[1].Receiver)
+}
+
+func TestNewPackageOutline(t *testing.T) {
+	root := t.TempDir()
+	path1 := writeFile(t, root, "a.go", "package demo\n\nfunc (a *A) Run() {}\n")
+	path2 := writeFile(t, root, "b.go", "package demo\n\nimport \"fmt\"\n\ntype A struct{}\n\nvar _ = fmt.Sprint\n")
+
+	outline := rese.P1(NewPackageOutline([]*syntaxgo_ast.AstBundle{
+		rese.P1(syntaxgo_ast.NewAstBundleV4(path1)),
+		rese.P1(syntaxgo_ast.NewAstBundleV4(path2)),
+	}))
+	require.Equal(t, []string{path1, path2}, outline.Files)
+	require.Len(t, outline.Types, 1)
+	require.Len(t, outline.Types[0].Methods, 1)
+	require.Equal(t, &Position{File: path1, Line: 3, Column: 1}, outline.Types[0].Methods[0].Position)
+	require.Equal(t, &Position{File: path2, Line: 5, Column: 6}, outline.Types[0].Position)
+	require.Empty(t, outline.Funcs)
+	require.Len(t, outline.Imports, 1)
+}
+
+func TestNewPackageOutline_MixedPackages(t *testing.T) {
+	_, err := NewPackageOutline([]*syntaxgo_ast.AstBundle{
+		rese.P1(syntaxgo_ast.NewAstBundleV1([]byte("package a\n"))),
+		rese.P1(syntaxgo_ast.NewAstBundleV1([]byte("package b\n"))),
+	})
+	require.Error(t, err)
+
+	_, err = NewPackageOutline(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package syntaxgo_reflect
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// GenerateZeroValueCode generates the zero-value expression of the type, as it would be used in another package.
+// It returns 0, "", false, nil for pointer/slice/map/chan/func/interface types, and T{} for struct and array types.
+//
+// For example, if the type is struct "Demo" from package "abc", this function will return "abc.Demo{}".
+//
+// GenerateZeroValueCode 生成类型的零值表达式，结果是在其他包中使用时的写法。
+// 对于指针/切片/映射/通道/函数/接口类型返回 nil，基础类型返回 0、""、false，结构体和数组类型返回 T{}。
+//
+// 举个例子，如果类型是来自包 "abc" 的结构体 "Demo"，这个函数将返回 "abc.Demo{}"。
+func GenerateZeroValueCode(a reflect.Type) string {
+	switch a.Kind() {
+	case reflect.Bool:
+		return "false"
+	case reflect.String:
+		return `""`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return "0"
+	case reflect.Struct, reflect.Array:
+		return generateTypeCode(a) + "{}"
+	default:
+		// Pointer, Slice, Map, Chan, Func, Interface and UnsafePointer.
+		// 指针、切片、映射、通道、函数、接口以及 unsafe.Pointer。
+		return "nil"
+	}
+}
+
+// GenerateZeroValueCodeV2 is the generic version of GenerateZeroValueCode.
+// GenerateZeroValueCodeV2 是 GenerateZeroValueCode 的泛型版本。
+func GenerateZeroValueCodeV2[T any]() string {
+	return GenerateZeroValueCode(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// generateTypeCode generates the type code as used in another package, supporting unnamed array types.
+// generateTypeCode 生成在其他包中使用时的类型代码，支持未命名的数组类型。
+func generateTypeCode(a reflect.Type) string {
+	if a.Name() != "" {
+		return GenerateTypeUsageCode(a)
+	}
+	if a.Kind() == reflect.Array {
+		return "[" + strconv.Itoa(a.Len()) + "]" + generateElemTypeCode(a.Elem())
+	}
+	return a.String() // Unnamed struct type / 未命名的结构体类型
+}
+
+// generateElemTypeCode generates the element type code of composite types.
+// generateElemTypeCode 生成复合类型中元素类型的代码。
+func generateElemTypeCode(a reflect.Type) string {
+	if a.Name() != "" {
+		return GenerateTypeUsageCode(a)
+	}
+	switch a.Kind() {
+	case reflect.Ptr:
+		return "*" + generateElemTypeCode(a.Elem())
+	case reflect.Slice:
+		return "[]" + generateElemTypeCode(a.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(a.Len()) + "]" + generateElemTypeCode(a.Elem())
+	case reflect.Map:
+		return "map[" + generateElemTypeCode(a.Key()) + "]" + generateElemTypeCode(a.Elem())
+	default:
+		return a.String()
+	}
+}
@@ -0,0 +1,29 @@
+package syntaxgo_reflect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type exampleStatus int
+
+func TestGenerateZeroValueCode(t *testing.T) {
+	require.Equal(t, "0", GenerateZeroValueCode(reflect.TypeOf(0)))
+	require.Equal(t, "0", GenerateZeroValueCode(reflect.TypeOf(exampleStatus(0))))
+	require.Equal(t, `""`, GenerateZeroValueCode(reflect.TypeOf("")))
+	require.Equal(t, "false", GenerateZeroValueCode(reflect.TypeOf(false)))
+	require.Equal(t, "nil", GenerateZeroValueCode(reflect.TypeOf(&Example{})))
+	require.Equal(t, "nil", GenerateZeroValueCode(reflect.TypeOf([]int{})))
+	require.Equal(t, "nil", GenerateZeroValueCode(reflect.TypeOf(map[string]int{})))
+	require.Equal(t, "syntaxgo_reflect.Example{}", GenerateZeroValueCode(reflect.TypeOf(Example{})))
+	require.Equal(t, "[2]*syntaxgo_reflect.Example{}", GenerateZeroValueCode(reflect.TypeOf([2]*Example{})))
+}
+
+func TestGenerateZeroValueCodeV2(t *testing.T) {
+	require.Equal(t, "syntaxgo_reflect.Example{}", GenerateZeroValueCodeV2[Example]())
+	require.Equal(t, "nil", GenerateZeroValueCodeV2[*Example]())
+	require.Equal(t, "nil", GenerateZeroValueCodeV2[error]())
+	require.Equal(t, "nil", GenerateZeroValueCodeV2[ExampleInterface]())
+}
@@ -0,0 +1,126 @@
+package syntaxgo_rewrite
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_diff"
+)
+
+// DirOptions configures rewriting the files in a directory.
+// DirOptions 配置重写目录中的文件。
+type DirOptions struct {
+	dryRun    bool // Compute the changes without writing / 只计算变更而不写入
+	recursive bool // Include the subdirectories, skipping vendor, testdata, hidden ones and nested modules / 包含子目录，跳过 vendor、testdata、隐藏目录以及嵌套模块
+}
+
+// NewDirOptions creates DirOptions that writes the files in the directory only.
+// NewDirOptions 创建只写入该目录中文件的 DirOptions。
+func NewDirOptions() *DirOptions {
+	return &DirOptions{}
+}
+
+// SetDryRun sets whether to compute the changes without writing, the changes have diffs to review.
+// SetDryRun 设置是否只计算变更而不写入，变更带有差异以供审阅。
+func (options *DirOptions) SetDryRun(dryRun bool) *DirOptions {
+	options.dryRun = dryRun
+	return options
+}
+
+// SetRecursive sets whether to include the subdirectories, skipping the same directories as syntaxgo_batch:
+// vendor, testdata, the ones starting with "." or "_", and nested modules.
+// SetRecursive 设置是否包含子目录，跳过与 syntaxgo_batch 相同的目录：
+// vendor、testdata、以 "." 或 "_" 开头的目录，以及嵌套模块。
+func (options *DirOptions) SetRecursive(recursive bool) *DirOptions {
+	options.recursive = recursive
+	return options
+}
+
+// FileChange is the change of a file with rewritten calls.
+// FileChange 是有调用被重写的文件的变更。
+type FileChange struct {
+	Path      string // File path / 文件路径
+	Original  []byte // Source before the rewrite / 重写之前的源代码
+	Rewritten []byte // Source after the rewrite / 重写之后的源代码
+	Count     int    // Number of rewritten calls / 被重写的调用数量
+}
+
+// Diff returns the unified diff of the change.
+// Diff 返回该变更的统一差异格式文本。
+func (change *FileChange) Diff() string {
+	return syntaxgo_diff.Unified(change.Path, change.Original, change.Rewritten)
+}
+
+// RewriteDir rewrites the calls in the Go files of the directory as one transaction.
+// All files are rewritten in memory first, nothing is written when any file fails,
+// and the written files are restored when writing a file fails. The file modes are kept.
+// RewriteDir 以一个事务重写目录中 Go 文件里的调用。
+// 所有文件首先在内存中重写，任何文件失败时都不会写入，写入某个文件失败时会恢复已经写入的文件。文件权限保持不变。
+func RewriteDir(root string, options *DirOptions, rewrites ...*CallRewrite) ([]*FileChange, error) {
+	if options == nil {
+		options = NewDirOptions()
+	}
+	paths, err := options.listGoFiles(root)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var changes []*FileChange
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		newSource, count, err := RewriteSource(source, rewrites...)
+		if err != nil {
+			return nil, erero.WithMessagef(err, "rewrite %s", path)
+		}
+		if count == 0 || bytes.Equal(source, newSource) {
+			continue
+		}
+		changes = append(changes, &FileChange{Path: path, Original: source, Rewritten: newSource, Count: count})
+	}
+	if options.dryRun {
+		return changes, nil
+	}
+	if err := writeChanges(changes); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return changes, nil
+}
+
+// listGoFiles lists the Go files in the directory in sorted order.
+// listGoFiles 按排序后的顺序列出目录中的 Go 文件。
+func (options *DirOptions) listGoFiles(root string) ([]string, error) {
+	paths, err := utils.ListGoFiles(root, options.recursive)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return paths, nil
+}
+
+// writeChanges writes the rewritten sources, the written files are restored when a write fails.
+// writeChanges 写入重写后的源代码，写入失败时恢复已经写入的文件。
+func writeChanges(changes []*FileChange) error {
+	for idx, change := range changes {
+		if err := writeKeepMode(change.Path, change.Rewritten); err != nil {
+			for _, written := range changes[:idx] {
+				_ = writeKeepMode(written.Path, written.Original)
+			}
+			return erero.WithMessagef(err, "write %s", change.Path)
+		}
+	}
+	return nil
+}
+
+func writeKeepMode(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return erero.Wro(err)
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return erero.Wro(err)
+	}
+	return nil
+}
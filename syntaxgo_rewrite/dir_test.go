@@ -0,0 +1,54 @@
+package syntaxgo_rewrite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/done"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+func TestRewriteDir(t *testing.T) {
+	root := t.TempDir()
+	done.Done(os.WriteFile(filepath.Join(root, "a.go"), []byte(rewriteSource), 0640))
+	done.Done(os.WriteFile(filepath.Join(root, "b.go"), []byte("package demo\n\nfunc B() {}\n"), 0644))
+	done.Done(os.MkdirAll(filepath.Join(root, "sub"), 0755))
+	done.Done(os.WriteFile(filepath.Join(root, "sub", "c.go"), []byte(rewriteSource), 0644))
+	done.Done(os.MkdirAll(filepath.Join(root, "vendor"), 0755))
+	done.Done(os.WriteFile(filepath.Join(root, "vendor", "d.go"), []byte(rewriteSource), 0644))
+	done.Done(os.MkdirAll(filepath.Join(root, "nested"), 0755))
+	done.Done(os.WriteFile(filepath.Join(root, "nested", "go.mod"), []byte("module example.com/nested\n"), 0644))
+	done.Done(os.WriteFile(filepath.Join(root, "nested", "e.go"), []byte(rewriteSource), 0644))
+
+	rewrite := NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "newpkg.Bar(ctx, $2, $1)").
+		AddNamedImport("newpkg", "example.com/new/v2")
+
+	changes := rese.V1(RewriteDir(root, NewDirOptions().SetDryRun(true).SetRecursive(true), rewrite))
+	require.Len(t, changes, 2)
+	require.Equal(t, filepath.Join(root, "a.go"), changes[0].Path)
+	require.Equal(t, filepath.Join(root, "sub", "c.go"), changes[1].Path)
+	require.Equal(t, 2, changes[0].Count)
+	t.Log(changes[0].Diff())
+	require.Contains(t, changes[0].Diff(), "-\told.Foo(\"a\", old.Foo(\"b\", 1))\n+\tnewpkg.Bar(ctx, newpkg.Bar(ctx, 1, \"b\"), \"a\")\n")
+	require.Equal(t, rewriteSource, string(rese.V1(os.ReadFile(filepath.Join(root, "a.go")))))
+
+	changes = rese.V1(RewriteDir(root, NewDirOptions(), rewrite))
+	require.Len(t, changes, 1)
+	require.Equal(t, string(changes[0].Rewritten), string(rese.V1(os.ReadFile(filepath.Join(root, "a.go")))))
+	require.Equal(t, os.FileMode(0640), rese.V1(os.Stat(filepath.Join(root, "a.go"))).Mode().Perm())
+	require.Equal(t, rewriteSource, string(rese.V1(os.ReadFile(filepath.Join(root, "sub", "c.go")))))
+}
+
+func TestRewriteDir_Transaction(t *testing.T) {
+	root := t.TempDir()
+	done.Done(os.WriteFile(filepath.Join(root, "a.go"), []byte(rewriteSource), 0644))
+	done.Done(os.WriteFile(filepath.Join(root, "b.go"), []byte("package demo\n\nimport old \"example.com/old\"\n\nfunc B() { old.Foo() }\n"), 0644))
+
+	rewrite := NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "newpkg.Bar(ctx, $2, $1)")
+	_, err := RewriteDir(root, nil, rewrite)
+	require.Error(t, err)
+	require.Equal(t, rewriteSource, string(rese.V1(os.ReadFile(filepath.Join(root, "a.go")))))
+}
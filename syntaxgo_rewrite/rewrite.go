@@ -0,0 +1,267 @@
+package syntaxgo_rewrite
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/internal/utils"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+/*
+Package `syntaxgo_rewrite` rewrites the call sites found by syntaxgo_search with templates, used for API migrations.
+
+A template is the new call expression, with placeholders for the parts of the old call:
+
+	old.Foo(a, b)  =>  "newpkg.Bar(ctx, $2, $1)"  =>  newpkg.Bar(ctx, b, a)
+
+Key features include:
+  - Placeholders $1, $2 ... for the arguments, $* for all the arguments and $recv for the receiver of a method call.
+  - Adding the imports used by the template, and dropping the import of the old package when it is no longer used.
+  - Rewriting all files in a directory in one transaction, nothing is written when any file fails, with a dry-run diff mode.
+*/
+
+/*
+Package `syntaxgo_rewrite` 使用模板重写 syntaxgo_search 找到的调用位置，用于 API 迁移。
+
+模板是新的调用表达式，其中的占位符代表旧调用的各个部分：
+
+	old.Foo(a, b)  =>  "newpkg.Bar(ctx, $2, $1)"  =>  newpkg.Bar(ctx, b, a)
+
+主要功能包括：
+  - 占位符 $1、$2 ... 代表参数，$* 代表全部参数，$recv 代表方法调用的接收者。
+  - 添加模板使用的导入，并在旧包不再被使用时删除其导入。
+  - 在一个事务中重写目录中的所有文件，任何文件失败时都不会写入，并提供输出差异的试运行模式。
+*/
+
+// placeholderRegexp matches the placeholders of templates, like $1, $* and $recv.
+// placeholderRegexp 匹配模板中的占位符，比如 $1、$* 和 $recv。
+var placeholderRegexp = regexp.MustCompile(`\$(\d+|\*|recv)`)
+
+// CallRewrite rewrites the calls of the target with the template.
+// CallRewrite 使用模板重写目标函数的调用。
+type CallRewrite struct {
+	target   *syntaxgo_search.CallTarget
+	template string
+	imports  []*importItem // Imports used by the template / 模板使用的导入
+}
+
+// importItem is an import path with an optional name.
+// importItem 是带有可选名称的导入路径。
+type importItem struct {
+	name string
+	path string
+}
+
+// NewCallRewrite creates a rewrite of the calls of the target, like the template "newpkg.Bar(ctx, $2, $1)".
+// NewCallRewrite 创建目标函数调用的重写规则，比如模板 "newpkg.Bar(ctx, $2, $1)"。
+func NewCallRewrite(target *syntaxgo_search.CallTarget, template string) *CallRewrite {
+	return &CallRewrite{target: target, template: template}
+}
+
+// AddImport adds the import path used by the template, it is added to the files with rewritten calls.
+// AddImport 添加模板使用的导入路径，该路径会被添加到有调用被重写的文件中。
+func (rewrite *CallRewrite) AddImport(path string) *CallRewrite {
+	rewrite.imports = append(rewrite.imports, &importItem{path: path})
+	return rewrite
+}
+
+// AddNamedImport adds the named import used by the template, like ("newpkg", "example.com/new/v2").
+// AddNamedImport 添加模板使用的带名称导入，比如 ("newpkg", "example.com/new/v2")。
+func (rewrite *CallRewrite) AddNamedImport(name string, path string) *CallRewrite {
+	rewrite.imports = append(rewrite.imports, &importItem{name: name, path: path})
+	return rewrite
+}
+
+// RewriteSource rewrites the calls in the source code with the rewrites in order, and returns the number of rewritten calls.
+// The source is returned unchanged when there is no call to rewrite, otherwise the result is formatted.
+// RewriteSource 按顺序使用重写规则重写源代码中的调用，并返回被重写的调用数量。
+// 没有需要重写的调用时原样返回源代码，否则返回格式化后的结果。
+func RewriteSource(source []byte, rewrites ...*CallRewrite) ([]byte, int, error) {
+	total := 0
+	for _, rewrite := range rewrites {
+		newSource, count, err := rewrite.apply(source)
+		if err != nil {
+			return nil, 0, erero.Wro(err)
+		}
+		source = newSource
+		total += count
+	}
+	return source, total, nil
+}
+
+// apply rewrites the calls in the source code, then fixes the imports.
+// apply 重写源代码中的调用，然后修正导入。
+func (rewrite *CallRewrite) apply(source []byte) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, 0, erero.Wro(err)
+	}
+	callSites := syntaxgo_search.FindCallSites(fset, astFile, source, rewrite.target)
+	if len(callSites) == 0 {
+		return source, 0, nil
+	}
+	expander := &callExpander{
+		rewrite:   rewrite,
+		fset:      fset,
+		source:    source,
+		callSites: callSites,
+	}
+	sort.Slice(expander.callSites, func(i, j int) bool {
+		return expander.callSites[i].Call.Pos() < expander.callSites[j].Call.Pos()
+	})
+	code, err := expander.expandRange(0, len(source))
+	if err != nil {
+		return nil, 0, erero.Wro(err)
+	}
+	newSource, err := rewrite.fixImports([]byte(code))
+	if err != nil {
+		return nil, 0, erero.Wro(err)
+	}
+	return newSource, len(callSites), nil
+}
+
+// fixImports adds the imports used by the template, and drops the import of the old package when it is no longer used.
+// fixImports 添加模板使用的导入，并在旧包不再被使用时删除其导入。
+func (rewrite *CallRewrite) fixImports(source []byte) ([]byte, error) {
+	astBundle, err := syntaxgo_ast.NewAstBundleV1(source)
+	if err != nil {
+		return nil, erero.WithMessagef(err, "rewritten source does not parse")
+	}
+	for _, item := range rewrite.imports {
+		if item.name != "" {
+			astBundle.AddNamedImport(item.name, item.path)
+		} else {
+			astBundle.AddImport(item.path)
+		}
+	}
+	if oldPath := rewrite.target.ImportPath(); oldPath != "" {
+		astFile, _ := astBundle.GetBundle()
+		for _, importSpec := range astFile.Imports {
+			name := syntaxgo_search.GetImportName(importSpec)
+			if syntaxgo_search.GetImportPath(importSpec) != oldPath || name == "_" || utils.UsesQualifier(astFile, name) {
+				continue
+			}
+			if importSpec.Name != nil {
+				astBundle.DeleteNamedImport(importSpec.Name.Name, oldPath)
+			} else {
+				astBundle.DeleteImport(oldPath)
+			}
+		}
+	}
+	newSource, err := astBundle.FormatSource()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newSource, nil
+}
+
+// callExpander expands the templates of the call sites, the calls nested in the arguments are rewritten too.
+// callExpander 展开调用位置的模板，嵌套在参数中的调用也会被重写。
+type callExpander struct {
+	rewrite   *CallRewrite
+	fset      *token.FileSet
+	source    []byte
+	callSites []*syntaxgo_search.CallSite // Sorted by position / 按位置排序
+}
+
+// expandRange returns the source code between the offsets, with the outermost calls inside replaced by their templates.
+// expandRange 返回两个偏移量之间的源代码，其中最外层的调用被替换为模板展开的结果。
+func (x *callExpander) expandRange(start int, end int) (string, error) {
+	var builder strings.Builder
+	offset := start
+	for _, callSite := range x.callSites {
+		callStart, callEnd := x.offsetOf(callSite.Call.Pos()), x.offsetOf(callSite.Call.End())
+		if callStart < offset || callEnd > end {
+			continue
+		}
+		code, err := x.expand(callSite)
+		if err != nil {
+			return "", erero.Wro(err)
+		}
+		builder.Write(x.source[offset:callStart])
+		builder.WriteString(code)
+		offset = callEnd
+	}
+	builder.Write(x.source[offset:end])
+	return builder.String(), nil
+}
+
+// expand returns the template with the placeholders replaced by the parts of the call.
+// expand 返回将占位符替换为调用的各个部分之后的模板。
+func (x *callExpander) expand(callSite *syntaxgo_search.CallSite) (string, error) {
+	var args []string
+	for _, arg := range callSite.Call.Args {
+		code, err := x.expandRange(x.offsetOf(arg.Pos()), x.offsetOf(arg.End()))
+		if err != nil {
+			return "", erero.Wro(err)
+		}
+		args = append(args, code)
+	}
+	if callSite.Call.Ellipsis.IsValid() && len(args) > 0 {
+		args[len(args)-1] += "..."
+	}
+	var problem error
+	code := placeholderRegexp.ReplaceAllStringFunc(x.rewrite.template, func(placeholder string) string {
+		switch name := placeholder[1:]; name {
+		case "*":
+			return strings.Join(args, ", ")
+		case "recv":
+			recv, ok := receiverExpr(callSite.Call.Fun)
+			if !ok || !x.rewrite.target.IsMethod() {
+				problem = erero.Errorf("call at %s has no receiver for $recv", callSite.Position)
+				return placeholder
+			}
+			code, err := x.expandRange(x.offsetOf(recv.Pos()), x.offsetOf(recv.End()))
+			if err != nil {
+				problem = err
+			}
+			return code
+		default:
+			idx, _ := strconv.Atoi(name)
+			if idx < 1 || idx > len(args) {
+				problem = erero.Errorf("call at %s has %d arguments, no argument for %s", callSite.Position, len(args), placeholder)
+				return placeholder
+			}
+			return args[idx-1]
+		}
+	})
+	if problem != nil {
+		return "", erero.Wro(problem)
+	}
+	if _, err := parser.ParseExpr(code); err != nil {
+		return "", erero.WithMessagef(err, "rewritten call %q at %s is not an expression", code, callSite.Position)
+	}
+	return code, nil
+}
+
+func (x *callExpander) offsetOf(pos token.Pos) int {
+	return x.fset.Position(pos).Offset
+}
+
+// receiverExpr returns the receiver of the called method, like "a" of "a.Save" and "a.Save[T]".
+// receiverExpr 返回被调用方法的接收者，比如 "a.Save" 和 "a.Save[T]" 中的 "a"。
+func receiverExpr(fun ast.Expr) (ast.Expr, bool) {
+	for {
+		switch item := fun.(type) {
+		case *ast.ParenExpr:
+			fun = item.X
+		case *ast.IndexExpr:
+			fun = item.X
+		case *ast.IndexListExpr:
+			fun = item.X
+		case *ast.SelectorExpr:
+			return item.X, true
+		default:
+			return nil, false
+		}
+	}
+}
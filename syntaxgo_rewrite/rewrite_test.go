@@ -0,0 +1,67 @@
+package syntaxgo_rewrite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+)
+
+const rewriteSource = `package demo
+
+import (
+	"context"
+
+	old "example.com/old"
+)
+
+type Store struct{}
+
+func (s *Store) Save(key string, value int) error { return nil }
+
+func Run(ctx context.Context, store *Store) {
+	old.Foo("a", old.Foo("b", 1))
+	store.Save("k", 1)
+}
+`
+
+func TestRewriteSource(t *testing.T) {
+	rewrite := NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "newpkg.Bar(ctx, $2, $1)").
+		AddNamedImport("newpkg", "example.com/new/v2")
+
+	source, count := rese.V2(RewriteSource([]byte(rewriteSource), rewrite))
+	t.Log(string(source))
+	require.Equal(t, 2, count)
+	require.Contains(t, string(source), `newpkg.Bar(ctx, newpkg.Bar(ctx, 1, "b"), "a")`)
+	require.Contains(t, string(source), `newpkg "example.com/new/v2"`)
+	require.NotContains(t, string(source), "example.com/old")
+}
+
+func TestRewriteSource_Method(t *testing.T) {
+	rewrite := NewCallRewrite(syntaxgo_search.NewMethodTarget("Store", "Save"), "$recv.Put(ctx, $*)")
+
+	source, count := rese.V2(RewriteSource([]byte(rewriteSource), rewrite))
+	require.Equal(t, 1, count)
+	require.Contains(t, string(source), `store.Put(ctx, "k", 1)`)
+	require.Contains(t, string(source), `old "example.com/old"`)
+}
+
+func TestRewriteSource_NoMatch(t *testing.T) {
+	rewrite := NewCallRewrite(syntaxgo_search.NewFuncTarget("Missing"), "Other()")
+
+	source, count := rese.V2(RewriteSource([]byte(rewriteSource), rewrite))
+	require.Equal(t, 0, count)
+	require.Equal(t, rewriteSource, string(source))
+}
+
+func TestRewriteSource_BadTemplate(t *testing.T) {
+	_, _, err := RewriteSource([]byte(rewriteSource), NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "newpkg.Bar($3)"))
+	require.Error(t, err)
+
+	_, _, err = RewriteSource([]byte(rewriteSource), NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "$recv.Bar($1)"))
+	require.Error(t, err)
+
+	_, _, err = RewriteSource([]byte(rewriteSource), NewCallRewrite(syntaxgo_search.NewPackageFuncTarget("example.com/old", "Foo"), "newpkg.Bar($1"))
+	require.Error(t, err)
+}
@@ -0,0 +1,299 @@
+package syntaxgo_search
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// CallTarget is the function to find calls of, a local function, a method, or a package-qualified function.
+// CallTarget 是要查找其调用的函数，可以是本地函数、方法或者带包名限定的函数。
+type CallTarget struct {
+	name       string // Function or method name, or a selector path after the qualifier like "LOG.Panic" / 函数或方法名称，或者限定符之后的选择器路径，比如 "LOG.Panic"
+	receiver   string // Receiver type name of a method, empty matches any receiver / 方法的接收者类型名称，为空时匹配任意接收者
+	isMethod   bool   // Whether the target is a method / 目标是否为方法
+	importPath string // Import path of a package-qualified function, aliases are resolved / 带包名限定的函数的导入路径，会解析别名
+	qualifier  string // Qualifier matched as written, like "zaplog" / 按原样匹配的限定符，比如 "zaplog"
+}
+
+// NewFuncTarget creates a target of the function declared in the package, calls of same named locals are skipped.
+// NewFuncTarget 创建包内声明的函数的目标，同名局部变量的调用会被跳过。
+func NewFuncTarget(name string) *CallTarget {
+	return &CallTarget{name: name}
+}
+
+// NewMethodTarget creates a target of the method of the receiver type, an empty receiver matches any receiver.
+// The receiver type of a call is inferred from the declaration of the variable, calls on values of unknown types are marked Uncertain.
+// NewMethodTarget 创建接收者类型的方法的目标，接收者为空时匹配任意接收者。
+// 调用的接收者类型根据变量的声明推断，在未知类型的值上的调用会被标记为 Uncertain。
+func NewMethodTarget(receiver string, name string) *CallTarget {
+	return &CallTarget{name: name, receiver: receiver, isMethod: true}
+}
+
+// NewPackageFuncTarget creates a target of the function of the imported package, like ("github.com/yyle88/zaplog", "LOG.Panic").
+// The qualifier is resolved through the imports of each file, so aliased and dot imports are matched.
+// NewPackageFuncTarget 创建导入包中函数的目标，比如 ("github.com/yyle88/zaplog", "LOG.Panic")。
+// 限定符通过每个文件的导入进行解析，因此带别名的导入和点导入都能匹配。
+func NewPackageFuncTarget(importPath string, name string) *CallTarget {
+	return &CallTarget{name: name, importPath: importPath}
+}
+
+// NewQualifiedFuncTarget creates a target of the qualified function as written, like ("zaplog", "LOG.Panic"), import aliases are not resolved.
+// NewQualifiedFuncTarget 创建按原样书写的限定函数的目标，比如 ("zaplog", "LOG.Panic")，不解析导入别名。
+func NewQualifiedFuncTarget(qualifier string, name string) *CallTarget {
+	return &CallTarget{name: name, qualifier: qualifier}
+}
+
+// ImportPath returns the import path of a package-qualified function target, empty for other targets.
+// ImportPath 返回带包名限定的函数目标的导入路径，其它目标返回空。
+func (target *CallTarget) ImportPath() string {
+	return target.importPath
+}
+
+// IsMethod reports whether the target is a method.
+// IsMethod 判断目标是否为方法。
+func (target *CallTarget) IsMethod() bool {
+	return target.isMethod
+}
+
+// CallSite is a call of the target.
+// CallSite 是目标函数的一次调用。
+type CallSite struct {
+	Call       *ast.CallExpr  // Call expression / 调用表达式
+	Caller     *ast.FuncDecl  // Enclosing function, nil for calls at package level / 所在的函数，包级别的调用为 nil
+	CallerName string         // Name of the enclosing function, "Receiver.Method" for methods / 所在函数的名称，方法为 "Receiver.Method"
+	Args       []string       // Arguments as source text / 参数的源代码文本
+	Position   token.Position // Position of the call / 调用的位置
+	Uncertain  bool           // The receiver type of a method call can not be inferred / 无法推断方法调用的接收者类型
+}
+
+// FindCallSites finds the calls of the target in the file, in source order.
+// The source is used to take the arguments as written, when it is nil the arguments are printed from the AST.
+// FindCallSites 在文件中查找目标函数的调用，按源码顺序排列。
+// source 用于按原样获取参数文本，为 nil 时参数根据 AST 打印得到。
+func FindCallSites(fset *token.FileSet, astFile *ast.File, source []byte, target *CallTarget) []*CallSite {
+	importPaths := MapImportPathsByName(astFile)
+	dotImported := false
+	for _, importSpec := range astFile.Imports {
+		if importSpec.Name != nil && importSpec.Name.Name == "." && GetImportPath(importSpec) == target.importPath {
+			dotImported = true
+		}
+	}
+	var callSites []*CallSite
+	for _, decl := range astFile.Decls {
+		caller, _ := decl.(*ast.FuncDecl)
+		ast.Inspect(decl, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			matched, uncertain := target.match(call.Fun, importPaths, dotImported)
+			if !matched {
+				return true
+			}
+			callSite := &CallSite{
+				Call:      call,
+				Caller:    caller,
+				Position:  fset.Position(call.Pos()),
+				Uncertain: uncertain,
+			}
+			if caller != nil {
+				callSite.CallerName = callerName(caller)
+			}
+			for _, arg := range call.Args {
+				callSite.Args = append(callSite.Args, exprText(fset, source, arg))
+			}
+			callSites = append(callSites, callSite)
+			return true
+		})
+	}
+	return callSites
+}
+
+// FindCallSitesInPackage finds the calls of the target in the files of a package, the sources are in the same order as the files.
+// FindCallSitesInPackage 在一个包的文件中查找目标函数的调用，源代码与文件的顺序相同。
+func FindCallSitesInPackage(fset *token.FileSet, astFiles []*ast.File, sources [][]byte, target *CallTarget) []*CallSite {
+	var callSites []*CallSite
+	for idx, astFile := range astFiles {
+		var source []byte
+		if idx < len(sources) {
+			source = sources[idx]
+		}
+		callSites = append(callSites, FindCallSites(fset, astFile, source, target)...)
+	}
+	return callSites
+}
+
+// match reports whether the called expression is the target, and whether the receiver type is uncertain.
+// match 判断被调用的表达式是否为目标函数，以及接收者类型是否不确定。
+func (target *CallTarget) match(fun ast.Expr, importPaths map[string]string, dotImported bool) (bool, bool) {
+	fun = unwrapCallee(fun)
+	if target.isMethod {
+		selectorExpr, ok := fun.(*ast.SelectorExpr)
+		if !ok || selectorExpr.Sel.Name != target.name {
+			return false, false
+		}
+		if ident, ok := selectorExpr.X.(*ast.Ident); ok && ident.Obj == nil && importPaths[ident.Name] != "" {
+			return false, false
+		}
+		if target.receiver == "" {
+			return true, false
+		}
+		typeName, ok := inferTypeName(selectorExpr.X)
+		if !ok {
+			return true, true
+		}
+		return typeName == target.receiver, false
+	}
+	path, ok := selectorPath(fun)
+	// A head resolved in the file is a local, except the functions declared in the file.
+	// 在文件内解析到的首个标识符是局部变量，文件内声明的函数除外。
+	if !ok || (path[0].Obj != nil && (len(path) > 1 || path[0].Obj.Kind != ast.Fun)) {
+		return false, false
+	}
+	names := make([]string, 0, len(path))
+	for _, ident := range path {
+		names = append(names, ident.Name)
+	}
+	switch {
+	case target.importPath != "":
+		if dotImported && strings.Join(names, ".") == target.name {
+			return true, false
+		}
+		return len(names) > 1 && importPaths[names[0]] == target.importPath && strings.Join(names[1:], ".") == target.name, false
+	case target.qualifier != "":
+		return len(names) > 1 && names[0] == target.qualifier && strings.Join(names[1:], ".") == target.name, false
+	default:
+		return len(names) == 1 && names[0] == target.name, false
+	}
+}
+
+// unwrapCallee skips the parentheses and the type arguments of the called expression, like "(F)" and "F[int]".
+// unwrapCallee 跳过被调用表达式的括号和类型参数，比如 "(F)" 和 "F[int]"。
+func unwrapCallee(fun ast.Expr) ast.Expr {
+	for {
+		switch item := fun.(type) {
+		case *ast.ParenExpr:
+			fun = item.X
+		case *ast.IndexExpr:
+			fun = item.X
+		case *ast.IndexListExpr:
+			fun = item.X
+		default:
+			return fun
+		}
+	}
+}
+
+// selectorPath returns the identifiers of a selector chain like "zaplog.LOG.Panic".
+// selectorPath 返回选择器链中的标识符，比如 "zaplog.LOG.Panic"。
+func selectorPath(expr ast.Expr) ([]*ast.Ident, bool) {
+	switch item := expr.(type) {
+	case *ast.Ident:
+		return []*ast.Ident{item}, true
+	case *ast.SelectorExpr:
+		path, ok := selectorPath(item.X)
+		if !ok {
+			return nil, false
+		}
+		return append(path, item.Sel), true
+	}
+	return nil, false
+}
+
+// inferTypeName infers the type name of the expression from the declaration of the variable,
+// like parameters, receivers, typed vars and composite literals. Method expressions like "(*T).M" give the type T.
+// inferTypeName 根据变量的声明推断表达式的类型名称，
+// 比如参数、接收者、带类型的变量以及复合字面量。像 "(*T).M" 这样的方法表达式得到类型 T。
+func inferTypeName(expr ast.Expr) (string, bool) {
+	switch item := expr.(type) {
+	case *ast.ParenExpr:
+		return inferTypeName(item.X)
+	case *ast.StarExpr:
+		return inferTypeName(item.X)
+	case *ast.UnaryExpr:
+		if item.Op == token.AND {
+			return inferTypeName(item.X)
+		}
+	case *ast.CompositeLit:
+		return typeExprName(item.Type)
+	case *ast.Ident:
+		if item.Obj == nil {
+			return "", false
+		}
+		switch decl := item.Obj.Decl.(type) {
+		case *ast.TypeSpec:
+			return decl.Name.Name, true
+		case *ast.Field:
+			return typeExprName(decl.Type)
+		case *ast.ValueSpec:
+			if decl.Type != nil {
+				return typeExprName(decl.Type)
+			}
+			if idx := identIndex(decl.Names, item.Name); idx >= 0 && len(decl.Values) == len(decl.Names) {
+				return inferTypeName(decl.Values[idx])
+			}
+		case *ast.AssignStmt:
+			if decl.Tok != token.DEFINE || len(decl.Lhs) != len(decl.Rhs) {
+				return "", false
+			}
+			for idx, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == item.Name {
+					return inferTypeName(decl.Rhs[idx])
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// typeExprName returns the name of the local type expression, like "Account" from "*Account" or "Stack[T]".
+// typeExprName 返回本地类型表达式的名称，比如从 "*Account" 或 "Stack[T]" 得到 "Account"。
+func typeExprName(typeExpr ast.Expr) (string, bool) {
+	switch item := typeExpr.(type) {
+	case *ast.Ident:
+		return item.Name, true
+	case *ast.StarExpr:
+		return typeExprName(item.X)
+	case *ast.ParenExpr:
+		return typeExprName(item.X)
+	case *ast.IndexExpr:
+		return typeExprName(item.X)
+	case *ast.IndexListExpr:
+		return typeExprName(item.X)
+	}
+	return "", false
+}
+
+func identIndex(idents []*ast.Ident, name string) int {
+	for idx, ident := range idents {
+		if ident.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// callerName returns the name of the function, "Receiver.Method" for methods.
+// callerName 返回函数的名称，方法返回 "Receiver.Method"。
+func callerName(funcDecl *ast.FuncDecl) string {
+	if typeName, _, ok := GetReceiverTypeName(funcDecl); ok {
+		return typeName + "." + funcDecl.Name.Name
+	}
+	return funcDecl.Name.Name
+}
+
+// exprText returns the source text of the expression, or prints it when the source is nil.
+// exprText 返回表达式的源代码文本，source 为 nil 时打印该表达式。
+func exprText(fset *token.FileSet, source []byte, expr ast.Expr) string {
+	if source != nil {
+		return string(source[fset.Position(expr.Pos()).Offset:fset.Position(expr.End()).Offset])
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,108 @@
+package syntaxgo_search
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const callSiteSource = `package demo
+
+import (
+	"fmt"
+
+	log "github.com/yyle88/zaplog"
+	. "strings"
+)
+
+var greeting = Greet("init")
+
+type Account struct{ Name string }
+
+func (a *Account) Save(force bool) error { return nil }
+
+func Greet(name string) string { return fmt.Sprintf("hello %s", name) }
+
+func Run(account *Account, other interface{ Save(bool) error }) {
+	log.LOG.Panic("boom", "x")
+	fmt.Println(Greet(account.Name), ToUpper("x"))
+	account.Save(true)
+	(&Account{}).Save(false)
+	other.Save(true)
+	Greet := func(s string) string { return s }
+	_ = Greet("local")
+}
+`
+
+func TestFindCallSites(t *testing.T) {
+	fset := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fset, "demo.go", callSiteSource, parser.ParseComments))
+
+	callSites := FindCallSites(fset, astFile, []byte(callSiteSource), NewFuncTarget("Greet"))
+	require.Len(t, callSites, 2)
+	require.Nil(t, callSites[0].Caller)
+	require.Equal(t, []string{`"init"`}, callSites[0].Args)
+	require.Equal(t, 10, callSites[0].Position.Line)
+	require.Equal(t, "Run", callSites[1].CallerName)
+	require.Equal(t, []string{"account.Name"}, callSites[1].Args)
+	require.Equal(t, 20, callSites[1].Position.Line)
+}
+
+func TestFindCallSites_Package(t *testing.T) {
+	fset := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fset, "demo.go", callSiteSource, parser.ParseComments))
+
+	callSites := FindCallSites(fset, astFile, []byte(callSiteSource), NewPackageFuncTarget("github.com/yyle88/zaplog", "LOG.Panic"))
+	require.Len(t, callSites, 1)
+	require.Equal(t, []string{`"boom"`, `"x"`}, callSites[0].Args)
+	require.Equal(t, 19, callSites[0].Position.Line)
+
+	require.Empty(t, FindCallSites(fset, astFile, nil, NewQualifiedFuncTarget("zaplog", "LOG.Panic")))
+	require.Len(t, FindCallSites(fset, astFile, nil, NewQualifiedFuncTarget("log", "LOG.Panic")), 1)
+
+	callSites = FindCallSites(fset, astFile, nil, NewPackageFuncTarget("strings", "ToUpper"))
+	require.Len(t, callSites, 1)
+	require.Equal(t, []string{`"x"`}, callSites[0].Args)
+
+	require.Len(t, FindCallSites(fset, astFile, nil, NewPackageFuncTarget("fmt", "Sprintf")), 1)
+
+	require.Equal(t, "fmt", NewPackageFuncTarget("fmt", "Sprintf").ImportPath())
+	require.False(t, NewPackageFuncTarget("fmt", "Sprintf").IsMethod())
+	require.True(t, NewMethodTarget("Account", "Save").IsMethod())
+}
+
+func TestFindCallSites_Method(t *testing.T) {
+	fset := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fset, "demo.go", callSiteSource, parser.ParseComments))
+
+	callSites := FindCallSites(fset, astFile, []byte(callSiteSource), NewMethodTarget("Account", "Save"))
+	require.Len(t, callSites, 3)
+	require.False(t, callSites[0].Uncertain)
+	require.Equal(t, []string{"true"}, callSites[0].Args)
+	require.False(t, callSites[1].Uncertain)
+	require.Equal(t, []string{"false"}, callSites[1].Args)
+	require.True(t, callSites[2].Uncertain)
+	require.Equal(t, 23, callSites[2].Position.Line)
+
+	callSites = FindCallSites(fset, astFile, nil, NewMethodTarget("Other", "Save"))
+	require.Len(t, callSites, 1)
+	require.True(t, callSites[0].Uncertain)
+	require.Len(t, FindCallSites(fset, astFile, nil, NewMethodTarget("", "Save")), 3)
+}
+
+func TestFindCallSitesInPackage(t *testing.T) {
+	fset := token.NewFileSet()
+	sourceA := "package demo\n\nfunc Hello() string { return \"hello\" }\n"
+	sourceB := "package demo\n\nfunc Greet() string { return Hello() + \" world\" }\n"
+	astFileA := rese.P1(parser.ParseFile(fset, "a.go", sourceA, 0))
+	astFileB := rese.P1(parser.ParseFile(fset, "b.go", sourceB, 0))
+
+	callSites := FindCallSitesInPackage(fset, []*ast.File{astFileA, astFileB}, [][]byte{[]byte(sourceA), []byte(sourceB)}, NewFuncTarget("Hello"))
+	require.Len(t, callSites, 1)
+	require.Equal(t, "b.go", callSites[0].Position.Filename)
+	require.Equal(t, "Greet", callSites[0].CallerName)
+}
@@ -0,0 +1,313 @@
+package syntaxgo_search
+
+import (
+	"go/ast"
+	"go/doc/comment"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/syntaxgo/internal/utils"
+)
+
+// Directive is a directive comment line, like "//go:generate stringer -type=Kind" or "//nolint:errcheck".
+// Directive 是指令注释行，比如 "//go:generate stringer -type=Kind" 或 "//nolint:errcheck"。
+type Directive struct {
+	Namespace string // Part before the colon, like "go" / 冒号前的部分，比如 "go"
+	Name      string // First word after the colon, like "generate" / 冒号后的第一个单词，比如 "generate"
+	Value     string // Rest of the line, like "stringer -type=Kind" / 该行剩余的部分，比如 "stringer -type=Kind"
+	Raw       string // Raw comment text with the slashes / 带斜杠的原始注释文本
+}
+
+// Key returns the directive key, like "go:generate".
+// Key 返回指令的键，比如 "go:generate"。
+func (directive *Directive) Key() string {
+	return directive.Namespace + ":" + directive.Name
+}
+
+// DocComment is the doc comment of a declaration, with directives separated and the structure parsed.
+// DocComment 是声明的文档注释，其中的指令被分离出来，并且解析了注释的结构。
+type DocComment struct {
+	Text           string            // Cleaned text without slashes and directives / 去掉斜杠和指令后的文本
+	Directives     []*Directive      // Directive lines in the comment / 注释中的指令行
+	Deprecated     bool              // Whether there is a "Deprecated:" paragraph / 是否存在 "Deprecated:" 段落
+	DeprecatedNote string            // Text after "Deprecated:" / "Deprecated:" 之后的文本
+	Doc            *comment.Doc      // Parsed doc comment, with headings, lists, code blocks and links / 解析后的文档注释，包含标题、列表、代码块和链接
+	CommentGroup   *ast.CommentGroup // Source comment group, nil when there is no comment / 源注释组，没有注释时为 nil
+}
+
+// NewDocComment parses the comment group into a DocComment, the comment group can be nil.
+// NewDocComment 将注释组解析为 DocComment，注释组可以为 nil。
+func NewDocComment(commentGroup *ast.CommentGroup) *DocComment {
+	docComment := &DocComment{CommentGroup: commentGroup}
+	if commentGroup != nil {
+		docComment.Text = commentGroup.Text()
+		for _, item := range commentGroup.List {
+			if directive, ok := ParseDirective(item.Text); ok {
+				docComment.Directives = append(docComment.Directives, directive)
+			}
+		}
+	}
+	docComment.DeprecatedNote, docComment.Deprecated = findDeprecatedNote(docComment.Text)
+	docComment.Doc = new(comment.Parser).Parse(docComment.Text)
+	return docComment
+}
+
+// GetDocComment returns the doc comment of the node, the node can be *ast.File (the package clause),
+// *ast.FuncDecl, *ast.GenDecl, *ast.TypeSpec, *ast.ValueSpec or *ast.Field.
+// A spec without its own doc uses the doc of the GenDecl when the GenDecl is not parenthesized, like "type A struct{}".
+// GetDocComment 返回节点的文档注释，节点可以是 *ast.File（包声明）、*ast.FuncDecl、*ast.GenDecl、*ast.TypeSpec、*ast.ValueSpec 或 *ast.Field。
+// 当 GenDecl 没有括号时（比如 "type A struct{}"），没有自身文档的声明会使用 GenDecl 的文档。
+func GetDocComment(astFile *ast.File, node ast.Node) *DocComment {
+	switch item := node.(type) {
+	case *ast.File:
+		return NewDocComment(item.Doc)
+	case *ast.FuncDecl:
+		return NewDocComment(item.Doc)
+	case *ast.GenDecl:
+		return NewDocComment(item.Doc)
+	case *ast.TypeSpec:
+		return NewDocComment(specDoc(astFile, item, item.Doc))
+	case *ast.ValueSpec:
+		return NewDocComment(specDoc(astFile, item, item.Doc))
+	case *ast.Field:
+		return NewDocComment(item.Doc)
+	default:
+		return NewDocComment(nil)
+	}
+}
+
+// GetDocCommentByName returns the doc comment of the function, type, const or var with the name.
+// Methods are named like "Receiver.Method".
+// GetDocCommentByName 返回指定名称的函数、类型、常量或变量的文档注释。
+// 方法的名称形如 "Receiver.Method"。
+func GetDocCommentByName(astFile *ast.File, name string) (*DocComment, bool) {
+	receiverName, methodName, isMethod := strings.Cut(name, ".")
+	for _, decl := range astFile.Decls {
+		switch item := decl.(type) {
+		case *ast.FuncDecl:
+			if isMethod {
+				if typeName, _, ok := GetReceiverTypeName(item); ok && typeName == receiverName && item.Name.Name == methodName {
+					return GetDocComment(astFile, item), true
+				}
+			} else if item.Recv == nil && item.Name.Name == name {
+				return GetDocComment(astFile, item), true
+			}
+		case *ast.GenDecl:
+			if isMethod {
+				continue
+			}
+			for _, spec := range item.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.Name == name {
+						return GetDocComment(astFile, spec), true
+					}
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						if ident.Name == name {
+							return GetDocComment(astFile, spec), true
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// ParseDirective parses a directive comment line, like "//go:embed static" or "//lint:ignore SA1019 reason".
+// Following the Go convention, the line must start with "//" directly followed by "word:word" in lowercase letters and digits.
+// ParseDirective 解析指令注释行，比如 "//go:embed static" 或 "//lint:ignore SA1019 reason"。
+// 按照 Go 的约定，该行必须以 "//" 开头，并紧跟由小写字母和数字组成的 "word:word"。
+func ParseDirective(text string) (*Directive, bool) {
+	namespace, name, value, ok := utils.CutDirective(text)
+	if !ok {
+		return nil, false
+	}
+	return &Directive{
+		Namespace: namespace,
+		Name:      name,
+		Value:     value,
+		Raw:       text,
+	}, true
+}
+
+// LookupDirective returns the first directive with the key, like "go:generate".
+// LookupDirective 返回第一个具有指定键的指令，比如 "go:generate"。
+func (docComment *DocComment) LookupDirective(key string) (*Directive, bool) {
+	for _, directive := range docComment.Directives {
+		if directive.Key() == key {
+			return directive, true
+		}
+	}
+	return nil, false
+}
+
+// Headings returns the text of the headings, like "# Usage" becomes "Usage".
+// Headings 返回标题的文本，比如 "# Usage" 会变成 "Usage"。
+func (docComment *DocComment) Headings() []string {
+	var results []string
+	for _, block := range docComment.Doc.Content {
+		if heading, ok := block.(*comment.Heading); ok {
+			results = append(results, plainText(heading.Text))
+		}
+	}
+	return results
+}
+
+// Lists returns the items of each list, each item is the plain text of its paragraphs.
+// Lists 返回每个列表的条目，每个条目是其段落的纯文本。
+func (docComment *DocComment) Lists() [][]string {
+	var results [][]string
+	for _, block := range docComment.Doc.Content {
+		if list, ok := block.(*comment.List); ok {
+			var items []string
+			for _, item := range list.Items {
+				var parts []string
+				for _, content := range item.Content {
+					if paragraph, ok := content.(*comment.Paragraph); ok {
+						parts = append(parts, plainText(paragraph.Text))
+					}
+				}
+				items = append(items, strings.Join(parts, "\n"))
+			}
+			results = append(results, items)
+		}
+	}
+	return results
+}
+
+// CodeBlocks returns the text of the indented code blocks.
+// CodeBlocks 返回缩进代码块的文本。
+func (docComment *DocComment) CodeBlocks() []string {
+	var results []string
+	for _, block := range docComment.Doc.Content {
+		if code, ok := block.(*comment.Code); ok {
+			results = append(results, code.Text)
+		}
+	}
+	return results
+}
+
+// Links returns the URLs of link definitions and automatic links, in order of appearance without duplicates.
+// Links 返回链接定义和自动链接的 URL，按出现顺序排列且不重复。
+func (docComment *DocComment) Links() []string {
+	var results []string
+	seen := map[string]bool{}
+	add := func(url string) {
+		if !seen[url] {
+			seen[url] = true
+			results = append(results, url)
+		}
+	}
+	walkTexts(docComment.Doc, func(text comment.Text) {
+		if link, ok := text.(*comment.Link); ok {
+			add(link.URL)
+		}
+	})
+	for _, linkDef := range docComment.Doc.Links {
+		add(linkDef.URL)
+	}
+	return results
+}
+
+// DocLinks returns the symbols referenced with doc links, like "[io.Reader]" becomes "io.Reader".
+// DocLinks 返回文档链接引用的符号，比如 "[io.Reader]" 会变成 "io.Reader"。
+func (docComment *DocComment) DocLinks() []string {
+	var results []string
+	walkTexts(docComment.Doc, func(text comment.Text) {
+		if docLink, ok := text.(*comment.DocLink); ok {
+			var parts []string
+			if docLink.ImportPath != "" {
+				parts = append(parts, docLink.ImportPath)
+			}
+			if docLink.Recv != "" {
+				parts = append(parts, docLink.Recv)
+			}
+			if docLink.Name != "" {
+				parts = append(parts, docLink.Name)
+			}
+			results = append(results, strings.Join(parts, "."))
+		}
+	})
+	return results
+}
+
+// Markdown renders the doc comment as Markdown.
+// Markdown 将文档注释渲染为 Markdown。
+func (docComment *DocComment) Markdown() string {
+	return string(new(comment.Printer).Markdown(docComment.Doc))
+}
+
+// specDoc returns the doc of the spec, or the doc of its GenDecl when the GenDecl is not parenthesized.
+// specDoc 返回声明的文档，当 GenDecl 没有括号时返回 GenDecl 的文档。
+func specDoc(astFile *ast.File, spec ast.Spec, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc != nil || astFile == nil {
+		return doc
+	}
+	for _, decl := range astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Lparen != token.NoPos || len(genDecl.Specs) != 1 {
+			continue
+		}
+		if genDecl.Specs[0] == spec {
+			return genDecl.Doc
+		}
+	}
+	return nil
+}
+
+// findDeprecatedNote finds the paragraph starting with "Deprecated:" and returns the text after it.
+// findDeprecatedNote 查找以 "Deprecated:" 开头的段落并返回其后的文本。
+func findDeprecatedNote(text string) (string, bool) {
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if note, ok := strings.CutPrefix(strings.TrimSpace(paragraph), "Deprecated:"); ok {
+			return strings.Join(strings.Fields(note), " "), true
+		}
+	}
+	return "", false
+}
+
+// walkTexts calls the function with each inline text of the paragraphs, headings and list items.
+// walkTexts 对段落、标题和列表条目中的每个行内文本调用该函数。
+func walkTexts(doc *comment.Doc, visit func(text comment.Text)) {
+	var walkBlocks func(blocks []comment.Block)
+	walkBlocks = func(blocks []comment.Block) {
+		for _, block := range blocks {
+			switch item := block.(type) {
+			case *comment.Paragraph:
+				for _, text := range item.Text {
+					visit(text)
+				}
+			case *comment.Heading:
+				for _, text := range item.Text {
+					visit(text)
+				}
+			case *comment.List:
+				for _, listItem := range item.Items {
+					walkBlocks(listItem.Content)
+				}
+			}
+		}
+	}
+	walkBlocks(doc.Content)
+}
+
+// plainText joins the inline texts without formatting.
+// plainText 拼接行内文本，不带格式。
+func plainText(texts []comment.Text) string {
+	var sb strings.Builder
+	for _, text := range texts {
+		switch item := text.(type) {
+		case comment.Plain:
+			sb.WriteString(string(item))
+		case comment.Italic:
+			sb.WriteString(string(item))
+		case *comment.Link:
+			sb.WriteString(plainText(item.Text))
+		case *comment.DocLink:
+			sb.WriteString(plainText(item.Text))
+		}
+	}
+	return sb.String()
+}
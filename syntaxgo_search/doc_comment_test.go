@@ -0,0 +1,138 @@
+package syntaxgo_search
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const docCommentSource = `// Package demo shows doc comments.
+package demo
+
+// Kind is the kind of an account.
+//
+// # Usage
+//
+// Pick one of:
+//   - Admin
+//   - Guest
+//
+// Example:
+//
+//	kind := Admin
+//
+// See [io.Reader] and https://go.dev/doc/comment for details.
+//
+// Deprecated: use
+// Role instead.
+//
+//go:generate stringer -type=Kind
+//nolint:revive
+type Kind int
+
+const (
+	// Admin is the admin kind.
+	Admin Kind = iota
+	Guest
+)
+
+type Account struct {
+	// Name is the name.
+	//lint:ignore U1000 used by reflection
+	Name string
+}
+
+// Login logs in.
+func (a *Account) Login() {}
+
+// Open opens the account.
+func Open() {}
+`
+
+func TestGetDocCommentByName(t *testing.T) {
+	astFile := rese.P1(parser.ParseFile(token.NewFileSet(), "", docCommentSource, parser.ParseComments))
+
+	docComment, ok := GetDocCommentByName(astFile, "Kind")
+	require.True(t, ok)
+	t.Log(docComment.Text)
+	require.NotContains(t, docComment.Text, "// ")
+	require.NotContains(t, docComment.Text, "go:generate")
+
+	require.Len(t, docComment.Directives, 2)
+	directive, ok := docComment.LookupDirective("go:generate")
+	require.True(t, ok)
+	require.Equal(t, "go", directive.Namespace)
+	require.Equal(t, "generate", directive.Name)
+	require.Equal(t, "stringer -type=Kind", directive.Value)
+	directive, ok = docComment.LookupDirective("nolint:revive")
+	require.True(t, ok)
+	require.Empty(t, directive.Value)
+
+	require.True(t, docComment.Deprecated)
+	require.Equal(t, "use Role instead.", docComment.DeprecatedNote)
+
+	require.Equal(t, []string{"Usage"}, docComment.Headings())
+	require.Equal(t, [][]string{{"Admin", "Guest"}}, docComment.Lists())
+	require.Equal(t, []string{"kind := Admin\n"}, docComment.CodeBlocks())
+	require.Equal(t, []string{"https://go.dev/doc/comment"}, docComment.Links())
+	require.Equal(t, []string{"io.Reader"}, docComment.DocLinks())
+	t.Log(docComment.Markdown())
+}
+
+func TestGetDocCommentByName_Others(t *testing.T) {
+	astFile := rese.P1(parser.ParseFile(token.NewFileSet(), "", docCommentSource, parser.ParseComments))
+
+	docComment, ok := GetDocCommentByName(astFile, "Admin")
+	require.True(t, ok)
+	require.Equal(t, "Admin is the admin kind.\n", docComment.Text)
+	require.False(t, docComment.Deprecated)
+
+	docComment, ok = GetDocCommentByName(astFile, "Guest")
+	require.True(t, ok)
+	require.Nil(t, docComment.CommentGroup)
+	require.Empty(t, docComment.Text)
+
+	docComment, ok = GetDocCommentByName(astFile, "Account.Login")
+	require.True(t, ok)
+	require.Equal(t, "Login logs in.\n", docComment.Text)
+
+	docComment, ok = GetDocCommentByName(astFile, "Open")
+	require.True(t, ok)
+	require.Equal(t, "Open opens the account.\n", docComment.Text)
+
+	_, ok = GetDocCommentByName(astFile, "Login")
+	require.False(t, ok)
+}
+
+func TestGetDocComment(t *testing.T) {
+	astFile := rese.P1(parser.ParseFile(token.NewFileSet(), "", docCommentSource, parser.ParseComments))
+
+	require.Equal(t, "Package demo shows doc comments.\n", GetDocComment(astFile, astFile).Text)
+
+	structType, ok := FindStructTypeByName(astFile, "Account")
+	require.True(t, ok)
+	docComment := GetDocComment(astFile, structType.Fields.List[0])
+	require.Equal(t, "Name is the name.\n", docComment.Text)
+	directive, ok := docComment.LookupDirective("lint:ignore")
+	require.True(t, ok)
+	require.Equal(t, "U1000 used by reflection", directive.Value)
+}
+
+func TestParseDirective(t *testing.T) {
+	directive, ok := ParseDirective("//go:embed static/*")
+	require.True(t, ok)
+	require.Equal(t, "go:embed", directive.Key())
+	require.Equal(t, "static/*", directive.Value)
+
+	_, ok = ParseDirective("// go:embed static")
+	require.False(t, ok)
+	_, ok = ParseDirective("//TODO: fix it")
+	require.False(t, ok)
+	_, ok = ParseDirective("//see: https://go.dev")
+	require.False(t, ok)
+	_, ok = ParseDirective("/* go:embed */")
+	require.False(t, ok)
+}
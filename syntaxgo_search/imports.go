@@ -0,0 +1,54 @@
+package syntaxgo_search
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/syntaxgo/internal/utils"
+)
+
+// GetImportPath returns the unquoted import path of the import spec.
+// GetImportPath 返回导入声明中去掉双引号的导入路径。
+func GetImportPath(importSpec *ast.ImportSpec) string {
+	importPath, err := strconv.Unquote(importSpec.Path.Value)
+	if err != nil {
+		return strings.Trim(importSpec.Path.Value, "`\"")
+	}
+	return importPath
+}
+
+// GetImportName returns the name used to refer to the imported package in the file.
+// It is the explicit name when present, otherwise the name is guessed from the import path.
+// GetImportName 返回文件中引用该导入包时使用的名称。
+// 有显式别名时返回别名，否则根据导入路径推测包名。
+func GetImportName(importSpec *ast.ImportSpec) string {
+	if importSpec.Name != nil {
+		return importSpec.Name.Name
+	}
+	return GuessPackageName(GetImportPath(importSpec))
+}
+
+// GuessPackageName guesses the package name from the import path, such as "yaml" from "gopkg.in/yaml.v3" or "zap" from "go.uber.org/zap".
+// Version suffixes like "/v2" and the "go-" prefix are skipped, it is the same convention used by goimports.
+// GuessPackageName 根据导入路径推测包名，比如从 "gopkg.in/yaml.v3" 得到 "yaml"，从 "go.uber.org/zap" 得到 "zap"。
+// 会跳过 "/v2" 这样的版本后缀以及 "go-" 前缀，这和 goimports 使用的约定相同。
+func GuessPackageName(importPath string) string {
+	return utils.GuessPackageName(importPath)
+}
+
+// MapImportPathsByName returns a map of the names used in the file to the import paths.
+// Blank imports "_" and dot imports "." are skipped.
+// MapImportPathsByName 返回文件中使用的包名到导入路径的映射。
+// 会跳过 "_" 匿名导入和 "." 点导入。
+func MapImportPathsByName(astFile *ast.File) map[string]string {
+	importPaths := map[string]string{}
+	for _, importSpec := range astFile.Imports {
+		name := GetImportName(importSpec)
+		if name == "_" || name == "." {
+			continue
+		}
+		importPaths[name] = GetImportPath(importSpec)
+	}
+	return importPaths
+}
@@ -0,0 +1,43 @@
+package syntaxgo_search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+func TestGuessPackageName(t *testing.T) {
+	require.Equal(t, "zap", GuessPackageName("go.uber.org/zap"))
+	require.Equal(t, "yaml", GuessPackageName("gopkg.in/yaml.v3"))
+	require.Equal(t, "chi", GuessPackageName("github.com/go-chi/chi/v5"))
+	require.Equal(t, "spew", GuessPackageName("github.com/davecgh/go-spew"))
+	require.Equal(t, "sqlite3", GuessPackageName("github.com/mattn/go-sqlite3"))
+	require.Equal(t, "redis", GuessPackageName("github.com/redis/go-redis/v9"))
+	require.Equal(t, "toml", GuessPackageName("github.com/pelletier/toml-go"))
+	require.Equal(t, "rand", GuessPackageName("math/rand/v2"))
+	require.Equal(t, "fmt", GuessPackageName("fmt"))
+}
+
+func TestMapImportPathsByName(t *testing.T) {
+	const code = `package demo
+
+import (
+	"fmt"
+	_ "embed"
+	stdlog "log"
+	"gopkg.in/yaml.v3"
+)
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(code)))
+	astFile, _ := astBundle.GetBundle()
+
+	importPaths := MapImportPathsByName(astFile)
+	t.Log(importPaths)
+	require.Equal(t, map[string]string{
+		"fmt":    "fmt",
+		"stdlog": "log",
+		"yaml":   "gopkg.in/yaml.v3",
+	}, importPaths)
+}
@@ -0,0 +1,461 @@
+package syntaxgo_search
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// Pattern is a Go expression or statement list with wildcards, matched against the AST like gogrep.
+//   - $name matches any expression, statement or identifier and binds it, "$_" matches without binding.
+//   - $*name matches any number of elements in a list, like arguments, statements or results, "$*_" matches without binding.
+//   - A name used twice only matches equal nodes, like "$x == $x".
+//
+// Pattern 是带有通配符的 Go 表达式或语句列表，像 gogrep 一样与 AST 进行匹配。
+//   - $name 匹配任意表达式、语句或标识符并进行绑定，"$_" 只匹配而不绑定。
+//   - $*name 匹配列表中任意数量的元素，比如参数、语句或返回值，"$*_" 只匹配而不绑定。
+//   - 使用两次的名称只匹配相等的节点，比如 "$x == $x"。
+type Pattern struct {
+	text  string     // Pattern text / 模式文本
+	expr  ast.Expr   // Expression pattern, nil for statement patterns / 表达式模式，语句模式时为 nil
+	stmts []ast.Stmt // Statement pattern / 语句模式
+}
+
+// PatternMatch is a match of the pattern with the bindings of the wildcards.
+// PatternMatch 是模式的一次匹配，包含通配符的绑定。
+type PatternMatch struct {
+	Nodes  []ast.Node            // Matched nodes, several statements for a pattern of several statements / 匹配的节点，多条语句的模式对应多条语句
+	Values map[string]ast.Node   // Bindings of $name / $name 的绑定
+	Lists  map[string][]ast.Node // Bindings of $*name / $*name 的绑定
+}
+
+// Pos returns the position of the first matched node.
+// Pos 返回第一个匹配节点的位置。
+func (match *PatternMatch) Pos() token.Pos {
+	return match.Nodes[0].Pos()
+}
+
+// End returns the end position of the last matched node.
+// End 返回最后一个匹配节点的结束位置。
+func (match *PatternMatch) End() token.Pos {
+	return match.Nodes[len(match.Nodes)-1].End()
+}
+
+const (
+	wildcardValuePrefix = "_syntaxgo_value_" // Identifier prefix standing for $name / 代表 $name 的标识符前缀
+	wildcardListPrefix  = "_syntaxgo_list_"  // Identifier prefix standing for $*name / 代表 $*name 的标识符前缀
+)
+
+// CompilePattern compiles the pattern, like `if $err != nil { return $*_ }` or `fmt.Sprintf("%s", $x)`.
+// CompilePattern 编译模式，比如 `if $err != nil { return $*_ }` 或 `fmt.Sprintf("%s", $x)`。
+func CompilePattern(text string) (*Pattern, error) {
+	code := string(replacePlaceholders(text, func(item *placeholder) string {
+		if item.list {
+			return wildcardListPrefix + item.name
+		}
+		return wildcardValuePrefix + item.name
+	}))
+	if expr, err := parser.ParseExpr(code); err == nil {
+		return &Pattern{text: text, expr: expr}, nil
+	}
+	astFile, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\nfunc _() {\n"+code+"\n}\n", 0)
+	if err != nil {
+		return nil, erero.WithMessagef(err, "pattern %q is not an expression or statements", text)
+	}
+	stmts := astFile.Decls[0].(*ast.FuncDecl).Body.List
+	if len(stmts) == 0 {
+		return nil, erero.Errorf("pattern %q is empty", text)
+	}
+	return &Pattern{text: text, stmts: stmts}, nil
+}
+
+// String returns the pattern text.
+// String 返回模式文本。
+func (pattern *Pattern) String() string {
+	return pattern.text
+}
+
+// Match matches the pattern against the node, a statement list pattern only matches a node when it has one statement.
+// Match 将模式与节点进行匹配，语句列表模式只有在只包含一条语句时才能匹配单个节点。
+func (pattern *Pattern) Match(node ast.Node) (*PatternMatch, bool) {
+	var root ast.Node = pattern.expr
+	if root == nil {
+		if len(pattern.stmts) != 1 {
+			return nil, false
+		}
+		root = pattern.stmts[0]
+	}
+	matcher := newPatternMatcher()
+	if !matcher.match(reflect.ValueOf(root), reflect.ValueOf(node)) {
+		return nil, false
+	}
+	return matcher.result([]ast.Node{node}), true
+}
+
+// FindMatches finds the matches of the pattern in the node, like an *ast.File, in source order.
+// Matches nested in other matches are found too, statement patterns match consecutive statements in blocks.
+// FindMatches 在节点（比如 *ast.File）中查找模式的匹配，按源码顺序排列。
+// 嵌套在其它匹配中的匹配也会被找到，语句模式匹配代码块中连续的语句。
+func (pattern *Pattern) FindMatches(root ast.Node) []*PatternMatch {
+	var matches []*PatternMatch
+	ast.Inspect(root, func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+		if pattern.expr != nil || len(pattern.stmts) == 1 {
+			if match, ok := pattern.Match(node); ok {
+				matches = append(matches, match)
+			}
+			return true
+		}
+		if stmts, ok := blockStmts(node); ok {
+			matches = append(matches, pattern.matchStmts(stmts)...)
+		}
+		return true
+	})
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Pos() < matches[j].Pos()
+	})
+	return matches
+}
+
+// matchStmts matches a pattern of several statements against the consecutive statements in the list.
+// matchStmts 将多条语句的模式与列表中连续的语句进行匹配。
+func (pattern *Pattern) matchStmts(stmts []ast.Stmt) []*PatternMatch {
+	var matches []*PatternMatch
+	for start := 0; start < len(stmts); start++ {
+		for end := len(stmts); end > start; end-- {
+			matcher := newPatternMatcher()
+			if matcher.match(reflect.ValueOf(pattern.stmts), reflect.ValueOf(stmts[start:end])) {
+				nodes := make([]ast.Node, 0, end-start)
+				for _, stmt := range stmts[start:end] {
+					nodes = append(nodes, stmt)
+				}
+				matches = append(matches, matcher.result(nodes))
+				start = end - 1
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// Replace replaces the matches in the file with the template, like `fmt.Sprint($x)`, the wildcards are replaced by the source text of their bindings.
+// Lists are joined with ", ", or with newlines for statements. Matches nested in replaced matches are skipped, the result is formatted.
+// Replace 将文件中的匹配替换为模板，比如 `fmt.Sprint($x)`，通配符被替换为其绑定节点的源代码文本。
+// 列表使用 ", " 连接，语句列表使用换行连接。嵌套在已替换匹配中的匹配会被跳过，结果会被格式化。
+func (pattern *Pattern) Replace(fset *token.FileSet, astFile *ast.File, source []byte, template string) ([]byte, int, error) {
+	offsetOf := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	var buf bytes.Buffer
+	offset, count := 0, 0
+	for _, match := range pattern.FindMatches(astFile) {
+		start, end := offsetOf(match.Pos()), offsetOf(match.End())
+		if start < offset {
+			continue
+		}
+		var problem error
+		code := replacePlaceholders(template, func(item *placeholder) string {
+			if item.list {
+				nodes, ok := match.Lists[item.name]
+				if !ok {
+					problem = erero.Errorf("template placeholder $*%s is not bound by the pattern", item.name)
+				}
+				var texts []string
+				separator := ", "
+				for _, node := range nodes {
+					texts = append(texts, string(source[offsetOf(node.Pos()):offsetOf(node.End())]))
+					if _, ok := node.(ast.Stmt); ok {
+						separator = "\n"
+					}
+				}
+				return strings.Join(texts, separator)
+			}
+			node, ok := match.Values[item.name]
+			if !ok {
+				problem = erero.Errorf("template placeholder $%s is not bound by the pattern", item.name)
+				return ""
+			}
+			return string(source[offsetOf(node.Pos()):offsetOf(node.End())])
+		})
+		if problem != nil {
+			return nil, 0, erero.Wro(problem)
+		}
+		buf.Write(source[offset:start])
+		buf.Write(code)
+		offset = end
+		count++
+	}
+	if count == 0 {
+		return source, 0, nil
+	}
+	buf.Write(source[offset:])
+	newSource, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, 0, erero.WithMessagef(err, "replaced source does not parse")
+	}
+	return newSource, count, nil
+}
+
+// blockStmts returns the statement list of blocks and case clauses.
+// blockStmts 返回代码块以及 case 子句中的语句列表。
+func blockStmts(node ast.Node) ([]ast.Stmt, bool) {
+	switch item := node.(type) {
+	case *ast.BlockStmt:
+		return item.List, true
+	case *ast.CaseClause:
+		return item.Body, true
+	case *ast.CommClause:
+		return item.Body, true
+	}
+	return nil, false
+}
+
+var (
+	posType          = reflect.TypeOf(token.NoPos)
+	objectType       = reflect.TypeOf(&ast.Object{})
+	scopeType        = reflect.TypeOf(&ast.Scope{})
+	commentGroupType = reflect.TypeOf(&ast.CommentGroup{})
+	nodeType         = reflect.TypeOf((*ast.Node)(nil)).Elem()
+)
+
+// patternMatcher matches pattern nodes against nodes with reflection, positions, objects and comments are ignored.
+// patternMatcher 使用反射将模式节点与节点进行匹配，位置、对象以及注释会被忽略。
+type patternMatcher struct {
+	values map[string]ast.Node
+	lists  map[string][]ast.Node
+}
+
+func newPatternMatcher() *patternMatcher {
+	return &patternMatcher{values: map[string]ast.Node{}, lists: map[string][]ast.Node{}}
+}
+
+func (m *patternMatcher) result(nodes []ast.Node) *PatternMatch {
+	return &PatternMatch{Nodes: nodes, Values: m.values, Lists: m.lists}
+}
+
+func (m *patternMatcher) snapshot() *patternMatcher {
+	res := newPatternMatcher()
+	for name, node := range m.values {
+		res.values[name] = node
+	}
+	for name, nodes := range m.lists {
+		res.lists[name] = nodes
+	}
+	return res
+}
+
+func (m *patternMatcher) restore(saved *patternMatcher) {
+	m.values = saved.values
+	m.lists = saved.lists
+}
+
+func (m *patternMatcher) match(pattern reflect.Value, value reflect.Value) bool {
+	if pattern.Kind() == reflect.Interface {
+		if pattern.IsNil() {
+			return value.Kind() == reflect.Interface && value.IsNil() || value.Kind() == reflect.Ptr && value.IsNil()
+		}
+		pattern = pattern.Elem()
+	}
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return pattern.Kind() == reflect.Ptr && pattern.IsNil()
+		}
+		value = value.Elem()
+	}
+	if name, ok := wildcardName(pattern, wildcardValuePrefix); ok {
+		if value.Kind() != reflect.Ptr || value.IsNil() || !value.Type().Implements(nodeType) {
+			return false
+		}
+		return m.bind(name, value.Interface().(ast.Node))
+	}
+	if pattern.Type() != value.Type() {
+		return false
+	}
+	switch pattern.Kind() {
+	case reflect.Ptr:
+		if pattern.IsNil() || value.IsNil() {
+			return pattern.IsNil() == value.IsNil()
+		}
+		return m.match(pattern.Elem(), value.Elem())
+	case reflect.Struct:
+		for idx := 0; idx < pattern.NumField(); idx++ {
+			switch pattern.Type().Field(idx).Type {
+			case posType, objectType, scopeType, commentGroupType:
+				continue
+			}
+			if !m.match(pattern.Field(idx), value.Field(idx)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		return m.matchList(pattern, 0, value, 0)
+	case reflect.String:
+		return pattern.String() == value.String()
+	case reflect.Bool:
+		return pattern.Bool() == value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return pattern.Int() == value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return pattern.Uint() == value.Uint()
+	}
+	return false
+}
+
+// matchList matches the elements of the lists from the indexes, $*name elements match any number of elements.
+// matchList 从指定下标开始匹配列表的元素，$*name 元素匹配任意数量的元素。
+func (m *patternMatcher) matchList(pattern reflect.Value, pi int, value reflect.Value, vi int) bool {
+	if pi == pattern.Len() {
+		return vi == value.Len()
+	}
+	if name, ok := wildcardName(pattern.Index(pi), wildcardListPrefix); ok {
+		for end := vi; end <= value.Len(); end++ {
+			saved := m.snapshot()
+			var nodes []ast.Node
+			for idx := vi; idx < end; idx++ {
+				if node, ok := value.Index(idx).Interface().(ast.Node); ok {
+					nodes = append(nodes, node)
+				}
+			}
+			if m.bindList(name, nodes) && m.matchList(pattern, pi+1, value, end) {
+				return true
+			}
+			m.restore(saved)
+		}
+		return false
+	}
+	if vi == value.Len() {
+		return false
+	}
+	saved := m.snapshot()
+	if m.match(pattern.Index(pi), value.Index(vi)) && m.matchList(pattern, pi+1, value, vi+1) {
+		return true
+	}
+	m.restore(saved)
+	return false
+}
+
+func (m *patternMatcher) bind(name string, node ast.Node) bool {
+	if name == "_" {
+		return true
+	}
+	if bound, ok := m.values[name]; ok {
+		return newPatternMatcher().match(reflect.ValueOf(bound), reflect.ValueOf(node))
+	}
+	m.values[name] = node
+	return true
+}
+
+func (m *patternMatcher) bindList(name string, nodes []ast.Node) bool {
+	if name == "_" {
+		return true
+	}
+	if bound, ok := m.lists[name]; ok {
+		return newPatternMatcher().match(reflect.ValueOf(bound), reflect.ValueOf(nodes))
+	}
+	m.lists[name] = nodes
+	return true
+}
+
+// wildcardName returns the name of the wildcard identifier with the prefix.
+// A statement of only the wildcard, and a field of only the wildcard type, are wildcards too.
+// wildcardName 返回带有该前缀的通配符标识符的名称。
+// 只包含通配符的语句，以及只包含通配符类型的字段，也是通配符。
+func wildcardName(pattern reflect.Value, prefix string) (string, bool) {
+	if pattern.Kind() == reflect.Interface {
+		pattern = pattern.Elem()
+	}
+	if !pattern.IsValid() || pattern.Kind() != reflect.Ptr || pattern.IsNil() {
+		return "", false
+	}
+	var ident *ast.Ident
+	switch item := pattern.Interface().(type) {
+	case *ast.Ident:
+		ident = item
+	case *ast.ExprStmt:
+		ident, _ = item.X.(*ast.Ident)
+	case *ast.Field:
+		if len(item.Names) == 0 && item.Tag == nil {
+			ident, _ = item.Type.(*ast.Ident)
+		}
+	}
+	if ident == nil {
+		return "", false
+	}
+	return strings.CutPrefix(ident.Name, prefix)
+}
+
+// placeholder is a $name or $*name in the text.
+// placeholder 是文本中的 $name 或 $*name。
+type placeholder struct {
+	start int
+	end   int
+	name  string
+	list  bool
+}
+
+// replacePlaceholders replaces the $name and $*name placeholders in the text, the ones in string literals and comments are kept.
+// replacePlaceholders 替换文本中的 $name 和 $*name 占位符，字符串字面量和注释中的占位符保持不变。
+func replacePlaceholders(text string, replace func(item *placeholder) string) []byte {
+	var items []*placeholder
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(text))
+	var s scanner.Scanner
+	s.Init(file, []byte(text), func(token.Position, string) {}, 0)
+	var tokens []struct {
+		offset int
+		tok    token.Token
+		lit    string
+	}
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		tokens = append(tokens, struct {
+			offset int
+			tok    token.Token
+			lit    string
+		}{offset: file.Offset(pos), tok: tok, lit: lit})
+	}
+	for idx := 0; idx < len(tokens); idx++ {
+		if tokens[idx].tok != token.ILLEGAL || tokens[idx].lit != "$" {
+			continue
+		}
+		start, next := tokens[idx].offset, idx+1
+		item := &placeholder{start: start}
+		if next < len(tokens) && tokens[next].tok == token.MUL && tokens[next].offset == start+1 {
+			item.list = true
+			next++
+		}
+		expected := start + 1
+		if item.list {
+			expected++
+		}
+		if next < len(tokens) && tokens[next].tok == token.IDENT && tokens[next].offset == expected {
+			item.name = tokens[next].lit
+			item.end = expected + len(item.name)
+			items = append(items, item)
+			idx = next
+		}
+	}
+	var buf bytes.Buffer
+	offset := 0
+	for _, item := range items {
+		buf.WriteString(text[offset:item.start])
+		buf.WriteString(replace(item))
+		offset = item.end
+	}
+	buf.WriteString(text[offset:])
+	return buf.Bytes()
+}
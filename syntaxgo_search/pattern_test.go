@@ -0,0 +1,117 @@
+package syntaxgo_search
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+const patternSource = `package demo
+
+import (
+	"errors"
+	"fmt"
+)
+
+func Load(name string) (string, error) {
+	value, err := read(name)
+	if err != nil {
+		return "", err
+	}
+	if err != nil {
+		fmt.Println("again")
+	}
+	text := fmt.Sprintf("%s", value)
+	same := fmt.Sprintf("%s", "$x")
+	if name == name {
+		return text + same, errors.New("same")
+	}
+	return text, nil
+}
+
+func read(name string) (string, error) { return name, nil }
+`
+
+func TestCompilePattern(t *testing.T) {
+	pattern := rese.P1(CompilePattern(`fmt.Sprintf("%s", $x)`))
+	require.Equal(t, `fmt.Sprintf("%s", $x)`, pattern.String())
+
+	_, err := CompilePattern("if {")
+	require.Error(t, err)
+}
+
+func TestPattern_FindMatches(t *testing.T) {
+	astFile := rese.P1(parser.ParseFile(token.NewFileSet(), "", patternSource, 0))
+
+	pattern := rese.P1(CompilePattern(`fmt.Sprintf("%s", $x)`))
+	matches := pattern.FindMatches(astFile)
+	require.Len(t, matches, 2)
+	require.Equal(t, "value", matches[0].Values["x"].(*ast.Ident).Name)
+	require.Equal(t, `"$x"`, matches[1].Values["x"].(*ast.BasicLit).Value)
+
+	pattern = rese.P1(CompilePattern(`if $err != nil { return $*_ }`))
+	matches = pattern.FindMatches(astFile)
+	require.Len(t, matches, 1)
+	require.Equal(t, "err", matches[0].Values["err"].(*ast.Ident).Name)
+
+	pattern = rese.P1(CompilePattern(`if $err != nil { $*body }`))
+	matches = pattern.FindMatches(astFile)
+	require.Len(t, matches, 2)
+	require.Len(t, matches[1].Lists["body"], 1)
+
+	pattern = rese.P1(CompilePattern(`$x == $x`))
+	matches = pattern.FindMatches(astFile)
+	require.Len(t, matches, 1)
+	require.Equal(t, "name", matches[0].Values["x"].(*ast.Ident).Name)
+
+	pattern = rese.P1(CompilePattern(`return $*results`))
+	matches = pattern.FindMatches(astFile)
+	require.Len(t, matches, 4)
+	require.Len(t, matches[0].Lists["results"], 2)
+}
+
+func TestPattern_FindMatches_Statements(t *testing.T) {
+	astFile := rese.P1(parser.ParseFile(token.NewFileSet(), "", patternSource, 0))
+
+	pattern := rese.P1(CompilePattern("$v, $err := $call\nif $err != nil { $*_ }"))
+	matches := pattern.FindMatches(astFile)
+	require.Len(t, matches, 1)
+	require.Len(t, matches[0].Nodes, 2)
+	require.Equal(t, "value", matches[0].Values["v"].(*ast.Ident).Name)
+	require.IsType(t, &ast.CallExpr{}, matches[0].Values["call"])
+}
+
+func TestPattern_Match(t *testing.T) {
+	pattern := rese.P1(CompilePattern(`errors.New($msg)`))
+
+	match, ok := pattern.Match(rese.V1(parser.ParseExpr(`errors.New("boom")`)))
+	require.True(t, ok)
+	require.Equal(t, `"boom"`, match.Values["msg"].(*ast.BasicLit).Value)
+
+	_, ok = pattern.Match(rese.V1(parser.ParseExpr(`errors.New("boom", 1)`)))
+	require.False(t, ok)
+}
+
+func TestPattern_Replace(t *testing.T) {
+	fset := token.NewFileSet()
+	astFile := rese.P1(parser.ParseFile(fset, "", patternSource, 0))
+
+	pattern := rese.P1(CompilePattern(`fmt.Sprintf("%s", $x)`))
+	source, count := rese.V2(pattern.Replace(fset, astFile, []byte(patternSource), `fmt.Sprint($x)`))
+	t.Log(string(source))
+	require.Equal(t, 2, count)
+	require.Contains(t, string(source), "text := fmt.Sprint(value)\n")
+	require.Contains(t, string(source), "same := fmt.Sprint(\"$x\")\n")
+
+	pattern = rese.P1(CompilePattern(`if $err != nil { $*body }`))
+	source, count = rese.V2(pattern.Replace(fset, astFile, []byte(patternSource), "if $err != nil {\nlog($err)\n$*body\n}"))
+	require.Equal(t, 2, count)
+	require.Contains(t, string(source), "if err != nil {\n\t\tlog(err)\n\t\treturn \"\", err\n\t}")
+
+	_, _, err := pattern.Replace(fset, astFile, []byte(patternSource), "use($missing)")
+	require.Error(t, err)
+}
@@ -17,6 +17,8 @@ Key features include:
 - Searching for functions, types, and variables.
 - Finding specific array types and struct types.
 - Returning struct and interface types by name.
+- Finding the call sites of functions, methods and package-qualified functions.
+- Matching and replacing Go patterns with wildcards like `if $err != nil { return $*_ }`.
 
 It simplifies the process of analyzing Go source code, allowing developers to better understand and manipulate the code.
 */
@@ -28,6 +30,8 @@ It simplifies the process of analyzing Go source code, allowing developers to be
 - 查找函数、类型和变量。
 - 查找特定的数组类型、结构体类型。
 - 按名称返回结构体类型和接口类型。
+- 查找函数、方法以及带包名限定的函数的调用位置。
+- 使用带通配符的 Go 模式（比如 `if $err != nil { return $*_ }`）进行匹配和替换。
 
 简化了 Go 源代码分析的操作，使得开发者可以更好地理解和操作代码。
 */
@@ -447,7 +451,7 @@ func ExtractFunctionsByReceiverName(functions []*ast.FuncDecl, receiverName stri
 func GetFunctionReceiverNameAndType(astFunc *ast.FuncDecl, source []byte) (receiverName string, receiverType string) {
 	// Check if the function has a receiver
 	// 检查函数是否具有接收者
-	if astFunc.Recv != nil {
+	if astFunc.Recv != nil && len(astFunc.Recv.List) > 0 {
 		names := astFunc.Recv.List[0].Names
 		// If the receiver has a name, assign it
 		// 如果接收者有名称，则赋值
@@ -458,10 +462,13 @@ func GetFunctionReceiverNameAndType(astFunc *ast.FuncDecl, source []byte) (recei
 		// Check the type of the receiver
 		// 检查接收者的类型
 		switch node := nodeRecvType.(type) {
-		case *ast.Ident:
+		case *ast.Ident, *ast.IndexExpr, *ast.IndexListExpr:
 			receiverType = string(syntaxgo_astnode.GetCode(source, node))
 		case *ast.StarExpr:
 			receiverType = string(syntaxgo_astnode.GetCode(source, node.X))
+		case *ast.BadExpr:
+			// A broken receiver in a partial file with syntax errors has no type
+			// 带有语法错误的部分文件中损坏的接收者没有类型
 		default:
 			// Log and panic if the receiver type is unknown
 			// 如果接收者类型未知，则记录并触发panic
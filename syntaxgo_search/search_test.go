@@ -116,3 +116,64 @@ func TestFindFunctions(t *testing.T) {
 		return nil
 	}))
 }
+
+func TestGetFunctionReceiverNameAndType_Generic(t *testing.T) {
+	source := []byte("package demo\n\nfunc (s Stack[T]) Len() int { return 0 }\n\nfunc (p *Pair[K, V]) Key() K { return p.k }\n")
+	astFile, _ := rese.P1(syntaxgo_ast.NewAstBundleV1(source)).GetBundle()
+
+	functions := FindFunctions(astFile)
+	receiverName, receiverType := GetFunctionReceiverNameAndType(functions[0], source)
+	require.Equal(t, "s", receiverName)
+	require.Equal(t, "Stack[T]", receiverType)
+	receiverName, receiverType = GetFunctionReceiverNameAndType(functions[1], source)
+	require.Equal(t, "p", receiverName)
+	require.Equal(t, "Pair[K, V]", receiverType)
+}
+
+// TestSearchPartialFile runs the search functions on partial files with syntax errors, they must not panic.
+// TestSearchPartialFile 在带有语法错误的部分文件上运行搜索函数，这些函数不能 panic。
+func TestSearchPartialFile(t *testing.T) {
+	sources := []string{
+		"package demo\n\nfunc A(",
+		"package demo\n\nfunc (a *A) M( {\n",
+		"package demo\n\nfunc () M() {}\n", // Syntax is fine, the receiver is missing / 语法正确，缺少接收者
+		"package demo\n\nfunc (a A[) M() {}\n",
+		"package demo\n\ntype T struct {\n\tName string `json:\"name\"\n",
+		"package demo\n\ntype T\n\nvar x = \n\nconst ( A = \n",
+		"package demo\n\nimport (\n\t\"fmt\n)\n\nfunc A() { fmt.Println( }\n",
+		"func A() {}\n",
+	}
+	pattern := rese.P1(CompilePattern("fmt.Println($x)"))
+	for _, source := range sources {
+		astBundle := rese.P1(syntaxgo_ast.NewAstBundleTolerant(token.NewFileSet(), "a.go", []byte(source)))
+		astFile, fset := astBundle.GetBundle()
+
+		require.NotPanics(t, func() {
+			FindClassesAndFunctions(astFile)
+			FindArrayTypeByName(astFile, "T")
+			FindStructTypeByName(astFile, "T")
+			MapStructDeclarationsByName(astFile)
+			FindInterfaceTypes(astFile)
+			FindFunctionByName(astFile, "A")
+			FindFunctionsByReceiverName(astFile, "A", false)
+			FindFunctionByReceiverAndName(astFile, "A", "M")
+			MapImportPathsByName(astFile)
+			GetDocCommentByName(astFile, "A")
+			FindCallSites(fset, astFile, []byte(source), NewPackageFuncTarget("fmt", "Println"))
+			FindCallSites(fset, astFile, []byte(source), NewMethodTarget("A", "M"))
+			pattern.FindMatches(astFile)
+			for _, function := range FindFunctions(astFile) {
+				ExtractFunctionDefinitionCode([]byte(source), function)
+				GetFunctionReceiverNameAndType(function, []byte(source))
+				GetReceiverTypeName(function)
+				GetDocComment(astFile, function)
+			}
+		}, source)
+	}
+
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleTolerant(token.NewFileSet(), "a.go", []byte(sources[1])))
+	astFile, _ := astBundle.GetBundle()
+	function, ok := FindFunctionByReceiverAndName(astFile, "A", "M")
+	require.True(t, ok)
+	require.Equal(t, "func (a *A) M( {\n", ExtractFunctionDefinitionCode([]byte(sources[1]), function))
+}
@@ -2,11 +2,18 @@ package syntaxgo_search
 
 import (
 	"go/ast"
+
+	"github.com/yyle88/syntaxgo/internal/utils"
 )
 
 // ExtractFunctionDefinitionCode extracts the code definition of the specified function from the source byte slice.
+// A function without body, like in a partial file with syntax errors, gives the code up to its end.
 // ExtractFunctionDefinitionCode 从源字节切片中提取指定函数的代码定义。
+// 没有函数体的函数（比如在带有语法错误的部分文件中）给出直到其结尾的代码。
 func ExtractFunctionDefinitionCode(source []byte, funcDecl *ast.FuncDecl) string {
+	if funcDecl.Body == nil {
+		return string(source[funcDecl.Pos()-1 : min(int(funcDecl.End()-1), len(source))])
+	}
 	// Return the function's code definition as a string.
 	// 返回函数的代码定义作为字符串。
 	return string(source[funcDecl.Pos()-1 : funcDecl.Body.Lbrace-1])
@@ -42,3 +49,11 @@ func IsFunctionReceiverName(funcDecl *ast.FuncDecl, receiverName string) bool {
 	// 如果未找到匹配项，返回false。
 	return false
 }
+
+// GetReceiverTypeName returns the type name of the function receiver and whether it is a pointer receiver.
+// Generic receivers like (s *Stack[T]) are supported, the result is "Stack" in that case.
+// GetReceiverTypeName 返回函数接收者的类型名称以及是否为指针接收者。
+// 支持像 (s *Stack[T]) 这样的泛型接收者，这时返回的结果是 "Stack"。
+func GetReceiverTypeName(funcDecl *ast.FuncDecl) (typeName string, isPointer bool, ok bool) {
+	return utils.GetReceiverTypeName(funcDecl)
+}
@@ -0,0 +1,40 @@
+package syntaxgo_search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+)
+
+func TestGetReceiverTypeName(t *testing.T) {
+	const code = `package demo
+
+type Stack[T any] struct{ items []T }
+
+func (s *Stack[T]) Push(item T) { s.items = append(s.items, item) }
+
+func (s Stack[T]) Size() int { return len(s.items) }
+
+func Hello() {}
+`
+	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1([]byte(code)))
+	astFile, _ := astBundle.GetBundle()
+
+	functions := FindFunctions(astFile)
+	require.Len(t, functions, 3)
+
+	typeName, isPointer, ok := GetReceiverTypeName(functions[0])
+	require.True(t, ok)
+	require.Equal(t, "Stack", typeName)
+	require.True(t, isPointer)
+
+	typeName, isPointer, ok = GetReceiverTypeName(functions[1])
+	require.True(t, ok)
+	require.Equal(t, "Stack", typeName)
+	require.False(t, isPointer)
+
+	_, _, ok = GetReceiverTypeName(functions[2])
+	require.False(t, ok)
+}